@@ -0,0 +1,71 @@
+package bridge
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sessionInfo is one session in the GET /sessions response.
+type sessionInfo struct {
+	ChatID    string    `json:"chat_id"`
+	ThreadID  string    `json:"thread_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// registerAdminAPI adds the admin endpoints to mux when
+// Config.AdminAPIToken is set:
+//
+//	GET    /sessions          lists stored sessions
+//	DELETE /sessions/{chatID} clears a chat's context, like /clear
+//	GET    /metrics           serves the stats in the Prometheus text format
+func (b *Bridge) registerAdminAPI(mux *http.ServeMux) {
+	if b.config.AdminAPIToken == "" {
+		return
+	}
+	mux.HandleFunc("GET /sessions", b.requireAdminToken(func(w http.ResponseWriter, r *http.Request) {
+		entries, err := b.sessionStore.ListAll()
+		if err != nil {
+			writeWebhookJSON(w, http.StatusInternalServerError, webhookResponse{Error: err.Error()})
+			return
+		}
+		sessions := make([]sessionInfo, 0, len(entries))
+		for _, e := range entries {
+			sessions = append(sessions, sessionInfo{
+				ChatID:    e.ChatID,
+				ThreadID:  e.ThreadID,
+				CreatedAt: e.CreatedAt,
+				UpdatedAt: e.UpdatedAt,
+			})
+		}
+		writeWebhookJSON(w, http.StatusOK, sessions)
+	}))
+	mux.HandleFunc("DELETE /sessions/{chatID}", b.requireAdminToken(func(w http.ResponseWriter, r *http.Request) {
+		chatID := strings.TrimSpace(r.PathValue("chatID"))
+		if chatID == "" {
+			writeWebhookJSON(w, http.StatusBadRequest, webhookResponse{Error: "chat id is required"})
+			return
+		}
+		fmt.Printf("[Bridge] Admin API cleared session for %s\n", chatID)
+		b.clearChatContext(chatID)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	mux.HandleFunc("GET /metrics", b.requireAdminToken(b.metricsHandler))
+}
+
+// requireAdminToken rejects requests without "Authorization: Bearer
+// <Config.AdminAPIToken>".
+func (b *Bridge) requireAdminToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token := b.config.AdminAPIToken
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			writeWebhookJSON(w, http.StatusUnauthorized, webhookResponse{Error: "unauthorized"})
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,76 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAPI_RequiresToken(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.AdminAPIToken = "t0ken"
+
+	for _, auth := range []string{"", "Bearer wrong", "t0ken"} {
+		req := httptest.NewRequest(http.MethodGet, "/sessions", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		b.webhookHandler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401 for %q, got %d", auth, rec.Code)
+		}
+	}
+}
+
+func TestAdminAPI_DisabledWithoutToken(t *testing.T) {
+	b, _ := newTestBridge(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/sessions", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	b.webhookHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when the admin API is off, got %d", rec.Code)
+	}
+}
+
+func TestAdminAPI_ListAndDeleteSessions(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.AdminAPIToken = "t0ken"
+	if _, err := b.sessionStore.Create("c1", "thread-1"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	b.getChatState("c1").ThreadID = "thread-1"
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer t0ken")
+		rec := httptest.NewRecorder()
+		b.webhookHandler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/sessions")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var sessions []sessionInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body, err)
+	}
+	if len(sessions) != 1 || sessions[0].ChatID != "c1" || sessions[0].ThreadID != "thread-1" {
+		t.Fatalf("unexpected sessions: %+v", sessions)
+	}
+
+	if rec := do(http.MethodDelete, "/sessions/c1"); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body)
+	}
+	if entry, _ := b.sessionStore.GetByChatID("c1"); entry != nil {
+		t.Fatalf("expected the session to be cleared, got %+v", entry)
+	}
+	if got := b.getChatState("c1").ThreadID; got != "" {
+		t.Fatalf("expected the chat's thread to be cleared, got %q", got)
+	}
+}
@@ -0,0 +1,349 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+const (
+	// ApprovalPolicyAuto accepts every approval request (default).
+	ApprovalPolicyAuto = "auto"
+	// ApprovalPolicyAsk posts each request to the chat and waits for a reaction.
+	ApprovalPolicyAsk = "ask"
+	// ApprovalPolicyReadOnly declines every command and file change request.
+	ApprovalPolicyReadOnly = "readonly"
+)
+
+// approvalPolicies are the values /approvals accepts.
+var approvalPolicies = map[string]bool{
+	ApprovalPolicyAuto:     true,
+	ApprovalPolicyAsk:      true,
+	ApprovalPolicyReadOnly: true,
+}
+
+const (
+	approveEmoji           = "CheckMark"
+	declineEmoji           = "CrossMark"
+	defaultApprovalTimeout = 2 * time.Minute
+)
+
+// pendingApproval is a prompt waiting for a ✅/❌ reaction. Only requester
+// (the open_id of whoever triggered it) or an admin may answer it.
+type pendingApproval struct {
+	requester string
+	decision  chan string
+}
+
+// newCodexClient creates a codex client for workingDir wired to the bridge's
+// approval handling.
+func (b *Bridge) newCodexClient(workingDir string) *codex.Client {
+	client := codex.NewClient(workingDir, b.config.CodexModel)
+	client.SetDebug(b.config.Debug)
+	client.SetInitTimeout(b.config.CodexInitTimeout)
+	client.SetDisableNetwork(b.config.DisableNetwork)
+	client.SetKeepAlive(b.config.CodexKeepAliveInterval)
+	client.SetConfigOverrides(b.config.CodexConfigOverrides)
+	client.SetApprovalHandler(func(req codex.ApprovalRequest) bool {
+		switch b.approvalPolicyFor(req) {
+		case ApprovalPolicyAsk:
+			go b.handleApprovalRequest(req, client.RespondToApproval)
+			return true
+		case ApprovalPolicyReadOnly:
+			if req.Method != codex.MethodCommandExecutionRequestApproval && req.Method != codex.MethodFileChangeRequestApproval {
+				return false
+			}
+			if err := client.RespondToApproval(req.ID, "decline"); err != nil {
+				fmt.Printf("[Bridge] Failed to decline approval %d: %v\n", req.ID, err)
+			}
+			return true
+		}
+		return false
+	})
+	return client
+}
+
+// approvalPolicyFor returns the policy for the chat that owns req's thread:
+// the one set with /approvals, else the bridge-wide policy.
+func (b *Bridge) approvalPolicyFor(req codex.ApprovalRequest) string {
+	var params struct {
+		ThreadID string `json:"threadId"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err == nil && params.ThreadID != "" {
+		if chatID := b.findChatByThread(params.ThreadID); chatID != "" {
+			state := b.getChatState(chatID)
+			state.mu.Lock()
+			policy := state.ApprovalPolicy
+			state.mu.Unlock()
+			if policy != "" {
+				return policy
+			}
+		}
+	}
+	return b.globalApprovalPolicy()
+}
+
+// globalApprovalPolicy returns the bridge-wide policy: the one set with
+// /approvals global, else Config.ApprovalPolicy, else auto.
+func (b *Bridge) globalApprovalPolicy() string {
+	b.approvalsMu.Lock()
+	policy := b.approvalPolicy
+	b.approvalsMu.Unlock()
+	if policy == "" {
+		policy = b.config.ApprovalPolicy
+	}
+	if !approvalPolicies[policy] {
+		return ApprovalPolicyAuto
+	}
+	return policy
+}
+
+// loadChatApprovalPolicies restores the policies chats chose with
+// /approvals before a restart.
+func (b *Bridge) loadChatApprovalPolicies() {
+	policies, err := b.sessionStore.ChatApprovalPolicies()
+	if err != nil {
+		fmt.Printf("[Bridge] Failed to load chat approval policies: %v\n", err)
+		return
+	}
+	for chatID, policy := range policies {
+		if !approvalPolicies[policy] {
+			continue
+		}
+		state := b.getChatState(chatID)
+		state.mu.Lock()
+		state.ApprovalPolicy = policy
+		state.mu.Unlock()
+	}
+}
+
+// setApprovalPolicy handles /approvals. An empty arg reports the chat's
+// active policy; "<policy>" sets it for this chat and "global <policy>" for
+// every chat without its own. Changes are admin-only.
+func (b *Bridge) setApprovalPolicy(msg *feishu.Message, arg string) string {
+	msgs := b.chatMessages(msg.ChatID)
+	state := b.getChatState(msg.ChatID)
+	fields := strings.Fields(arg)
+	if len(fields) == 0 {
+		state.mu.Lock()
+		policy := state.ApprovalPolicy
+		state.mu.Unlock()
+		if policy == "" {
+			policy = b.globalApprovalPolicy()
+		}
+		return fmt.Sprintf(msgs.ApprovalsCurrent, policy, b.withPrefix("/approvals"))
+	}
+
+	global := fields[0] == "global"
+	if global {
+		fields = fields[1:]
+	}
+	if len(fields) != 1 || !approvalPolicies[fields[0]] {
+		return fmt.Sprintf(msgs.ApprovalsUsage, b.withPrefix("/approvals"))
+	}
+	if !b.isAdmin(msg) {
+		return msgs.AdminOnly
+	}
+	policy := fields[0]
+	if global {
+		b.approvalsMu.Lock()
+		b.approvalPolicy = policy
+		b.approvalsMu.Unlock()
+		return fmt.Sprintf(msgs.ApprovalsGlobalSet, policy)
+	}
+	if err := b.sessionStore.SetApprovalPolicy(msg.ChatID, policy); err != nil {
+		fmt.Printf("[Bridge] Failed to persist approval policy for %s: %v\n", msg.ChatID, err)
+	}
+	state.mu.Lock()
+	state.ApprovalPolicy = policy
+	state.mu.Unlock()
+	return fmt.Sprintf(msgs.ApprovalsSet, policy)
+}
+
+// handleApprovalRequest posts the request to the chat that owns the thread,
+// adds ✅/❌ reactions and waits for the user's choice. Anything that prevents
+// asking (unknown chat, send failure, timeout) declines.
+func (b *Bridge) handleApprovalRequest(req codex.ApprovalRequest, respond func(requestID int64, decision string) error) {
+	var threadID string
+	var format func(msgs *Messages) string
+	switch req.Method {
+	case codex.MethodCommandExecutionRequestApproval:
+		var params codex.CommandExecutionApprovalParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			fmt.Printf("[Bridge] Failed to parse command approval: %v\n", err)
+			_ = respond(req.ID, "decline")
+			return
+		}
+		threadID = params.ThreadID
+		format = func(msgs *Messages) string {
+			prompt := fmt.Sprintf(msgs.ApprovalCommand, params.Command)
+			if params.Cwd != "" {
+				prompt += "\n" + fmt.Sprintf(msgs.ApprovalCwd, params.Cwd)
+			}
+			return prompt
+		}
+	case codex.MethodFileChangeRequestApproval:
+		var params codex.FileChangeApprovalParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			fmt.Printf("[Bridge] Failed to parse file change approval: %v\n", err)
+			_ = respond(req.ID, "decline")
+			return
+		}
+		threadID = params.ThreadID
+		paths := make([]string, 0, len(params.Changes))
+		for _, ch := range params.Changes {
+			paths = append(paths, ch.Path)
+		}
+		format = func(msgs *Messages) string {
+			return fmt.Sprintf(msgs.ApprovalFileChange, strings.Join(paths, "\n"))
+		}
+	default:
+		// Unknown request types keep the auto-accept behavior.
+		_ = respond(req.ID, "accept")
+		return
+	}
+
+	chatID := b.findChatByThread(threadID)
+	if chatID == "" {
+		fmt.Printf("[Bridge] Approval request for unknown thread %s, declining\n", threadID)
+		_ = respond(req.ID, "decline")
+		return
+	}
+	msgs := b.chatMessages(chatID)
+	prompt := format(msgs) + "\n\n" + msgs.ApprovalFooter
+
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	msgID := state.MsgID
+	requester := state.SenderID
+	replyInThread := state.ChatType == "group"
+	state.mu.Unlock()
+
+	promptID, err := b.postForConfirmation(chatID, msgID, prompt, replyInThread)
+	if err != nil {
+		fmt.Printf("[Bridge] Failed to post approval request: %v\n", err)
+		_ = respond(req.ID, "decline")
+		return
+	}
+
+	decision := "decline"
+	var note string
+	switch b.awaitReaction(promptID, requester) {
+	case "accept":
+		decision = "accept"
+		note = msgs.ApprovalAccepted
+	case "decline":
+		note = msgs.ApprovalDeclined
+	case "timeout":
+		note = msgs.ApprovalTimedOut
+	}
+
+	if err := respond(req.ID, decision); err != nil {
+		fmt.Printf("[Bridge] Failed to respond to approval %d: %v\n", req.ID, err)
+	}
+	if note != "" {
+		if err := b.feishuClient.ReplyText(promptID, note, replyInThread); err != nil {
+			_ = b.feishuClient.SendText(chatID, note)
+		}
+	}
+}
+
+// postForConfirmation posts prompt (as a reply to msgID when set) and adds the
+// ✅/❌ reactions the user picks from. It returns the prompt's message ID.
+func (b *Bridge) postForConfirmation(chatID, msgID, prompt string, replyInThread bool) (string, error) {
+	var promptID string
+	var err error
+	if canReplyTo(msgID) {
+		promptID, err = b.feishuClient.ReplyTextWithID(msgID, prompt, replyInThread)
+	}
+	if !canReplyTo(msgID) || err != nil {
+		promptID, err = b.feishuClient.SendTextWithID(chatID, prompt)
+	}
+	if err != nil {
+		return "", err
+	}
+	if promptID == "" {
+		return "", fmt.Errorf("no message id returned")
+	}
+	_, _ = b.feishuClient.AddReaction(promptID, approveEmoji)
+	_, _ = b.feishuClient.AddReaction(promptID, declineEmoji)
+	return promptID, nil
+}
+
+// awaitReaction waits for a ✅/❌ reaction on promptID from requester or an
+// admin. It returns "accept", "decline", "timeout", or "" if the bridge is
+// shutting down.
+func (b *Bridge) awaitReaction(promptID, requester string) string {
+	pa := &pendingApproval{requester: requester, decision: make(chan string, 1)}
+	b.approvalsMu.Lock()
+	if b.approvals == nil {
+		b.approvals = make(map[string]*pendingApproval)
+	}
+	b.approvals[promptID] = pa
+	b.approvalsMu.Unlock()
+	defer func() {
+		b.approvalsMu.Lock()
+		delete(b.approvals, promptID)
+		b.approvalsMu.Unlock()
+	}()
+
+	timeout := b.config.ApprovalTimeout
+	if timeout <= 0 {
+		timeout = defaultApprovalTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var ctxDone <-chan struct{}
+	if b.ctx != nil {
+		ctxDone = b.ctx.Done()
+	}
+
+	select {
+	case decision := <-pa.decision:
+		return decision
+	case <-timer.C:
+		return "timeout"
+	case <-ctxDone:
+		return ""
+	}
+}
+
+// handleFeishuReaction maps ✅/❌ reactions on a pending approval or
+// confirmation prompt to a decision. Reactions added by apps (including the
+// bot itself) and by users other than the requester or an admin are ignored.
+func (b *Bridge) handleFeishuReaction(ev *feishu.ReactionEvent) {
+	if ev == nil || ev.OperatorType == "app" {
+		return
+	}
+
+	var decision string
+	switch ev.EmojiType {
+	case approveEmoji:
+		decision = "accept"
+	case declineEmoji:
+		decision = "decline"
+	default:
+		return
+	}
+
+	b.approvalsMu.Lock()
+	pa := b.approvals[ev.MsgID]
+	b.approvalsMu.Unlock()
+	if pa == nil {
+		return
+	}
+	if ev.OperatorID == "" || (ev.OperatorID != pa.requester && !b.isAdminID(ev.OperatorID)) {
+		b.debugf("Ignoring reaction on %s by %s: not the requester or an admin", ev.MsgID, ev.OperatorID)
+		return
+	}
+
+	select {
+	case pa.decision <- decision:
+	default:
+	}
+}
@@ -0,0 +1,200 @@
+package bridge
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// askApprovals is a newTestBridge option for ApprovalPolicyAsk with the given
+// timeout and a chat c1 running thread t1.
+func askApprovals(timeout time.Duration) func(*Bridge) {
+	return func(b *Bridge) {
+		b.config.ApprovalPolicy = ApprovalPolicyAsk
+		b.config.ApprovalTimeout = timeout
+		state := b.getChatState("c1")
+		state.ThreadID = "t1"
+		state.MsgID = "m1"
+		state.SenderID = "ou_user"
+	}
+}
+
+func commandApprovalRequest(t *testing.T) codex.ApprovalRequest {
+	t.Helper()
+	params, err := json.Marshal(codex.CommandExecutionApprovalParams{ThreadID: "t1", Command: "rm -rf build"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return codex.ApprovalRequest{ID: 42, Method: codex.MethodCommandExecutionRequestApproval, Params: params}
+}
+
+// runApproval runs handleApprovalRequest in the background and returns a
+// function that waits for it and yields the decision sent back to codex.
+func runApproval(b *Bridge, req codex.ApprovalRequest) func() string {
+	var wg sync.WaitGroup
+	var decision string
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b.handleApprovalRequest(req, func(id int64, d string) error {
+			decision = d
+			return nil
+		})
+	}()
+	return func() string {
+		wg.Wait()
+		return decision
+	}
+}
+
+func waitForPendingApproval(t *testing.T, b *Bridge) string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		b.approvalsMu.Lock()
+		for id := range b.approvals {
+			b.approvalsMu.Unlock()
+			return id
+		}
+		b.approvalsMu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("approval prompt was not registered")
+	return ""
+}
+
+func TestApprovalRequest_ApprovedByReaction(t *testing.T) {
+	b, m := newTestBridge(t, askApprovals(time.Minute))
+	wait := runApproval(b, commandApprovalRequest(t))
+
+	promptID := waitForPendingApproval(t, b)
+
+	// The bot's own reactions and other members' must not count as a decision.
+	b.handleFeishuReaction(&feishu.ReactionEvent{MsgID: promptID, EmojiType: approveEmoji, OperatorType: "app"})
+	b.handleFeishuReaction(&feishu.ReactionEvent{MsgID: promptID, EmojiType: approveEmoji, OperatorType: "user", OperatorID: "ou_other"})
+	b.approvalsMu.Lock()
+	pending := len(b.approvals[promptID].decision)
+	b.approvalsMu.Unlock()
+	if pending != 0 {
+		t.Fatal("expected another member's reaction to be ignored")
+	}
+	b.handleFeishuReaction(&feishu.ReactionEvent{MsgID: promptID, EmojiType: approveEmoji, OperatorType: "user", OperatorID: "ou_user"})
+
+	if got := wait(); got != "accept" {
+		t.Fatalf("expected accept, got %q", got)
+	}
+	if len(m.SentMessages) != 2 || !strings.Contains(m.SentMessages[0].Text, "rm -rf build") {
+		t.Fatalf("unexpected messages: %+v", m.SentMessages)
+	}
+	if !strings.Contains(m.SentMessages[1].Text, "已批准") {
+		t.Fatalf("expected approval note, got %q", m.SentMessages[1].Text)
+	}
+	if len(m.Reactions) != 2 {
+		t.Fatalf("expected ✅/❌ reactions on the prompt, got %+v", m.Reactions)
+	}
+}
+
+func TestApprovalRequest_AdminMayDecide(t *testing.T) {
+	b, _ := newTestBridge(t, askApprovals(time.Minute), withAdmin)
+	wait := runApproval(b, commandApprovalRequest(t))
+
+	promptID := waitForPendingApproval(t, b)
+	b.handleFeishuReaction(&feishu.ReactionEvent{MsgID: promptID, EmojiType: declineEmoji, OperatorType: "user", OperatorID: "ou_admin"})
+
+	if got := wait(); got != "decline" {
+		t.Fatalf("expected the admin's decline, got %q", got)
+	}
+}
+
+func TestApprovalRequest_UsesChatLanguage(t *testing.T) {
+	b, m := newTestBridge(t, askApprovals(20*time.Millisecond))
+	b.setLanguage("c1", "en")
+
+	if got := runApproval(b, commandApprovalRequest(t))(); got != "decline" {
+		t.Fatalf("expected decline on timeout, got %q", got)
+	}
+	want := "🔐 Codex wants to run a command:\nrm -rf build\n\n" + englishMessages.ApprovalFooter
+	if len(m.SentMessages) != 2 || m.SentMessages[0].Text != want || m.SentMessages[1].Text != englishMessages.ApprovalTimedOut {
+		t.Fatalf("expected English prompt and note, got %+v", m.SentMessages)
+	}
+}
+
+func TestApprovalRequest_DeclinesOnTimeout(t *testing.T) {
+	b, m := newTestBridge(t, askApprovals(20*time.Millisecond))
+
+	if got := runApproval(b, commandApprovalRequest(t))(); got != "decline" {
+		t.Fatalf("expected decline on timeout, got %q", got)
+	}
+	if last := m.SentMessages[len(m.SentMessages)-1].Text; !strings.Contains(last, "超时") {
+		t.Fatalf("expected timeout note, got %q", last)
+	}
+	if len(b.approvals) != 0 {
+		t.Fatalf("pending approval not cleaned up: %+v", b.approvals)
+	}
+}
+
+func TestApprovalPolicyFor_ChatOverridesGlobal(t *testing.T) {
+	b, _ := newTestBridge(t, askApprovals(time.Second))
+	req := commandApprovalRequest(t)
+
+	if got := b.approvalPolicyFor(req); got != ApprovalPolicyAsk {
+		t.Fatalf("expected config policy ask, got %q", got)
+	}
+	b.getChatState("c1").ApprovalPolicy = ApprovalPolicyReadOnly
+	if got := b.approvalPolicyFor(req); got != ApprovalPolicyReadOnly {
+		t.Fatalf("expected chat policy readonly, got %q", got)
+	}
+
+	other, err := json.Marshal(codex.CommandExecutionApprovalParams{ThreadID: "t-other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.approvalPolicy = ApprovalPolicyAuto
+	if got := b.approvalPolicyFor(codex.ApprovalRequest{Method: codex.MethodCommandExecutionRequestApproval, Params: other}); got != ApprovalPolicyAuto {
+		t.Fatalf("expected runtime global policy auto, got %q", got)
+	}
+}
+
+func TestApprovalsCommand(t *testing.T) {
+	b, m := newTestBridge(t, askApprovals(time.Second), withAdmin)
+	admin := &feishu.Sender{SenderID: "ou_admin"}
+
+	send := func(content string, sender *feishu.Sender) string {
+		b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: content, Sender: sender})
+		return m.SentMessages[len(m.SentMessages)-1].Text
+	}
+
+	if got := send("/approvals", nil); !strings.HasPrefix(got, "当前审批策略：ask") {
+		t.Fatalf("unexpected /approvals reply: %q", got)
+	}
+	if got := send("/approvals readonly", &feishu.Sender{SenderID: "ou_user"}); got != defaultMessages.AdminOnly {
+		t.Fatalf("expected non-admin to be refused, got %q", got)
+	}
+	if got := send("/approvals sometimes", admin); got != "⚠️ 用法：/approvals [global] <auto|ask|readonly>" {
+		t.Fatalf("expected usage for unknown policy, got %q", got)
+	}
+	if got := send("/approvals ReadOnly", admin); got != "✅ 本会话的审批策略已设为 readonly" {
+		t.Fatalf("unexpected set reply: %q", got)
+	}
+	if got := send("/approvals global auto", admin); !strings.HasPrefix(got, "✅ 默认审批策略已设为 auto") {
+		t.Fatalf("unexpected global set reply: %q", got)
+	}
+	if got := b.approvalPolicyFor(commandApprovalRequest(t)); got != ApprovalPolicyReadOnly {
+		t.Fatalf("expected chat policy to win over global, got %q", got)
+	}
+	if got := b.globalApprovalPolicy(); got != ApprovalPolicyAuto {
+		t.Fatalf("expected global policy auto, got %q", got)
+	}
+
+	// The chat's policy survives a restart.
+	restarted, _ := newTestBridge(t, restartOf(b))
+	restarted.loadChatApprovalPolicies()
+	if got := restarted.getChatState("c1").ApprovalPolicy; got != ApprovalPolicyReadOnly {
+		t.Fatalf("expected readonly after a restart, got %q", got)
+	}
+}
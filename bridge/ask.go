@@ -0,0 +1,184 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// sideTurn is an /ask question running on its own throwaway thread, next to
+// (and independent of) the chat's main thread.
+type sideTurn struct {
+	chatID        string
+	threadID      string
+	msgID         string
+	replyInThread bool
+	reactionID    string
+	buffer        strings.Builder
+}
+
+// handleAskCommand starts question on a fresh thread that is never stored as
+// the chat's session, so it runs concurrently with the main queue without
+// touching its context. The answer is sent when the turn completes. Only one
+// /ask per chat runs at a time.
+func (b *Bridge) handleAskCommand(msg *feishu.Message, question string) {
+	replyInThread := msg.ChatType == "group"
+	reply := func(text string) {
+		if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+			_ = b.feishuClient.SendText(msg.ChatID, text)
+		}
+	}
+
+	msgs := b.chatMessages(msg.ChatID)
+	if !b.config.ParallelAsk {
+		reply(msgs.AskDisabled)
+		return
+	}
+	if question == "" {
+		reply(fmt.Sprintf(msgs.AskUsage, b.withPrefix("/ask")))
+		return
+	}
+
+	st := &sideTurn{
+		chatID:        msg.ChatID,
+		msgID:         msg.MsgID,
+		replyInThread: replyInThread,
+	}
+
+	b.sideTurnsMu.Lock()
+	if _, busy := b.sideTurns[msg.ChatID]; busy {
+		b.sideTurnsMu.Unlock()
+		reply(fmt.Sprintf(msgs.AskBusy, b.withPrefix("/ask")))
+		return
+	}
+	if b.sideTurns == nil {
+		b.sideTurns = make(map[string]*sideTurn)
+	}
+	b.sideTurns[msg.ChatID] = st
+	b.sideTurnsMu.Unlock()
+
+	st.reactionID = b.addProcessingReaction(msg.MsgID)
+
+	threadID, err := b.codexClient.ThreadStart(b.ctx, b.threadStartParams(msg.ChatID))
+	if err != nil {
+		b.releaseSideTurn(st)
+		b.clearSideTurnReaction(st)
+		reply(b.codexFailureText(msg.ChatID, msgs.CreateThreadFailed, err))
+		return
+	}
+	b.sideTurnsMu.Lock()
+	aborted := b.sideTurns[msg.ChatID] != st
+	st.threadID = threadID
+	b.sideTurnsMu.Unlock()
+	if aborted {
+		b.clearSideTurnReaction(st)
+		return
+	}
+
+	b.activeMu.Lock()
+	b.activeThreads[threadID] = struct{}{}
+	b.activeMu.Unlock()
+
+	if _, err := b.codexClient.TurnStart(b.ctx, threadID, b.withProjectPrompt(question), nil, b.turnOptions(msg.ChatID)); err != nil {
+		b.releaseSideTurn(st)
+		b.activeMu.Lock()
+		delete(b.activeThreads, threadID)
+		b.activeMu.Unlock()
+		b.clearSideTurnReaction(st)
+		reply(b.codexFailureText(msg.ChatID, msgs.SendRequestFailed, err))
+		return
+	}
+	b.stats.turnsStarted.Add(1)
+	fmt.Printf("[Bridge] Started /ask turn in thread %s for chat %s\n", threadID, msg.ChatID)
+}
+
+// sideTurnByThreadLocked returns the /ask turn running on threadID, if any.
+// Callers must hold sideTurnsMu.
+func (b *Bridge) sideTurnByThreadLocked(threadID string) *sideTurn {
+	if threadID == "" {
+		return nil
+	}
+	for _, st := range b.sideTurns {
+		if st.threadID == threadID {
+			return st
+		}
+	}
+	return nil
+}
+
+// appendSideDelta buffers delta if threadID belongs to an /ask turn.
+func (b *Bridge) appendSideDelta(threadID, delta string) bool {
+	b.sideTurnsMu.Lock()
+	defer b.sideTurnsMu.Unlock()
+	st := b.sideTurnByThreadLocked(threadID)
+	if st == nil {
+		return false
+	}
+	st.buffer.WriteString(delta)
+	return true
+}
+
+// takeSideTurnByThread removes and returns the /ask turn running on
+// threadID, if any.
+func (b *Bridge) takeSideTurnByThread(threadID string) *sideTurn {
+	b.sideTurnsMu.Lock()
+	defer b.sideTurnsMu.Unlock()
+	st := b.sideTurnByThreadLocked(threadID)
+	if st != nil {
+		delete(b.sideTurns, st.chatID)
+	}
+	return st
+}
+
+// releaseSideTurn frees st's slot unless it was already taken or aborted.
+func (b *Bridge) releaseSideTurn(st *sideTurn) {
+	b.sideTurnsMu.Lock()
+	defer b.sideTurnsMu.Unlock()
+	if b.sideTurns[st.chatID] == st {
+		delete(b.sideTurns, st.chatID)
+	}
+}
+
+// finishSideTurn replies with the /ask answer.
+func (b *Bridge) finishSideTurn(st *sideTurn, params codex.TurnCompletedParams) {
+	b.sideTurnsMu.Lock()
+	response := st.buffer.String()
+	b.sideTurnsMu.Unlock()
+
+	response, reaction := b.turnResponse(st.chatID, response, params)
+
+	b.clearSideTurnReaction(st)
+	_, _ = b.feishuClient.AddReaction(st.msgID, reaction)
+	if err := b.feishuClient.ReplyText(st.msgID, response, st.replyInThread); err != nil {
+		if err := b.feishuClient.SendText(st.chatID, response); err != nil {
+			fmt.Printf("[Bridge] Failed to send /ask response: %v\n", err)
+		}
+	}
+}
+
+// abortSideTurns drops every in-flight /ask turn, e.g. when the codex
+// app-server they run on is replaced.
+func (b *Bridge) abortSideTurns() {
+	var started []*sideTurn
+	b.sideTurnsMu.Lock()
+	for _, st := range b.sideTurns {
+		// Turns still creating their thread notice the abort themselves.
+		if st.threadID != "" {
+			started = append(started, st)
+		}
+	}
+	b.sideTurns = make(map[string]*sideTurn)
+	b.sideTurnsMu.Unlock()
+
+	for _, st := range started {
+		b.clearSideTurnReaction(st)
+	}
+}
+
+func (b *Bridge) clearSideTurnReaction(st *sideTurn) {
+	if st.reactionID != "" {
+		_ = b.feishuClient.RemoveReaction(st.msgID, st.reactionID)
+	}
+}
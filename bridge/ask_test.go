@@ -0,0 +1,65 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestAskCommand_RequiresParallelAsk(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{feishuClient: m}
+
+	b.handleAskCommand(&feishu.Message{ChatID: "c1", MsgID: "m1"}, "what is this?")
+
+	if len(m.SentMessages) != 1 || m.SentMessages[0].Text != "⚠️ 未开启并行提问（PARALLEL_ASK=true）" {
+		t.Fatalf("expected disabled notice, got %+v", m.SentMessages)
+	}
+}
+
+func TestAskCommand_OnePerChat(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{ParallelAsk: true},
+		feishuClient: m,
+		sideTurns:    map[string]*sideTurn{"c1": {chatID: "c1", threadID: "t2"}},
+	}
+
+	b.handleAskCommand(&feishu.Message{ChatID: "c1", MsgID: "m2"}, "again?")
+
+	if len(m.SentMessages) != 1 || m.SentMessages[0].Text != "⏳ 上一个 /ask 还在处理中，请稍后再试" {
+		t.Fatalf("expected busy notice, got %+v", m.SentMessages)
+	}
+}
+
+func TestSideTurn_DoesNotTouchMainThread(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		feishuClient:  m,
+		chatStates:    make(map[string]*ChatState),
+		activeThreads: map[string]struct{}{"t1": {}, "t2": {}},
+		sideTurns:     map[string]*sideTurn{"c1": {chatID: "c1", threadID: "t2", msgID: "ask1", reactionID: "r1"}},
+	}
+	mainState := b.getChatState("c1")
+	mainState.ThreadID = "t1"
+	mainState.MsgID = "m1"
+	mainState.Processing = true
+	mainState.Buffer.WriteString("main output")
+
+	b.handleAgentDelta(codex.AgentMessageDeltaParams{ThreadID: "t2", Delta: "side answer"})
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t2", TurnID: "turn2", Status: "completed"})
+
+	if len(m.SentMessages) != 1 || m.SentMessages[0].MsgID != "ask1" || m.SentMessages[0].Text != "side answer" {
+		t.Fatalf("expected /ask reply, got %+v", m.SentMessages)
+	}
+	if got := mainState.Buffer.String(); got != "main output" || !mainState.Processing {
+		t.Fatalf("main thread state changed: buffer=%q processing=%v", got, mainState.Processing)
+	}
+	if len(b.sideTurns) != 0 {
+		t.Fatalf("side turn not released: %+v", b.sideTurns)
+	}
+	if _, ok := b.activeThreads["t1"]; !ok {
+		t.Fatal("main thread should still be active")
+	}
+}
@@ -0,0 +1,222 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// maxAuditChars caps the text mirrored to the audit chat per reply.
+const maxAuditChars = 2000
+
+// auditQueueSize bounds the mirrors waiting to be sent; more are dropped.
+const auditQueueSize = 256
+
+// maxAuditTrackedMessages bounds the message -> chat map used to label reply
+// mirrors; the oldest entries are forgotten first.
+const maxAuditTrackedMessages = 1024
+
+// auditClient wraps a FeishuClient and mirrors every outgoing text or rich
+// text message to Config.AuditChatID. Mirroring is best-effort and happens
+// in the background: failures are logged and never affect or delay the real
+// send. Edits of an existing message (progress, live output) are not
+// mirrored.
+type auditClient struct {
+	feishu.FeishuClient
+	chatID  string
+	queue   chan string
+	pending sync.WaitGroup // mirrors queued but not yet sent
+
+	msgChatsMu sync.Mutex
+	msgChats   map[string]string // message ID -> chat ID, for labelling replies
+	msgOrder   []string
+}
+
+func newAuditClient(inner feishu.FeishuClient, auditChatID string) *auditClient {
+	a := &auditClient{
+		FeishuClient: inner,
+		chatID:       auditChatID,
+		queue:        make(chan string, auditQueueSize),
+		msgChats:     make(map[string]string),
+	}
+	go a.run()
+	return a
+}
+
+// run sends queued mirrors in order for the life of the process.
+func (a *auditClient) run() {
+	for msg := range a.queue {
+		if err := a.FeishuClient.SendText(a.chatID, msg); err != nil {
+			fmt.Printf("[Bridge] Failed to mirror reply to audit chat: %v\n", err)
+		}
+		a.pending.Done()
+	}
+}
+
+// flush waits until every queued mirror has been sent.
+func (a *auditClient) flush() {
+	a.pending.Wait()
+}
+
+// Stop sends the queued mirrors before stopping the wrapped client.
+func (a *auditClient) Stop() {
+	a.flush()
+	a.FeishuClient.Stop()
+}
+
+// mirror queues a copy of a sent message for the audit chat; target names
+// the chat or message it was sent to.
+func (a *auditClient) mirror(target, text string) {
+	if target == a.chatID {
+		return
+	}
+	msg := fmt.Sprintf("[%s]\n%s", target, truncateRunes(text, maxAuditChars))
+	a.pending.Add(1)
+	select {
+	case a.queue <- msg:
+	default:
+		a.pending.Done()
+		fmt.Printf("[Bridge] Audit queue full, dropped the mirror for %s\n", target)
+	}
+}
+
+// rememberChat records the chat of messageID so replies to it can be
+// labelled with the chat.
+func (a *auditClient) rememberChat(messageID, chatID string) {
+	if messageID == "" || chatID == "" {
+		return
+	}
+	a.msgChatsMu.Lock()
+	defer a.msgChatsMu.Unlock()
+	if _, ok := a.msgChats[messageID]; !ok {
+		a.msgOrder = append(a.msgOrder, messageID)
+	}
+	a.msgChats[messageID] = chatID
+	if over := len(a.msgOrder) - maxAuditTrackedMessages; over > 0 {
+		for _, id := range a.msgOrder[:over] {
+			delete(a.msgChats, id)
+		}
+		a.msgOrder = append([]string(nil), a.msgOrder[over:]...)
+	}
+}
+
+func (a *auditClient) chatOf(messageID string) string {
+	a.msgChatsMu.Lock()
+	defer a.msgChatsMu.Unlock()
+	return a.msgChats[messageID]
+}
+
+// replyTarget labels a reply to messageID with the chat it landed in, when
+// known.
+func (a *auditClient) replyTarget(messageID string) string {
+	chatID := a.chatOf(messageID)
+	if chatID == "" {
+		return "↩ " + messageID
+	}
+	return chatID + " ↩ " + messageID
+}
+
+func (a *auditClient) OnMessage(handler feishu.MessageHandler) {
+	a.FeishuClient.OnMessage(func(msg *feishu.Message) {
+		a.rememberChat(msg.MsgID, msg.ChatID)
+		handler(msg)
+	})
+}
+
+func (a *auditClient) SendText(chatID, text string) error {
+	err := a.FeishuClient.SendText(chatID, text)
+	if err == nil {
+		a.mirror(chatID, text)
+	}
+	return err
+}
+
+func (a *auditClient) SendTextWithID(chatID, text string) (string, error) {
+	id, err := a.FeishuClient.SendTextWithID(chatID, text)
+	if err == nil {
+		a.rememberChat(id, chatID)
+		a.mirror(chatID, text)
+	}
+	return id, err
+}
+
+func (a *auditClient) SendRichText(chatID, title string, content [][]map[string]interface{}) error {
+	err := a.FeishuClient.SendRichText(chatID, title, content)
+	if err == nil {
+		a.mirror(chatID, richTextSummary(title, content))
+	}
+	return err
+}
+
+func (a *auditClient) ReplyText(messageID, text string, replyInThread bool) error {
+	err := a.FeishuClient.ReplyText(messageID, text, replyInThread)
+	if err == nil {
+		a.mirror(a.replyTarget(messageID), text)
+	}
+	return err
+}
+
+func (a *auditClient) ReplyTextWithID(messageID, text string, replyInThread bool) (string, error) {
+	id, err := a.FeishuClient.ReplyTextWithID(messageID, text, replyInThread)
+	if err == nil {
+		a.rememberChat(id, a.chatOf(messageID))
+		a.mirror(a.replyTarget(messageID), text)
+	}
+	return id, err
+}
+
+func (a *auditClient) ReplyRichText(messageID, title string, content [][]map[string]interface{}, replyInThread bool) error {
+	err := a.FeishuClient.ReplyRichText(messageID, title, content, replyInThread)
+	if err == nil {
+		a.mirror(a.replyTarget(messageID), richTextSummary(title, content))
+	}
+	return err
+}
+
+// announceStartup posts a startup summary to Config.AuditChatID so restarts
+// are visible. Best-effort; Start runs it in the background once codex, the
+// webhook server and the Feishu connection are up.
+func (b *Bridge) announceStartup() {
+	if b.config.AuditChatID == "" {
+		return
+	}
+	msgs := b.messages()
+	text := fmt.Sprintf(msgs.StartupSummary, buildVersion(), b.config.CodexModel, b.config.WorkingDir, b.config.SessionDBPath)
+	if err := b.feishuClient.SendText(b.config.AuditChatID, text); err != nil {
+		fmt.Printf("[Bridge] Failed to post startup summary: %v\n", err)
+	}
+}
+
+// announceShutdown tells the audit chat the bridge is going down.
+func (b *Bridge) announceShutdown() {
+	if b.config.AuditChatID == "" {
+		return
+	}
+	if err := b.feishuClient.SendText(b.config.AuditChatID, b.messages().ShuttingDown); err != nil {
+		fmt.Printf("[Bridge] Failed to post shutdown notice: %v\n", err)
+	}
+}
+
+// richTextSummary flattens a post's title and text elements for the audit
+// mirror.
+func richTextSummary(title string, content [][]map[string]interface{}) string {
+	text := title
+	for _, line := range content {
+		var sb strings.Builder
+		for _, el := range line {
+			if s, ok := el["text"].(string); ok {
+				sb.WriteString(s)
+			}
+		}
+		if sb.Len() == 0 {
+			continue
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += sb.String()
+	}
+	return text
+}
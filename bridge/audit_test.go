@@ -0,0 +1,120 @@
+package bridge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestAuditClientMirrorsReplies(t *testing.T) {
+	m := &MockFeishuClient{}
+	a := newAuditClient(m, "oc_audit")
+	a.OnMessage(func(*feishu.Message) {})
+	m.OnMessageHandler(&feishu.Message{ChatID: "oc_src", MsgID: "om_1"})
+
+	if err := a.ReplyText("om_1", "hello", false); err != nil {
+		t.Fatalf("ReplyText: %v", err)
+	}
+	a.flush()
+	if err := a.SendText("oc_chat", "world"); err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+	a.flush()
+	if err := a.SendText("oc_audit", "direct"); err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+	if err := a.ReplyText("om_unknown", "hi", false); err != nil {
+		t.Fatalf("ReplyText: %v", err)
+	}
+	a.flush()
+
+	var mirrored []string
+	for _, sent := range m.SentMessages {
+		if sent.ChatID == "oc_audit" {
+			mirrored = append(mirrored, sent.Text)
+		}
+	}
+	if len(mirrored) != 4 {
+		t.Fatalf("expected 3 mirrors plus the direct send, got %q", mirrored)
+	}
+	if !strings.HasPrefix(mirrored[0], "[oc_src ↩ om_1]") || !strings.Contains(mirrored[0], "hello") {
+		t.Errorf("reply mirror = %q", mirrored[0])
+	}
+	if !strings.Contains(mirrored[1], "oc_chat") || !strings.Contains(mirrored[1], "world") {
+		t.Errorf("send mirror = %q", mirrored[1])
+	}
+	if mirrored[2] != "direct" {
+		t.Errorf("send to the audit chat itself should not be mirrored, got %q", mirrored[2])
+	}
+	if !strings.HasPrefix(mirrored[3], "[↩ om_unknown]") {
+		t.Errorf("reply to an unknown message = %q", mirrored[3])
+	}
+}
+
+// failingAuditMock fails every send to the audit chat.
+type failingAuditMock struct {
+	*MockFeishuClient
+}
+
+func (f failingAuditMock) SendText(chatID, text string) error {
+	if chatID == "oc_audit" {
+		return errors.New("audit chat unavailable")
+	}
+	return f.MockFeishuClient.SendText(chatID, text)
+}
+
+func TestAuditClientIgnoresMirrorFailure(t *testing.T) {
+	m := &MockFeishuClient{}
+	a := newAuditClient(failingAuditMock{m}, "oc_audit")
+
+	if err := a.ReplyText("om_1", "hello", false); err != nil {
+		t.Fatalf("reply should succeed when the audit send fails: %v", err)
+	}
+	a.flush()
+	if len(m.SentMessages) != 1 || !m.SentMessages[0].IsReply {
+		t.Fatalf("expected only the real reply, got %+v", m.SentMessages)
+	}
+}
+
+func TestAnnounceStartup(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config: Config{
+			AuditChatID:   "oc_audit",
+			CodexModel:    "gpt-test",
+			WorkingDir:    "/srv/repo",
+			SessionDBPath: "/var/lib/sessions.db",
+		},
+		feishuClient: newAuditClient(m, "oc_audit"),
+	}
+
+	b.announceStartup()
+	b.announceShutdown()
+
+	if len(m.SentMessages) != 2 {
+		t.Fatalf("expected startup and shutdown notices, got %+v", m.SentMessages)
+	}
+	startup := m.SentMessages[0]
+	if startup.ChatID != "oc_audit" {
+		t.Errorf("startup summary sent to %q", startup.ChatID)
+	}
+	for _, want := range []string{"gpt-test", "/srv/repo", "/var/lib/sessions.db"} {
+		if !strings.Contains(startup.Text, want) {
+			t.Errorf("startup summary %q missing %q", startup.Text, want)
+		}
+	}
+	if m.SentMessages[1].Text != defaultMessages.ShuttingDown {
+		t.Errorf("shutdown notice = %q", m.SentMessages[1].Text)
+	}
+
+	// Without an audit chat nothing is sent.
+	m2 := &MockFeishuClient{}
+	b2 := &Bridge{feishuClient: m2}
+	b2.announceStartup()
+	b2.announceShutdown()
+	if len(m2.SentMessages) != 0 {
+		t.Errorf("expected no sends without AuditChatID, got %+v", m2.SentMessages)
+	}
+}
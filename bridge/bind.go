@@ -0,0 +1,69 @@
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// handleBindCommand runs /bind for an admin and replies with the outcome.
+// It is called on its own goroutine, since resuming the thread waits on
+// codex.
+func (b *Bridge) handleBindCommand(msg *feishu.Message, threadID string) {
+	text := b.bindThread(msg.ChatID, threadID, b.codexClient)
+	if err := b.feishuClient.ReplyText(msg.MsgID, text, msg.ChatType == "group"); err != nil {
+		_ = b.feishuClient.SendText(msg.ChatID, text)
+	}
+}
+
+// bindThread handles /bind: it makes threadID the chat's thread, as if the
+// chat had created it, so operators can recover or hand over a known
+// session. The thread is resumed through client first, so a thread codex
+// doesn't know is refused. Chat settings are kept. The chat must be idle,
+// and a thread already in use by another chat is refused, since codex
+// events are routed to chats by thread.
+func (b *Bridge) bindThread(chatID, threadID string, client codex.CodexClient) string {
+	msgs := b.chatMessages(chatID)
+	if threadID == "" {
+		return fmt.Sprintf(msgs.BindUsage, b.withPrefix("/bind"))
+	}
+	if owner := b.findChatByThread(threadID); owner != "" && owner != chatID {
+		return fmt.Sprintf(msgs.BindInUse, threadID)
+	}
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	busy := state.Processing
+	state.mu.Unlock()
+	if busy {
+		return msgs.BindBusy
+	}
+
+	thread, err := client.ThreadResume(b.ctx, threadID)
+	if err == nil && thread == nil {
+		err = fmt.Errorf("empty thread/resume result")
+	}
+	if err != nil {
+		b.recordError(chatID, "bind %s: %v", threadID, err)
+		return fmt.Sprintf(msgs.BindFailed, threadID, err)
+	}
+	b.markThreadLoaded(threadID)
+
+	state.mu.Lock()
+	if state.Processing {
+		state.mu.Unlock()
+		return msgs.BindBusy
+	}
+	if state.ThreadID != threadID {
+		state.ChangedFiles = nil
+	}
+	state.ThreadID = threadID
+	state.TurnID = ""
+	state.mu.Unlock()
+	if _, err := b.sessionStore.Create(chatID, threadID); err != nil {
+		b.recordError(chatID, "bind %s: %v", threadID, err)
+		return fmt.Sprintf(msgs.BindFailed, threadID, err)
+	}
+	fmt.Printf("[Bridge] Bound chat %s to thread %s\n", chatID, threadID)
+	return fmt.Sprintf(msgs.BindDone, threadID)
+}
@@ -0,0 +1,92 @@
+package bridge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestBindThread(t *testing.T) {
+	b, _ := newTestBridge(t, withAdmin)
+	state := b.getChatState("c1")
+	state.ThreadID = "thread-old"
+	state.ChangedFiles = []string{"a.go"}
+	state.Effort = "high"
+
+	got := b.bindThread("c1", "thread-known", &MockCodexClient{})
+	if got != "🔗 已将本会话绑定到 Codex 线程 thread-known，后续消息会在该线程中继续" {
+		t.Fatalf("unexpected reply %q", got)
+	}
+	if state.ThreadID != "thread-known" || state.ChangedFiles != nil || state.Effort != "high" {
+		t.Errorf("expected the thread switched and settings kept, got %+v", state)
+	}
+	entry, err := b.sessionStore.GetByChatID("c1")
+	if err != nil || entry == nil || entry.ThreadID != "thread-known" {
+		t.Fatalf("expected the session to point at the bound thread, got %+v, %v", entry, err)
+	}
+	if !b.threadLoaded("thread-known") {
+		t.Error("expected the resumed thread to be marked loaded")
+	}
+}
+
+func TestBindThread_Refused(t *testing.T) {
+	b, _ := newTestBridge(t, withAdmin)
+	if _, err := b.sessionStore.Create("c1", "thread-1"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	b.getChatState("c1").ThreadID = "thread-1"
+	b.getChatState("c2").ThreadID = "thread-2"
+
+	missing := &MockCodexClient{ThreadResumeError: errors.New("thread not found: thread-x")}
+	if got := b.bindThread("c1", "thread-x", missing); got != "❌ 无法恢复线程 thread-x：thread not found: thread-x" {
+		t.Errorf("expected an unknown thread to be refused, got %q", got)
+	}
+	if b.threadLoaded("thread-x") {
+		t.Error("expected a thread that failed to resume not to be marked loaded")
+	}
+	if got := b.bindThread("c1", "thread-2", &MockCodexClient{}); got != "⚠️ 线程 thread-2 正被其他会话使用" {
+		t.Errorf("expected another chat's thread to be refused, got %q", got)
+	}
+	b.getChatState("c1").Processing = true
+	if got := b.bindThread("c1", "thread-3", &MockCodexClient{}); got != b.messages().BindBusy {
+		t.Errorf("expected a busy chat to be refused, got %q", got)
+	}
+	if got := b.bindThread("c1", "", &MockCodexClient{}); got != "⚠️ 用法：/bind <线程 ID>" {
+		t.Errorf("expected usage without a thread id, got %q", got)
+	}
+
+	if entry, err := b.sessionStore.GetByChatID("c1"); err != nil || entry == nil || entry.ThreadID != "thread-1" {
+		t.Errorf("expected the session to be left alone, got %+v, %v", entry, err)
+	}
+	if got := b.getChatState("c1").ThreadID; got != "thread-1" {
+		t.Errorf("expected the chat's thread to be left alone, got %q", got)
+	}
+}
+
+func TestHandleBindCommand_RepliesWithOutcome(t *testing.T) {
+	b, m := newTestBridge(t, withAdmin)
+	// codex isn't running in tests, so the resume fails.
+	b.handleBindCommand(&feishu.Message{ChatID: "c1", MsgID: "m1", Content: "/bind thread-known"}, "thread-known")
+	if got := findReplyText(m, "m1"); !strings.HasPrefix(got, "❌ 无法恢复线程 thread-known：") {
+		t.Fatalf("unexpected reply %q", got)
+	}
+}
+
+func TestBindCommand_AdminOnly(t *testing.T) {
+	b, m := newTestBridge(t, withAdmin)
+	b.handleFeishuMessageV2(&feishu.Message{
+		ChatID:  "c1",
+		MsgID:   "m1",
+		MsgType: "text",
+		Content: "/bind thread-1",
+		Sender:  &feishu.Sender{SenderID: "ou_user"},
+	})
+	if got := findReplyText(m, "m1"); got != "⚠️ 该命令仅管理员可用" {
+		t.Fatalf("expected non-admin to be rejected, got %q", got)
+	}
+	if entry, _ := b.sessionStore.GetByChatID("c1"); entry != nil {
+		t.Errorf("expected no session, got %+v", entry)
+	}
+}
@@ -3,12 +3,16 @@ package bridge
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/anthropics/feishu-codex-bridge/codex"
 	"github.com/anthropics/feishu-codex-bridge/feishu"
@@ -18,19 +22,232 @@ import (
 type Config struct {
 	FeishuAppID     string
 	FeishuAppSecret string
+	// FeishuBotOpenID is the bot's open_id; when set, its @-mention is
+	// stripped from incoming messages.
+	FeishuBotOpenID string
 	WorkingDir      string
 	CodexModel      string
-	SessionDBPath   string
-	SessionIdleMin  int
-	SessionResetHr  int
-	Debug           bool
+
+	// CodexModelProvider selects a non-default model provider configured in
+	// the codex CLI (sent as model_provider_id on thread/start). Requires
+	// CodexModel.
+	CodexModelProvider string
+
+	SessionDBPath  string
+	SessionIdleMin int
+	SessionResetHr int
+	Debug          bool
+
+	// InMemorySessions keeps sessions in memory instead of SessionDBPath,
+	// so every restart starts fresh.
+	InMemorySessions bool
+
+	// SessionDBDSN stores sessions in PostgreSQL instead of SessionDBPath,
+	// so several bridge instances can share them.
+	SessionDBDSN string
+
+	// IdleWarnMinutes warns on the next message when a session is within
+	// this many minutes of its idle reset. 0 disables the warning.
+	IdleWarnMinutes int
+
+	// CommandPrefix is what commands start with (default "/"), e.g. "!" to
+	// avoid colliding with other bots in the same group.
+	CommandPrefix string
+	// GroupCommandsRequireMention only treats group messages as commands when
+	// the bot is @-mentioned; New rejects it without FeishuBotOpenID. P2P
+	// chats are unaffected.
+	GroupCommandsRequireMention bool
+
+	// AdminOpenIDs lists the sender open_ids allowed to run admin commands
+	// (e.g. /cleanup). Empty means nobody.
+	AdminOpenIDs []string
+
+	// ProcessingEmoji is the reaction shown while a message is being processed.
+	// Empty means "Typing".
+	ProcessingEmoji string
+
+	// MaxAttachmentBytes caps the size of downloaded attachments; larger ones
+	// are skipped with a notice. 0 means feishu.DefaultMaxAttachmentBytes.
+	MaxAttachmentBytes int64
+
+	// MaxImagesPerMessage caps how many images of one message are downloaded
+	// and sent to codex; the rest are ignored with a notice. 0 means no limit.
+	MaxImagesPerMessage int
+
+	// ImagePrompt is the text sent to codex with an image-only message.
+	// Empty means the chat's Messages.ImagePrompt.
+	ImagePrompt string
+
+	// AcceptedMsgTypes lists the Feishu message types to process.
+	// Empty means feishu.DefaultAcceptedMsgTypes (text, image, post).
+	AcceptedMsgTypes []string
+
+	// DisableNetwork stops codex's sandbox from granting network access,
+	// for hosts where it must be forbidden (ALLOW_NETWORK=false).
+	DisableNetwork bool
+
+	// CleanupImages deletes downloaded images once the turn they were sent
+	// with is over. main.go defaults it to true (CLEANUP_IMAGES).
+	CleanupImages bool
+
+	// CommandCooldown is the minimum gap between two uses of the same
+	// expensive or state-changing command (see cooldownCommands) in a chat;
+	// ResetCooldown applies to /reset, which restarts codex. 0 disables the
+	// cooldown.
+	CommandCooldown time.Duration
+	ResetCooldown   time.Duration
+
+	// CodexDownPolicy is what happens to a message while the codex
+	// app-server is down: CodexDownReject or CodexDownWait. Empty skips the
+	// check and lets the codex request fail.
+	CodexDownPolicy string
+
+	// WarmupPolicy is what happens to a message while an app-server is
+	// starting: WarmupWait (also when empty) or WarmupReply.
+	WarmupPolicy string
+
+	// MaxPromptChars caps the text of one message sent to codex, in runes;
+	// 0 means no limit. LongPromptPolicy picks what happens to a longer
+	// message: LongPromptTrim (default when empty) or LongPromptReject.
+	MaxPromptChars   int
+	LongPromptPolicy string
+
+	// InterruptedOutput picks what an interrupted turn's partial response
+	// becomes: InterruptedOutputKeep (default when empty) sends it after the
+	// interruption notice, InterruptedOutputDiscard sends the notice alone.
+	InterruptedOutput string
+
+	// CodexInitTimeout bounds the codex app-server initialize handshake at
+	// startup and restarts. 0 means codex.DefaultInitTimeout.
+	CodexInitTimeout time.Duration
+
+	// CodexKeepAliveInterval is how often the codex app-server is pinged; a
+	// ping that goes unanswered restarts it like a crash. 0 disables it.
+	CodexKeepAliveInterval time.Duration
+
+	// CodexConfigOverrides are extra "key=value" codex -c overrides, already
+	// validated with codex.ParseConfigOverride, passed after the bridge's own.
+	CodexConfigOverrides []string
+
+	// ApprovalPolicy is "auto" (accept everything, default), "ask"
+	// (confirm each command/file change via reactions in the chat) or
+	// "readonly" (decline them all). /approvals can override it at runtime.
+	ApprovalPolicy string
+	// ApprovalTimeout is how long to wait for a reaction before declining.
+	ApprovalTimeout time.Duration
+
+	// RecalledTTL is how long recalled message IDs are remembered. Recalls
+	// only matter for queued/in-flight messages, so a few minutes is enough.
+	// 0 uses defaultRecalledTTL.
+	RecalledTTL time.Duration
+
+	// MaxResponseChars caps the length (in runes) of a turn's reply; longer
+	// replies are truncated with a notice and the rest is kept for /more. 0
+	// means no limit.
+	MaxResponseChars int
+
+	// QuotePromptInReply puts a one-line excerpt of the prompt above each
+	// turn's reply, so it's clear which message is being answered.
+	QuotePromptInReply bool
+
+	// SendImages sends images Codex viewed/produced (imageView items) back to
+	// the chat. Only files inside WorkingDir are sent.
+	SendImages bool
+
+	// GreetOnNewThread replies with a short notice whenever a fresh thread is
+	// created, so users can tell the previous context was reset.
+	GreetOnNewThread bool
+
+	// ParallelAsk enables /ask, which answers a one-off question on a
+	// throwaway thread alongside the chat's main thread. Off by default, so
+	// each chat stays strictly serialized.
+	ParallelAsk bool
+
+	// ProgressInterval, when set, posts a status message during long turns
+	// and updates it with the elapsed time and current step at this interval.
+	ProgressInterval time.Duration
+
+	// AckQueued replies to messages that are queued behind an in-progress turn.
+	AckQueued bool
+
+	// PlainTextOnly sends every reply, including /help, the welcome and
+	// pushed markdown, as plain text instead of rich-text posts.
+	PlainTextOnly bool
+
+	// LiveCommandOutput posts a message per running command and edits it
+	// with the tail of the command's output as it streams.
+	LiveCommandOutput bool
+
+	// MaxMessageAttempts is how many times a message's thread/start and
+	// turn/start requests are tried before it is dropped with a notice, so a
+	// message that keeps failing doesn't stall the chat. 0 or 1 means no
+	// retries.
+	MaxMessageAttempts int
+
+	// AllowedDirs restricts /cd to these directories and their
+	// subdirectories. Empty allows any directory.
+	AllowedDirs []string
+
+	// Workspaces are the named directories a bare /cd offers as buttons;
+	// "/cd <name>" switches to one too.
+	Workspaces []Workspace
+
+	// ClearOnDirMismatch starts a fresh thread instead of resuming one whose
+	// cwd (as reported by thread/resume) differs from WorkingDir.
+	ClearOnDirMismatch bool
+
+	// MaxConcurrentTurns caps how many chats can run a codex turn at once;
+	// further turns wait for a free slot. /ask turns are not counted. 0
+	// means unlimited.
+	MaxConcurrentTurns int
+
+	// VerboseP2P and VerboseGroup are the initial verbose mode (forwarding
+	// reasoning summaries and commands) for private and group chats; /verbose
+	// overrides it per chat.
+	VerboseP2P   bool
+	VerboseGroup bool
+
+	// WelcomeOnJoin sends a short introduction when the bot is added to a
+	// group chat.
+	WelcomeOnJoin bool
+
+	// Language selects the built-in user-facing strings: "zh" (default) or
+	// "en".
+	Language string
+
+	// MessagesFile is an optional JSON file overriding user-facing strings
+	// (see Messages) on top of Language's set.
+	MessagesFile string
+
+	// AuditChatID, when set, receives a copy of every text reply the bot
+	// sends elsewhere, as a single place to review bot activity.
+	AuditChatID string
+
+	// ChatInfoCacheTTL is how long chat info and member lists fetched from
+	// Feishu are reused; membership changes drop them early. 0 means
+	// DefaultChatInfoCacheTTL, negative disables the cache.
+	ChatInfoCacheTTL time.Duration
+
+	// IncludeSenderContext prepends the group name and sender name to
+	// group chat prompts so codex knows who is asking.
+	IncludeSenderContext bool
+
+	// WebhookAddr enables the HTTP webhook receiver when non-empty (e.g. ":8080").
+	WebhookAddr string
+	// WebhookSecret must be sent in the X-Webhook-Secret header.
+	WebhookSecret string
+	// AdminAPIToken enables the session admin endpoints on the webhook
+	// server, so it requires WebhookAddr; requests must send it as a bearer
+	// token.
+	AdminAPIToken string
 }
 
 type Bridge struct {
 	config       Config
 	feishuClient feishu.FeishuClient
+	chatCache    *chatInfoCache // wraps feishuClient; nil when caching is off
 	codexClient  *codex.Client
-	sessionStore *session.Store
+	sessionStore session.Store
 
 	// Per-chat state
 	chatStates   map[string]*ChatState
@@ -38,15 +255,56 @@ type Bridge struct {
 
 	// Codex process lifecycle (single app-server instance)
 	codexMu       sync.Mutex
+	codexStarting atomic.Int32 // app-server starts in flight; see warmup.go
+	switchingDir  atomic.Bool  // a /cd picker click is switching; see workspaces.go
 	activeThreads map[string]struct{}
+	loadedThreads map[string]struct{} // threads the current app-server started or resumed; see dirs.go
 	activeMu      sync.Mutex
+	turnSlots     chan struct{} // semaphore for Config.MaxConcurrentTurns; nil = unlimited
 
 	queuesMu   sync.Mutex
 	chatQueues map[string]*chatQueue
 
 	recalledMu  sync.Mutex
-	recalled    map[string]map[string]struct{}
-	recalledAll map[string]struct{}
+	recalled    map[string]map[string]time.Time // chatID -> msgID -> recall time
+	recalledAll map[string]time.Time
+
+	projectPromptsMu sync.Mutex
+	projectPrompts   map[string]string // workdir -> prompt.md contents
+
+	codexVersion string // `codex --version` output, set by Start
+
+	approvalsMu sync.Mutex
+	approvals   map[string]*pendingApproval
+	// approvalPolicy is set by /approvals global; empty = Config.ApprovalPolicy.
+	approvalPolicy string
+
+	msgs *Messages // user-facing strings; nil means defaultMessages
+
+	commandUsesMu sync.Mutex
+	commandUses   map[string]time.Time // chatID + kind -> last use, for command cooldowns
+
+	webhookUsesMu sync.Mutex
+	webhookUses   map[string]*webhookWindow // source address -> current rate window
+
+	welcomedMu sync.Mutex
+	welcomed   map[string]time.Time // chatID -> last welcome
+
+	recentErrorsMu sync.Mutex
+	recentErrors   []recentError // oldest first, at most maxRecentErrors
+
+	liveOutputsMu sync.Mutex
+	liveOutputs   map[string]*liveOutput // itemID -> live command output
+
+	sideTurnsMu sync.Mutex
+	sideTurns   map[string]*sideTurn // chatID -> in-flight /ask turn
+
+	// eventsDone is closed when the current client's event processor exits.
+	eventsDone chan struct{}
+
+	webhookServer *http.Server
+
+	stats bridgeStats
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -56,6 +314,7 @@ type Bridge struct {
 type chatQueue struct {
 	ch      chan *feishu.Message
 	pending []*feishu.Message
+	held    []*feishu.Message // received while paused; also listed in pending
 	mu      sync.Mutex
 }
 
@@ -63,45 +322,160 @@ type ChatState struct {
 	ThreadID             string
 	TurnID               string
 	MsgID                string // Current message ID for reactions
+	Prompt               string // text of MsgID; quoted by Config.QuotePromptInReply
+	SenderID             string // open_id of MsgID's sender, who may answer its approval requests
 	ProcessingReactionID string
 	Processing           bool
 	Gen                  uint64
 	ChatType             string
 	done                 chan struct{}
 	Buffer               strings.Builder
+	FinalText            string // completed agentMessage items of FinalTurnID; used when no deltas arrived
+	FinalTurnID          string
+	TurnStartedAt        time.Time // when turn/start succeeded; zero when no turn is running
 	LastItem             string
+	ChangedFiles         []string // files changed in the current session, oldest first
+	Paused               bool     // set by /pause; persisted in the session store
+	Verbose              bool     // forward reasoning summaries and commands
+	Effort               string   // reasoning effort set by /effort; empty = codex default
+	Language             string   // set by /lang; empty = Config.Language
+	Model                string   // set by /model; empty = Config.CodexModel
+	ApprovalPolicy       string   // set by /approvals; empty = bridge-wide policy
+	StreamMode           string   // set by /stream; empty = StreamModeFinal
+	verboseSet           bool     // Verbose was initialized from config or /verbose
+	lastDeltaItem        string   // item of the last agent delta; see isDuplicateDelta
+	lastDelta            string
+	turnImages           []string // images of the in-flight turn; see imagecleanup.go
+	pendingOutput        string   // the truncated rest of the last reply; see more.go
+	stream               streamState
 	mu                   sync.Mutex
 }
 
+// New creates a bridge whose session store follows the config: in memory
+// when InMemorySessions is set, PostgreSQL when SessionDBDSN is set, and
+// otherwise SQLite at SessionDBPath.
 func New(config Config) (*Bridge, error) {
-	// Initialize session store
-	sessionStore, err := session.NewStore(
-		config.SessionDBPath,
-		config.SessionIdleMin,
-		config.SessionResetHr,
-	)
+	if config.InMemorySessions && config.SessionDBDSN != "" {
+		return nil, fmt.Errorf("IN_MEMORY_SESSIONS and SESSION_DB_DSN can't both be set")
+	}
+	var sessionStore session.Store
+	var err error
+	if config.InMemorySessions {
+		sessionStore, err = session.NewMemoryStore(config.SessionIdleMin, config.SessionResetHr)
+	} else if config.SessionDBDSN != "" {
+		sessionStore, err = session.NewPostgresStore(config.SessionDBDSN, config.SessionIdleMin, config.SessionResetHr)
+	} else {
+		sessionStore, err = session.NewStore(
+			config.SessionDBPath,
+			config.SessionIdleMin,
+			config.SessionResetHr,
+		)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session store: %w", err)
 	}
 
+	b, err := NewWithStore(config, sessionStore)
+	if err != nil {
+		sessionStore.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// NewWithStore creates a bridge that keeps sessions in sessionStore instead
+// of the store New builds from the config; the Session* fields are then
+// ignored. The bridge closes the store on Stop.
+func NewWithStore(config Config, sessionStore session.Store) (*Bridge, error) {
+	if sessionStore == nil {
+		return nil, fmt.Errorf("session store is required")
+	}
+
+	// Resolve the working directory once so codex, /pwd and /cd all agree
+	// regardless of the process cwd later on.
+	if config.WorkingDir == "" {
+		config.WorkingDir = "."
+	}
+	absDir, err := filepath.Abs(config.WorkingDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve working dir: %w", err)
+	}
+	config.WorkingDir = absDir
+
+	if config.CodexModelProvider != "" && config.CodexModel == "" {
+		return nil, fmt.Errorf("CODEX_MODEL is required when CODEX_MODEL_PROVIDER is set")
+	}
+	// The admin API is only mounted on the webhook server.
+	if config.AdminAPIToken != "" && config.WebhookAddr == "" {
+		return nil, fmt.Errorf("WEBHOOK_ADDR is required when ADMIN_API_TOKEN is set")
+	}
+	// Without the bot's open_id no mention matches, so every group command
+	// would be ignored.
+	if config.GroupCommandsRequireMention && config.FeishuBotOpenID == "" {
+		return nil, fmt.Errorf("FEISHU_BOT_OPEN_ID is required when GROUP_COMMANDS_REQUIRE_MENTION is set")
+	}
+
+	lang, err := normalizeLanguage(config.Language)
+	if err != nil {
+		return nil, err
+	}
+	config.Language = lang
+	msgs := MessagesForLanguage(lang)
+	if config.MessagesFile != "" {
+		if msgs, err = LoadMessages(config.MessagesFile, msgs); err != nil {
+			return nil, err
+		}
+	}
+
 	// Initialize Feishu client
 	feishuClient := feishu.NewClient(config.FeishuAppID, config.FeishuAppSecret)
 	feishuClient.SetDebug(config.Debug)
+	feishuClient.SetAcceptedMsgTypes(config.AcceptedMsgTypes)
+	feishuClient.SetBotOpenID(config.FeishuBotOpenID)
+	feishuClient.SetMaxAttachmentBytes(config.MaxAttachmentBytes)
+
+	var client feishu.FeishuClient = feishuClient
+	if config.AuditChatID != "" {
+		client = newAuditClient(client, config.AuditChatID)
+	}
+	var chatCache *chatInfoCache
+	if config.ChatInfoCacheTTL >= 0 {
+		ttl := config.ChatInfoCacheTTL
+		if ttl == 0 {
+			ttl = DefaultChatInfoCacheTTL
+		}
+		chatCache = newChatInfoCache(client, ttl)
+		client = chatCache
+	}
 
-	// Initialize Codex client
-	codexClient := codex.NewClient(config.WorkingDir, config.CodexModel)
-
-	return &Bridge{
+	b := &Bridge{
 		config:        config,
-		feishuClient:  feishuClient,
-		codexClient:   codexClient,
+		feishuClient:  client,
+		chatCache:     chatCache,
 		sessionStore:  sessionStore,
 		chatStates:    make(map[string]*ChatState),
 		activeThreads: make(map[string]struct{}),
 		chatQueues:    make(map[string]*chatQueue),
-		recalled:      make(map[string]map[string]struct{}),
-		recalledAll:   make(map[string]struct{}),
-	}, nil
+		recalled:      make(map[string]map[string]time.Time),
+		recalledAll:   make(map[string]time.Time),
+		approvals:     make(map[string]*pendingApproval),
+		msgs:          &msgs,
+	}
+	if config.MaxConcurrentTurns > 0 {
+		b.turnSlots = make(chan struct{}, config.MaxConcurrentTurns)
+	}
+
+	b.loadPausedChats()
+	b.loadChatLanguages()
+	b.loadChatModels()
+	b.loadChatStreamModes()
+	b.loadChatEfforts()
+	b.loadChatApprovalPolicies()
+
+	// Initialize Codex client
+	b.codexClient = b.newCodexClient(config.WorkingDir)
+
+	return b, nil
 }
 
 func (b *Bridge) debugf(format string, args ...any) {
@@ -116,17 +490,26 @@ func (b *Bridge) debugf(format string, args ...any) {
 
 func (b *Bridge) Start() error {
 	b.ctx, b.cancel = context.WithCancel(context.Background())
+	b.stats.markStarted(time.Now())
 
 	fmt.Println("[Bridge] Starting Feishu-Codex bridge...")
 	fmt.Printf("[Bridge] Working directory: %s\n", b.config.WorkingDir)
 	fmt.Printf("[Bridge] Model: %s\n", b.config.CodexModel)
-	fmt.Printf("[Bridge] Session DB: %s\n", b.config.SessionDBPath)
+	if b.config.InMemorySessions {
+		fmt.Println("[Bridge] Session DB: in memory")
+	} else if b.config.SessionDBDSN != "" {
+		fmt.Println("[Bridge] Session DB: PostgreSQL")
+	} else {
+		fmt.Printf("[Bridge] Session DB: %s\n", b.config.SessionDBPath)
+	}
 	if b.config.Debug {
 		fmt.Println("[Bridge] Debug: true")
 	}
 
+	b.checkCodexVersion(b.ctx)
+
 	// Start Codex app-server
-	if err := b.codexClient.Start(b.ctx); err != nil {
+	if err := b.startCodex(b.ctx, b.codexClient); err != nil {
 		return fmt.Errorf("failed to start codex: %w", err)
 	}
 
@@ -136,10 +519,21 @@ func (b *Bridge) Start() error {
 	// Set up Feishu message handler
 	b.feishuClient.OnMessage(b.handleFeishuMessageV2)
 	b.feishuClient.OnMessageRecalled(b.handleFeishuMessageRecalled)
+	b.feishuClient.OnMessageRejected(b.handleFeishuMessageRejected)
+	b.feishuClient.OnReaction(b.handleFeishuReaction)
+	b.feishuClient.OnBotJoined(b.handleBotJoined)
+	b.feishuClient.OnChatChanged(b.handleChatChanged)
+	b.feishuClient.OnCardAction(b.handleCardAction)
 
 	// Start session cleanup
 	b.StartSessionCleanup(10 * time.Minute)
 
+	if b.config.WebhookAddr != "" {
+		if err := b.startWebhookServer(); err != nil {
+			return fmt.Errorf("failed to start webhook: %w", err)
+		}
+	}
+
 	// Start Feishu WebSocket in background; we block on context cancellation
 	// so Stop() can always unblock Start(), even if the SDK call doesn't return promptly.
 	fmt.Println("[Bridge] Starting Feishu connection...")
@@ -148,20 +542,34 @@ func (b *Bridge) Start() error {
 		feishuErrCh <- b.feishuClient.Start()
 	}()
 
-	select {
-	case err := <-feishuErrCh:
-		return err
-	case <-b.ctx.Done():
-		return nil
+	// feishuClient.Start blocks while connected, so the startup summary goes
+	// out once it has had feishuStartGrace to fail.
+	announce := time.NewTimer(feishuStartGrace)
+	defer announce.Stop()
+	for {
+		select {
+		case err := <-feishuErrCh:
+			return err
+		case <-announce.C:
+			go b.announceStartup()
+		case <-b.ctx.Done():
+			return nil
+		}
 	}
 }
 
+// feishuStartGrace is how long the Feishu connection must stay up before
+// Start announces the bridge as started.
+const feishuStartGrace = 3 * time.Second
+
 func (b *Bridge) Stop() {
 	fmt.Println("[Bridge] Stopping...")
 
 	if b.cancel != nil {
 		b.cancel()
 	}
+	b.stopWebhookServer()
+	b.announceShutdown()
 	b.feishuClient.Stop()
 	b.codexClient.Stop()
 	b.sessionStore.Close()
@@ -175,19 +583,26 @@ func (b *Bridge) Stop() {
 func (b *Bridge) handleFeishuMessageV2(msg *feishu.Message) {
 	fmt.Printf("[Bridge] Received %s from %s: %s\n", msg.MsgType, msg.ChatID, truncate(msg.Content, 50))
 
-	if cmd, ok := ParseCommand(msg.Content); ok {
+	if cmd, ok := b.parseCommand(msg); ok {
 		replyInThread := msg.ChatType == "group"
 		reactDone := func() {
 			_, _ = b.feishuClient.AddReaction(msg.MsgID, "DONE")
 		}
+		if !b.commandAllowed(msg.ChatID, cmd.Kind, time.Now()) {
+			text := b.chatMessages(msg.ChatID).CommandTooFrequent
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			return
+		}
 		switch cmd.Kind {
 		case CommandShowDir:
 			wd := b.config.WorkingDir
 			if abs, err := filepath.Abs(wd); err == nil {
 				wd = abs
 			}
-			if err := b.feishuClient.ReplyText(msg.MsgID, fmt.Sprintf("当前工作目录：%s", wd), replyInThread); err != nil {
-				_ = b.feishuClient.SendText(msg.ChatID, fmt.Sprintf("当前工作目录：%s", wd))
+			if err := b.feishuClient.ReplyText(msg.MsgID, fmt.Sprintf(b.chatMessages(msg.ChatID).ShowDir, wd), replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, fmt.Sprintf(b.chatMessages(msg.ChatID).ShowDir, wd))
 				reactDone()
 				return
 			}
@@ -195,9 +610,17 @@ func (b *Bridge) handleFeishuMessageV2(msg *feishu.Message) {
 			return
 
 		case CommandHelp:
-			title, content := buildHelpPost()
+			if cmd.Arg == HelpArgText || b.config.PlainTextOnly {
+				helpText := b.buildHelpFallbackText(b.chatLanguage(msg.ChatID))
+				if err := b.feishuClient.ReplyText(msg.MsgID, helpText, replyInThread); err != nil {
+					_ = b.feishuClient.SendText(msg.ChatID, helpText)
+				}
+				reactDone()
+				return
+			}
+			title, content := b.buildHelpPost(b.chatLanguage(msg.ChatID))
 			if err := b.feishuClient.ReplyRichText(msg.MsgID, title, content, replyInThread); err != nil {
-				helpText := buildHelpFallbackText()
+				helpText := b.buildHelpFallbackText(b.chatLanguage(msg.ChatID))
 				if err2 := b.feishuClient.ReplyText(msg.MsgID, helpText, replyInThread); err2 != nil {
 					_ = b.feishuClient.SendText(msg.ChatID, helpText)
 					reactDone()
@@ -219,6 +642,90 @@ func (b *Bridge) handleFeishuMessageV2(msg *feishu.Message) {
 			reactDone()
 			return
 
+		case CommandChanges:
+			text := b.formatChanges(msg.ChatID)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandAsk:
+			go b.handleAskCommand(msg, cmd.Arg)
+			return
+
+		case CommandExport:
+			go b.handleExportCommand(msg)
+			reactDone()
+			return
+
+		case CommandEffort:
+			text := b.setEffort(msg.ChatID, cmd.Arg)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandLang:
+			text := b.setLanguage(msg.ChatID, cmd.Arg)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandModel:
+			text := b.setModel(msg.ChatID, cmd.Arg)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandMore:
+			text := b.nextMoreOutput(msg.ChatID)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+		case CommandStream:
+			text := b.setStreamMode(msg.ChatID, cmd.Arg)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+		case CommandVerbose:
+			text := b.setVerbose(msg.ChatID, msg.ChatType, cmd.Arg)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandCommit:
+			go b.handleCommitCommand(msg, cmd.Arg)
+			reactDone()
+			return
+
+		case CommandVersion:
+			text := b.formatVersion(msg.ChatID)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandStats:
+			text := b.formatStats(msg.ChatID)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
 		case CommandStatus:
 			text := b.formatStatus(msg.ChatID)
 			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
@@ -229,10 +736,26 @@ func (b *Bridge) handleFeishuMessageV2(msg *feishu.Message) {
 			reactDone()
 			return
 
+		case CommandElapsed:
+			text := b.formatElapsedStatus(msg.ChatID)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandNew:
+			text := b.startNewThread(msg.ChatID)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
 		case CommandClear:
 			b.clearChatContext(msg.ChatID)
-			if err := b.feishuClient.ReplyText(msg.MsgID, "✅ 已清空当前会话上下文", replyInThread); err != nil {
-				_ = b.feishuClient.SendText(msg.ChatID, "✅ 已清空当前会话上下文")
+			if err := b.feishuClient.ReplyText(msg.MsgID, b.chatMessages(msg.ChatID).ClearDone, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, b.chatMessages(msg.ChatID).ClearDone)
 				reactDone()
 				return
 			}
@@ -241,7 +764,7 @@ func (b *Bridge) handleFeishuMessageV2(msg *feishu.Message) {
 
 		case CommandReset:
 			if err := b.resetCodexAndClearAll(); err != nil {
-				text := fmt.Sprintf("❌ 重置失败：%v", err)
+				text := fmt.Sprintf(b.chatMessages(msg.ChatID).ResetFailed, err)
 				if err2 := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err2 != nil {
 					_ = b.feishuClient.SendText(msg.ChatID, text)
 					reactDone()
@@ -250,26 +773,90 @@ func (b *Bridge) handleFeishuMessageV2(msg *feishu.Message) {
 				reactDone()
 				return
 			}
-			if err := b.feishuClient.ReplyText(msg.MsgID, "✅ 已重置", replyInThread); err != nil {
-				_ = b.feishuClient.SendText(msg.ChatID, "✅ 已重置")
+			if err := b.feishuClient.ReplyText(msg.MsgID, b.chatMessages(msg.ChatID).ResetDone, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, b.chatMessages(msg.ChatID).ResetDone)
 				reactDone()
 				return
 			}
 			reactDone()
 			return
 
+		case CommandPause, CommandResume:
+			text := b.chatMessages(msg.ChatID).AdminOnly
+			if b.isAdmin(msg) {
+				text = b.togglePause(msg.ChatID, cmd.Kind == CommandPause)
+			}
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandApprovals:
+			text := b.setApprovalPolicy(msg, cmd.Arg)
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandErrors:
+			text := b.chatMessages(msg.ChatID).AdminOnly
+			if b.isAdmin(msg) {
+				// Errors from every chat are only listed in a private chat.
+				text = b.formatRecentErrors(msg.ChatID, msg.ChatType == "p2p")
+			}
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
+		case CommandBind:
+			if !b.isAdmin(msg) {
+				text := b.chatMessages(msg.ChatID).AdminOnly
+				if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+					_ = b.feishuClient.SendText(msg.ChatID, text)
+				}
+				reactDone()
+				return
+			}
+			go b.handleBindCommand(msg, cmd.Arg)
+			reactDone()
+			return
+
+		case CommandCleanup:
+			text := b.chatMessages(msg.ChatID).AdminOnly
+			if b.isAdmin(msg) {
+				text = b.formatCleanupResult(msg.ChatID, b.runSessionCleanup())
+			}
+			if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+				_ = b.feishuClient.SendText(msg.ChatID, text)
+			}
+			reactDone()
+			return
+
 		case CommandSwitchDir:
-			if err := b.switchWorkingDir(msg.ChatID, cmd.Arg); err != nil {
-				if err2 := b.feishuClient.ReplyText(msg.MsgID, fmt.Sprintf("❌ 切换工作目录失败：%v", err), replyInThread); err2 != nil {
-					_ = b.feishuClient.SendText(msg.ChatID, fmt.Sprintf("❌ 切换工作目录失败：%v", err))
+			if cmd.Arg == "" {
+				b.replyWorkspacePicker(msg, replyInThread)
+				reactDone()
+				return
+			}
+			dir := cmd.Arg
+			if ws, ok := b.workspace(cmd.Arg); ok {
+				dir = ws.Dir
+			}
+			if err := b.switchWorkingDir(msg.ChatID, dir); err != nil {
+				if err2 := b.feishuClient.ReplyText(msg.MsgID, fmt.Sprintf(b.chatMessages(msg.ChatID).SwitchDirFailed, err), replyInThread); err2 != nil {
+					_ = b.feishuClient.SendText(msg.ChatID, fmt.Sprintf(b.chatMessages(msg.ChatID).SwitchDirFailed, err))
 					reactDone()
 					return
 				}
 				reactDone()
 				return
 			} else {
-				if err2 := b.feishuClient.ReplyText(msg.MsgID, fmt.Sprintf("✅ 已切换到新的工作目录：%s", b.config.WorkingDir), replyInThread); err2 != nil {
-					_ = b.feishuClient.SendText(msg.ChatID, fmt.Sprintf("✅ 已切换到新的工作目录：%s", b.config.WorkingDir))
+				if err2 := b.feishuClient.ReplyText(msg.MsgID, fmt.Sprintf(b.chatMessages(msg.ChatID).SwitchDirDone, b.config.WorkingDir), replyInThread); err2 != nil {
+					_ = b.feishuClient.SendText(msg.ChatID, fmt.Sprintf(b.chatMessages(msg.ChatID).SwitchDirDone, b.config.WorkingDir))
 					reactDone()
 					return
 				}
@@ -311,18 +898,45 @@ func (b *Bridge) enqueueMessage(msg *feishu.Message) {
 	q.mu.Lock()
 	q.pending = append(q.pending, msg)
 	pendingLen := len(q.pending)
+	held := b.holdIfPausedLocked(q, msg)
 	q.mu.Unlock()
 
+	if held {
+		b.debugf("Held while paused: chat_id=%s msg_id=%s", msg.ChatID, msg.MsgID)
+		b.replyText(msg.ChatID, msg.MsgID, fmt.Sprintf(b.chatMessages(msg.ChatID).PausedHold, b.withPrefix("/resume")), msg.ChatType == "group")
+		return
+	}
+
 	b.debugf("Enqueued: chat_id=%s msg_id=%s pending=%d chan_len=%d", msg.ChatID, msg.MsgID, pendingLen, len(q.ch))
 
 	if !b.trySendQueue(q.ch, msg) {
 		q.mu.Lock()
 		q.pending = removePendingByMsgID(q.pending, msg.MsgID)
 		q.mu.Unlock()
-		_ = b.feishuClient.ReplyText(msg.MsgID, "⚠️ 排队消息过多，请稍后再试。", msg.ChatType == "group")
+		b.replyText(msg.ChatID, msg.MsgID, b.chatMessages(msg.ChatID).QueueFull, msg.ChatType == "group")
+		return
+	}
+
+	if b.config.AckQueued {
+		b.ackQueued(msg, pendingLen)
 	}
 }
 
+// ackQueued lets the sender know their message is waiting behind an
+// in-progress turn. It stays quiet when the chat is idle.
+func (b *Bridge) ackQueued(msg *feishu.Message, pendingLen int) {
+	state := b.getChatState(msg.ChatID)
+	state.mu.Lock()
+	processing := state.Processing
+	state.mu.Unlock()
+	if !processing {
+		return
+	}
+	// pendingLen includes msg itself; the in-progress message is ahead of it.
+	text := fmt.Sprintf(b.chatMessages(msg.ChatID).Queued, pendingLen)
+	b.replyText(msg.ChatID, msg.MsgID, text, msg.ChatType == "group")
+}
+
 func (b *Bridge) trySendQueue(q chan *feishu.Message, msg *feishu.Message) (ok bool) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -352,19 +966,50 @@ func (b *Bridge) chatWorker(chatID string, q *chatQueue) {
 				continue
 			}
 			b.debugf("Dequeued: chat_id=%s msg_id=%s", chatID, msg.MsgID)
-			q.mu.Lock()
-			q.pending = removePendingByMsgID(q.pending, msg.MsgID)
-			pendingLen := len(q.pending)
-			q.mu.Unlock()
-			b.debugf("After dequeue pending len: chat_id=%s pending=%d", chatID, pendingLen)
-			b.processQueuedMessage(chatID, msg)
+			gen, ok := b.takeQueued(chatID, q, msg)
+			if !ok {
+				continue
+			}
+			b.processQueuedMessage(chatID, msg, gen)
 		}
 	}
 }
 
-func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message) {
+// takeQueued removes msg, just received from q.ch, from the pending list and
+// returns the chat generation to run it under. It reports false if msg
+// should not run now: it was dropped by /clear (or recalled) while still in
+// the channel, or the chat is paused and msg is held for /resume.
+func (b *Bridge) takeQueued(chatID string, q *chatQueue, msg *feishu.Message) (uint64, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !isPending(q.pending, msg.MsgID) {
+		b.debugf("Skip dropped message: chat_id=%s msg_id=%s", chatID, msg.MsgID)
+		return 0, false
+	}
+	if b.holdIfPausedLocked(q, msg) {
+		// Paused after this message was queued; keep it for /resume.
+		return 0, false
+	}
+	q.pending = removePendingByMsgID(q.pending, msg.MsgID)
+	b.debugf("After dequeue pending len: chat_id=%s pending=%d", chatID, len(q.pending))
+	// Read the generation under q.mu so a /clear racing with this dequeue
+	// is seen by processQueuedMessage.
+	return b.chatGen(chatID), true
+}
+
+// processQueuedMessage runs msg as a turn. queuedGen is the chat generation
+// when msg was dequeued; if a /clear has happened since, msg is dropped.
+func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message, queuedGen uint64) {
 	state := b.getChatState(chatID)
 
+	// Wait for a turn slot before marking the chat as processing, so a chat
+	// queued behind MAX_CONCURRENT_TURNS shows as waiting rather than busy.
+	release, ok := b.acquireTurnSlot(b.ctx)
+	if !ok {
+		return
+	}
+	defer release()
+
 	if b.isRecalled(msg.ChatID, msg.MsgID) {
 		b.debugf("Skip processing recalled message: chat_id=%s msg_id=%s", msg.ChatID, msg.MsgID)
 		b.clearRecalled(msg.ChatID, msg.MsgID)
@@ -372,14 +1017,33 @@ func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message) {
 	}
 
 	state.mu.Lock()
+	if state.Gen != queuedGen {
+		state.mu.Unlock()
+		b.debugf("Skip message cleared before it started: chat_id=%s msg_id=%s", chatID, msg.MsgID)
+		return
+	}
 	state.Processing = true
+	state.TurnStartedAt = time.Time{}
 	state.MsgID = msg.MsgID
+	state.SenderID = ""
+	if msg.Sender != nil {
+		state.SenderID = msg.Sender.SenderID
+	}
+	state.Prompt = msg.Content
 	state.ProcessingReactionID = ""
-	state.ChatType = msg.ChatType
+	// Webhook prompts carry no chat type; keep the one last seen.
+	if msg.ChatType != "" {
+		state.ChatType = msg.ChatType
+	}
+	b.applyVerboseDefaultLocked(state, state.ChatType)
 	gen := state.Gen
 	done := make(chan struct{})
 	state.done = done
 	state.Buffer.Reset()
+	state.FinalText = ""
+	state.FinalTurnID = ""
+	state.pendingOutput = ""
+	b.beginStreamLocked(chatID, state)
 	state.mu.Unlock()
 
 	defer func() {
@@ -393,6 +1057,7 @@ func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message) {
 			reactionID = state.ProcessingReactionID
 			shouldClose = state.done == done && state.done != nil
 			state.Processing = false
+			state.TurnStartedAt = time.Time{}
 			state.done = nil
 			state.ProcessingReactionID = ""
 		}
@@ -406,7 +1071,7 @@ func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message) {
 	}()
 
 	replyInThread := msg.ChatType == "group"
-	if reactionID, err := b.feishuClient.AddReaction(msg.MsgID, "Typing"); err == nil {
+	if reactionID := b.addProcessingReaction(msg.MsgID); reactionID != "" {
 		state.mu.Lock()
 		if state.Gen == gen {
 			state.ProcessingReactionID = reactionID
@@ -424,22 +1089,54 @@ func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message) {
 		if b.isRecalled(msg.ChatID, msg.MsgID) {
 			return false
 		}
-		if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
-			_ = b.feishuClient.SendText(chatID, text)
-		}
+		b.replyText(chatID, msg.MsgID, text, replyInThread)
 		return true
 	}
 
+	if !b.awaitWarmup(chatID, state, gen, sendReply) {
+		return
+	}
+	if !b.codexReady(chatID, state, gen, sendReply) {
+		return
+	}
+
+	text, ok := b.fillEmptyPrompt(chatID, msg, sendReply)
+	if !ok {
+		return
+	}
+	if text, ok = b.limitPrompt(chatID, text, sendReply); !ok {
+		return
+	} else if text != msg.Content {
+		trimmed := *msg
+		trimmed.Content = text
+		msg = &trimmed
+	}
+
 	// Download images if any
+	imageKeys, dropped := capImageKeys(msg.ImageKeys, b.config.MaxImagesPerMessage)
+	if dropped > 0 {
+		sendReply(fmt.Sprintf(b.chatMessages(chatID).ImagesCapped, len(imageKeys), dropped))
+	}
 	var imagePaths []string
-	for _, imageKey := range msg.ImageKeys {
+	for _, imageKey := range imageKeys {
 		path, err := b.feishuClient.DownloadImage(msg.MsgID, imageKey)
+		if errors.Is(err, feishu.ErrAttachmentTooLarge) {
+			sendReply(fmt.Sprintf(b.chatMessages(chatID).ImageTooLarge, formatBytes(b.maxAttachmentBytes())))
+			continue
+		}
 		if err != nil {
 			fmt.Printf("[Bridge] Failed to download image %s: %v\n", imageKey, err)
+			b.recordError(chatID, "download image %s: %v", imageKey, err)
 			continue
 		}
 		imagePaths = append(imagePaths, path)
 	}
+	turnStarted := false
+	defer func() {
+		if !turnStarted {
+			b.removeImages(imagePaths)
+		}
+	}()
 
 	ctx := b.ctx
 
@@ -447,21 +1144,41 @@ func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message) {
 	entry, err := b.sessionStore.GetByChatID(chatID)
 	if err != nil {
 		fmt.Printf("[Bridge] Failed to get session: %v\n", err)
+		b.recordError(chatID, "get session: %v", err)
 	}
 
 	var threadID string
-	if entry == nil || !b.sessionStore.IsFresh(entry) {
+	prompt := b.withSenderContext(msg)
+	content := prompt
+	resume := entry != nil && b.sessionStore.IsFresh(entry)
+	if resume && b.config.ClearOnDirMismatch && b.threadDirMismatch(ctx, b.codexClient, entry.ThreadID) {
+		fmt.Printf("[Bridge] Thread %s was created in another working directory, starting fresh\n", entry.ThreadID)
+		_ = b.sessionStore.Delete(chatID)
+		resume = false
+	}
+	if !resume {
 		fmt.Printf("[Bridge] Creating new thread for chat %s\n", chatID)
-		threadID, err = b.codexClient.ThreadStart(ctx, nil)
+		attempts, err := b.retryCodexRequest(ctx, state, gen, func() (err error) {
+			threadID, err = b.codexClient.ThreadStart(ctx, b.threadStartParams(chatID))
+			return err
+		})
 		if err != nil {
-			sendReply(fmt.Sprintf("❌ 创建会话失败: %v", err))
+			b.recordError(chatID, "thread/start (%d attempts): %v", attempts, err)
+			sendReply(b.failureAfterAttempts(chatID, b.chatMessages(chatID).CreateThreadFailed, err, attempts))
 			return
 		}
-		b.sessionStore.Create(chatID, threadID)
-		fmt.Printf("[Bridge] Created thread %s for chat %s\n", threadID, chatID)
+		b.markThreadLoaded(threadID)
+		if saved := b.saveThread(chatID, entry, threadID); saved != threadID {
+			threadID = saved
+		} else {
+			content = b.withProjectPrompt(prompt)
+			b.greetNewThread(chatID, sendReply)
+			fmt.Printf("[Bridge] Created thread %s for chat %s\n", threadID, chatID)
+		}
 	} else {
 		threadID = entry.ThreadID
 		fmt.Printf("[Bridge] Resuming thread %s for chat %s\n", threadID, chatID)
+		b.warnIdleSession(chatID, entry, sendReply)
 	}
 
 	state.mu.Lock()
@@ -469,20 +1186,30 @@ func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message) {
 		state.mu.Unlock()
 		return
 	}
+	if state.ThreadID != threadID {
+		state.ChangedFiles = nil
+	}
 	state.ThreadID = threadID
 	state.mu.Unlock()
 
-	turnID, err := b.codexClient.TurnStart(ctx, threadID, msg.Content, imagePaths)
+	var turnID string
+	attempts, err := b.retryCodexRequest(ctx, state, gen, func() (err error) {
+		turnID, err = b.codexClient.TurnStart(ctx, threadID, content, imagePaths, b.turnOptions(chatID))
+		return err
+	})
 	if err != nil {
-		if strings.Contains(err.Error(), "thread not found") {
+		if isThreadNotFound(err) {
 			fmt.Printf("[Bridge] Thread %s not found, creating new one\n", threadID)
 			_ = b.sessionStore.Delete(chatID)
-			threadID, err = b.codexClient.ThreadStart(ctx, nil)
+			threadID, err = b.codexClient.ThreadStart(ctx, b.threadStartParams(chatID))
 			if err != nil {
-				sendReply(fmt.Sprintf("❌ 创建会话失败: %v", err))
+				b.recordError(chatID, "thread/start: %v", err)
+				sendReply(b.codexFailureText(chatID, b.chatMessages(chatID).CreateThreadFailed, err))
 				return
 			}
+			b.markThreadLoaded(threadID)
 			_, _ = b.sessionStore.Create(chatID, threadID)
+			b.greetNewThread(chatID, sendReply)
 			state.mu.Lock()
 			if state.Gen != gen {
 				state.mu.Unlock()
@@ -490,32 +1217,44 @@ func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message) {
 			}
 			state.ThreadID = threadID
 			state.mu.Unlock()
-			turnID, err = b.codexClient.TurnStart(ctx, threadID, msg.Content, imagePaths)
+			turnID, err = b.codexClient.TurnStart(ctx, threadID, b.withProjectPrompt(prompt), imagePaths, b.turnOptions(chatID))
 			if err != nil {
-				sendReply(fmt.Sprintf("❌ 发送请求失败: %v", err))
+				b.recordError(chatID, "turn/start: %v", err)
+				sendReply(b.codexFailureText(chatID, b.chatMessages(chatID).SendRequestFailed, err))
 				return
 			}
 		} else {
-			sendReply(fmt.Sprintf("❌ 发送请求失败: %v", err))
+			b.recordError(chatID, "turn/start (%d attempts): %v", attempts, err)
+			sendReply(b.failureAfterAttempts(chatID, b.chatMessages(chatID).SendRequestFailed, err, attempts))
 			return
 		}
 	}
 
 	state.mu.Lock()
 	if state.Gen != gen {
+		// Cleared while the turn was starting; codex may still read the
+		// images, so they are left in place.
+		turnStarted = true
 		state.mu.Unlock()
 		return
 	}
 	state.TurnID = turnID
+	state.TurnStartedAt = time.Now()
+	b.setTurnImagesLocked(state, imagePaths)
+	turnStarted = true
 	state.mu.Unlock()
 
 	b.activeMu.Lock()
 	b.activeThreads[threadID] = struct{}{}
 	b.activeMu.Unlock()
 
+	b.stats.turnsStarted.Add(1)
 	fmt.Printf("[Bridge] Started turn %s in thread %s\n", turnID, threadID)
 	_ = b.sessionStore.Touch(chatID)
 
+	stopProgress := b.startProgressUpdates(msg, gen)
+	defer stopProgress()
+
 	select {
 	case <-done:
 	case <-b.ctx.Done():
@@ -523,14 +1262,162 @@ func (b *Bridge) processQueuedMessage(chatID string, msg *feishu.Message) {
 	}
 }
 
+// warnIdleSession gives a heads-up when a resumed session was idle long
+// enough to be within Config.IdleWarnMinutes of the idle reset.
+func (b *Bridge) warnIdleSession(chatID string, entry *session.Entry, sendReply func(string) bool) {
+	if b.config.IdleWarnMinutes <= 0 {
+		return
+	}
+	remaining, ok := b.sessionStore.IdleRemaining(entry)
+	if !ok || remaining > time.Duration(b.config.IdleWarnMinutes)*time.Minute {
+		return
+	}
+	mins := int((remaining + time.Minute - 1) / time.Minute)
+	sendReply(fmt.Sprintf(b.chatMessages(chatID).IdleWarning, mins))
+}
+
+// saveThread records threadID as chatID's session in place of read, the
+// entry the caller decided to replace (nil if there was none). If another
+// worker replaced the session since it was read and that session is still
+// fresh, it wins: its thread ID is returned and threadID is dropped.
+func (b *Bridge) saveThread(chatID string, read *session.Entry, threadID string) string {
+	if read != nil {
+		err := b.sessionStore.Update(read, threadID)
+		if err == nil {
+			return threadID
+		}
+		if !errors.Is(err, session.ErrConflict) {
+			b.recordError(chatID, "update session: %v", err)
+			return threadID
+		}
+		current, err := b.sessionStore.GetByChatID(chatID)
+		if err == nil && current != nil && b.sessionStore.IsFresh(current) {
+			fmt.Printf("[Bridge] Session of chat %s changed concurrently, using thread %s instead of %s\n", chatID, current.ThreadID, threadID)
+			return current.ThreadID
+		}
+	}
+	if _, err := b.sessionStore.Create(chatID, threadID); err != nil {
+		b.recordError(chatID, "create session: %v", err)
+	}
+	return threadID
+}
+
+// capImageKeys keeps the first max keys and reports how many were dropped.
+// max <= 0 keeps them all.
+func capImageKeys(keys []string, max int) ([]string, int) {
+	if max <= 0 || len(keys) <= max {
+		return keys, 0
+	}
+	return keys[:max], len(keys) - max
+}
+
+func (b *Bridge) maxAttachmentBytes() int64 {
+	if b.config.MaxAttachmentBytes <= 0 {
+		return feishu.DefaultMaxAttachmentBytes
+	}
+	return b.config.MaxAttachmentBytes
+}
+
+// formatBytes renders n as a short human-readable size, e.g. "20MB".
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%dMB", n>>20)
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%dKB", n>>10)
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
+
+// threadStartParams returns the thread/start params for a new thread in
+// chatID, derived from the chat's /model choice and config, or nil to let
+// codex use its defaults.
+func (b *Bridge) threadStartParams(chatID string) *codex.ThreadStartParams {
+	model := b.chatModel(chatID)
+	if b.config.CodexModelProvider == "" && model == b.config.CodexModel {
+		return nil
+	}
+	return &codex.ThreadStartParams{
+		Model:           model,
+		ModelProviderID: b.config.CodexModelProvider,
+	}
+}
+
+// greetNewThread tells the user a fresh thread was created (so earlier context
+// is gone), when Config.GreetOnNewThread is set.
+func (b *Bridge) greetNewThread(chatID string, sendReply func(string) bool) {
+	if !b.config.GreetOnNewThread {
+		return
+	}
+	sendReply(fmt.Sprintf(b.chatMessages(chatID).NewThreadGreeting, b.config.WorkingDir))
+}
+
+// fallbackProcessingEmojis are tried in order when the configured processing
+// emoji is rejected (e.g. not available in the tenant).
+var fallbackProcessingEmojis = []string{"OnIt", "FINGERHEART"}
+
+// addProcessingReaction adds the processing indicator to msgID and returns the
+// reaction ID of whichever emoji succeeded, or "" if all of them failed.
+func (b *Bridge) addProcessingReaction(msgID string) string {
+	if !canReplyTo(msgID) {
+		return ""
+	}
+	primary := b.config.ProcessingEmoji
+	if primary == "" {
+		primary = "Typing"
+	}
+	candidates := []string{primary}
+	for _, e := range fallbackProcessingEmojis {
+		if e != primary {
+			candidates = append(candidates, e)
+		}
+	}
+
+	for _, emoji := range candidates {
+		reactionID, err := b.feishuClient.AddReaction(msgID, emoji)
+		if err == nil {
+			return reactionID
+		}
+		fmt.Printf("[Bridge] Failed to add %s reaction to %s: %v\n", emoji, msgID, err)
+	}
+	return ""
+}
+
 func (b *Bridge) startEventProcessor(client *codex.Client) {
+	done := make(chan struct{})
+	b.eventsDone = done
 	b.wg.Add(1)
 	go func() {
 		defer b.wg.Done()
+		defer close(done)
 		for event := range client.Events() {
 			b.handleEvent(event)
 		}
 	}()
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.superviseCodex(client)
+	}()
+}
+
+// stopCodexClient stops the current codex client and waits for its event
+// processor to drain, so there is never more than one processor running when
+// a replacement client is started.
+func (b *Bridge) stopCodexClient() {
+	_ = b.codexClient.Stop()
+	if b.eventsDone == nil {
+		return
+	}
+	select {
+	case <-b.eventsDone:
+	case <-time.After(10 * time.Second):
+		fmt.Println("[Bridge] Timed out waiting for event processor to stop")
+	}
+	b.eventsDone = nil
 }
 
 func (b *Bridge) handleEvent(event codex.Event) {
@@ -557,10 +1444,12 @@ func (b *Bridge) handleEvent(event codex.Event) {
 			return
 		}
 		if chatID := b.findChatByThread(params.ThreadID); chatID != "" && params.Item != nil {
+			step := describeItem(params.Item, b.chatMessages(chatID))
 			state := b.getChatState(chatID)
 			state.mu.Lock()
-			state.LastItem = params.Item.Type
+			state.LastItem = step
 			state.mu.Unlock()
+			b.trackLiveOutput(chatID, params.Item)
 		}
 		if b.config.Debug {
 			fmt.Printf("[Bridge] Item started: %s (type: %s)\n", params.Item.ID, params.Item.Type)
@@ -575,12 +1464,36 @@ func (b *Bridge) handleEvent(event codex.Event) {
 			state := b.getChatState(chatID)
 			state.mu.Lock()
 			state.LastItem = ""
+			if params.Item != nil && params.Item.Type == "agentMessage" && params.Item.Text != "" {
+				recordFinalTextLocked(state, params.TurnID, params.Item.Text)
+			}
 			state.mu.Unlock()
+			if params.Item != nil {
+				switch params.Item.Type {
+				case "imageView":
+					b.handleImageViewItem(chatID, params.Item)
+				case "fileChange":
+					b.recordFileChanges(chatID, params.Item.Changes)
+				case "commandExecution":
+					if !b.finishLiveOutput(params.Item) {
+						b.forwardVerboseItem(chatID, params.Item)
+					}
+				case "reasoning":
+					b.forwardVerboseItem(chatID, params.Item)
+				}
+			}
 		}
 		if b.config.Debug {
 			fmt.Printf("[Bridge] Item completed: %s\n", params.Item.ID)
 		}
 
+	case codex.MethodCommandExecutionOutputDelta:
+		var params codex.CommandExecutionOutputDeltaParams
+		if err := json.Unmarshal(event.Params, &params); err != nil {
+			return
+		}
+		b.handleCommandOutputDelta(params)
+
 	default:
 		if b.config.Debug {
 			fmt.Printf("[Bridge] Event: %s\n", event.Method)
@@ -589,6 +1502,10 @@ func (b *Bridge) handleEvent(event codex.Event) {
 }
 
 func (b *Bridge) handleAgentDelta(params codex.AgentMessageDeltaParams) {
+	if b.appendSideDelta(params.ThreadID, params.Delta) {
+		return
+	}
+
 	// Find chat by thread ID
 	chatID := b.findChatByThread(params.ThreadID)
 	if chatID == "" {
@@ -597,15 +1514,58 @@ func (b *Bridge) handleAgentDelta(params codex.AgentMessageDeltaParams) {
 
 	state := b.getChatState(chatID)
 	state.mu.Lock()
+	if isDuplicateDelta(state, params.ItemID, params.Delta) {
+		state.mu.Unlock()
+		b.debugf("Dropping duplicate delta for item %s", params.ItemID)
+		return
+	}
 	state.Buffer.WriteString(params.Delta)
 	state.mu.Unlock()
+	b.streamDelta(chatID)
+}
+
+// minDuplicateDeltaLen is the shortest delta isDuplicateDelta treats as a
+// resend; short deltas (e.g. "-" in "---") legitimately repeat.
+const minDuplicateDeltaLen = 8
+
+// isDuplicateDelta reports whether delta repeats the last delta appended for
+// itemID, as the app-server may resend one after a reconnect, and records it
+// as the last delta otherwise. state.mu must be held.
+func isDuplicateDelta(state *ChatState, itemID, delta string) bool {
+	if itemID != "" && itemID == state.lastDeltaItem && delta == state.lastDelta && len(delta) >= minDuplicateDeltaLen {
+		return true
+	}
+	state.lastDeltaItem = itemID
+	state.lastDelta = delta
+	return false
+}
+
+// recordFinalTextLocked appends a completed agentMessage item's text to the
+// fallback for turnID, dropping text left over from an earlier turn.
+// state.mu must be held.
+func recordFinalTextLocked(state *ChatState, turnID, text string) {
+	if state.FinalTurnID != turnID {
+		state.FinalText = ""
+		state.FinalTurnID = turnID
+	}
+	if state.FinalText != "" {
+		state.FinalText += "\n\n"
+	}
+	state.FinalText += text
 }
 
 func (b *Bridge) handleTurnCompleted(params codex.TurnCompletedParams) {
+	b.stats.recordTurnCompleted(params.Status)
+
 	b.activeMu.Lock()
 	delete(b.activeThreads, params.ThreadID)
 	b.activeMu.Unlock()
 
+	if st := b.takeSideTurnByThread(params.ThreadID); st != nil {
+		b.finishSideTurn(st, params)
+		return
+	}
+
 	// Find chat by thread ID
 	chatID := b.findChatByThread(params.ThreadID)
 	if chatID == "" {
@@ -613,52 +1573,161 @@ func (b *Bridge) handleTurnCompleted(params codex.TurnCompletedParams) {
 		return
 	}
 
+	b.finishChatLiveOutputs(chatID)
+
 	state := b.getChatState(chatID)
 	state.mu.Lock()
-	response := state.Buffer.String()
+	worker := state.stream.worker
+	state.mu.Unlock()
+	// Streamed output must reach the chat before the final response.
+	worker.finish()
+
+	state.mu.Lock()
+	stream := state.stream
+	state.stream = streamState{}
+	// Paragraphs already sent in StreamModeChunks aren't repeated.
+	response := state.Buffer.String()[stream.sent:]
+	if response == "" && stream.sent == 0 && (state.FinalTurnID == params.TurnID || state.FinalTurnID == "" || params.TurnID == "") {
+		// The message arrived as completed items without deltas.
+		response = state.FinalText
+	}
+	state.pendingOutput = responseRemainder(response, b.config.MaxResponseChars)
 	msgID := state.MsgID
+	prompt := state.Prompt
 	processingReactionID := state.ProcessingReactionID
 	chatType := state.ChatType
 	done := state.done
 	state.Buffer.Reset()
+	state.FinalText = ""
+	state.FinalTurnID = ""
 	state.done = nil
 	state.Processing = false
+	state.TurnStartedAt = time.Time{}
 	state.ProcessingReactionID = ""
+	state.LastItem = ""
+	images := takeTurnImagesLocked(state)
 	state.mu.Unlock()
+	b.removeImages(images)
 
-	if response == "" {
-		response = "✅（无文字回应）"
+	if params.Status == "failed" {
+		b.recordError(chatID, "turn %s failed: %s", params.TurnID, params.ErrorMessage())
+	}
+	// Every paragraph already went out as a chunk; only the reaction is left.
+	streamedAll := stream.chunks > 0 && strings.TrimSpace(response) == "" && b.turnFailureNotice(chatID, params.Status) == ""
+	response, reaction := b.turnResponse(chatID, response, params)
+	if stream.chunks == 0 {
+		response = b.quotePrompt(prompt, response)
+	}
+
+	// The prompt may have been recalled after the turn started; don't answer it.
+	if msgID != "" && b.isRecalled(chatID, msgID) {
+		fmt.Printf("[Bridge] Message %s was recalled, dropping response for %s\n", msgID, chatID)
+		b.sessionStore.Touch(chatID)
+		if done != nil {
+			close(done)
+		}
+		return
 	}
 
 	// Replace "OnIt" reaction with completion reaction
 	if msgID != "" && processingReactionID != "" {
 		_ = b.feishuClient.RemoveReaction(msgID, processingReactionID)
 	}
-	if msgID != "" {
-		_, _ = b.feishuClient.AddReaction(msgID, "DONE")
+	if canReplyTo(msgID) {
+		_, _ = b.feishuClient.AddReaction(msgID, reaction)
 	}
 
 	// Send to Feishu
 	fmt.Printf("[Bridge] Turn completed, sending %d chars to %s\n", len(response), chatID)
-	replyInThread := chatType == "group"
-	if msgID != "" {
+	if !streamedAll && !b.finishLiveReply(stream, response) {
+		b.sendResponse(chatID, msgID, response, chatType == "group")
+	}
+
+	// Update session timestamp
+	b.sessionStore.Touch(chatID)
+
+	if done != nil {
+		close(done)
+	}
+}
+
+// sendResponse replies to msgID with response, falling back to a plain
+// message in the chat when the reply fails or there is no msgID.
+func (b *Bridge) sendResponse(chatID, msgID, response string, replyInThread bool) {
+	if canReplyTo(msgID) {
 		if err := b.feishuClient.ReplyText(msgID, response, replyInThread); err != nil {
 			fmt.Printf("[Bridge] Failed to reply response: %v\n", err)
 			if err := b.feishuClient.SendText(chatID, response); err != nil {
 				fmt.Printf("[Bridge] Failed to send response: %v\n", err)
+				b.recordError(chatID, "send response: %v", err)
 			}
 		}
 	} else {
 		if err := b.feishuClient.SendText(chatID, response); err != nil {
 			fmt.Printf("[Bridge] Failed to send response: %v\n", err)
+			b.recordError(chatID, "send response: %v", err)
 		}
 	}
+}
 
-	// Update session timestamp
-	b.sessionStore.Touch(chatID)
+// turnResponse builds the reply text and completion reaction for a finished
+// turn from its buffered output.
+func (b *Bridge) turnResponse(chatID, response string, params codex.TurnCompletedParams) (string, string) {
+	response = truncateResponse(response, b.config.MaxResponseChars, b.truncatedNotice(chatID))
+
+	reaction := "DONE"
+	if notice := b.turnFailureNotice(chatID, params.Status); notice != "" {
+		// Don't present a failed/interrupted turn as done; keep any partial
+		// output but make the outcome explicit.
+		reaction = turnFailedEmoji
+		if reason := params.ErrorMessage(); reason != "" {
+			if hint := b.codexErrorHint(chatID, reason); hint != "" {
+				reason = hint
+			}
+			notice += b.chatMessages(chatID).ReasonSeparator + reason
+		}
+		if params.Status == "interrupted" && b.config.InterruptedOutput == InterruptedOutputDiscard {
+			response = ""
+		}
+		switch {
+		case response == "":
+			response = notice
+		case params.Status == "interrupted":
+			// Lead with the notice so the partial output isn't read as an
+			// answer.
+			response = notice + "\n\n" + response
+		default:
+			response += "\n\n" + notice
+		}
+	}
+	if response == "" {
+		response = b.chatMessages(chatID).NoTextResponse
+	}
+	return response, reaction
+}
 
-	if done != nil {
-		close(done)
+// Supported values for Config.InterruptedOutput.
+const (
+	// InterruptedOutputKeep sends the partial response of an interrupted
+	// turn below the interruption notice (the default).
+	InterruptedOutputKeep = "keep"
+	// InterruptedOutputDiscard drops it and sends only the notice.
+	InterruptedOutputDiscard = "discard"
+)
+
+// turnFailedEmoji replaces DONE on turns that failed or were interrupted.
+const turnFailedEmoji = "CrossMark"
+
+// turnFailureNotice returns the user-facing note, in chatID's language, for
+// a turn that didn't complete successfully, or "" for a completed turn.
+func (b *Bridge) turnFailureNotice(chatID, status string) string {
+	switch status {
+	case "failed":
+		return b.chatMessages(chatID).TurnFailed
+	case "interrupted":
+		return b.chatMessages(chatID).TurnInterrupted
+	default:
+		return ""
 	}
 }
 
@@ -686,6 +1755,16 @@ func (b *Bridge) findChatByThread(threadID string) string {
 	return ""
 }
 
+// truncateResponse keeps the first max runes of response and appends
+// notice, see truncatedNotice. max <= 0 disables the limit.
+func truncateResponse(response string, max int, notice string) string {
+	if max <= 0 || utf8.RuneCountInString(response) <= max {
+		return response
+	}
+	runes := []rune(response)
+	return strings.TrimRight(string(runes[:max]), " \n") + "\n\n" + notice
+}
+
 func truncate(s string, n int) string {
 	if len(s) <= n {
 		return s
@@ -715,18 +1794,22 @@ func (b *Bridge) switchWorkingDir(chatID, newDir string) error {
 	if !info.IsDir() {
 		return fmt.Errorf("不是目录：%s", absDir)
 	}
+	if !dirAllowed(absDir, b.config.AllowedDirs) {
+		return fmt.Errorf("目录不在允许范围内：%s", absDir)
+	}
 	if absDir == b.config.WorkingDir {
 		return nil
 	}
 
 	// Stop old server and start a new one under the new working directory.
-	_ = b.codexClient.Stop()
+	b.stopCodexClient()
+	b.abortSideTurns()
 
-	newClient := codex.NewClient(absDir, b.config.CodexModel)
-	if err := newClient.Start(b.ctx); err != nil {
+	newClient := b.newCodexClient(absDir)
+	if err := b.startCodex(b.ctx, newClient); err != nil {
 		// Try to restore previous client to keep bridge usable.
-		restore := codex.NewClient(b.config.WorkingDir, b.config.CodexModel)
-		if restoreErr := restore.Start(b.ctx); restoreErr == nil {
+		restore := b.newCodexClient(b.config.WorkingDir)
+		if restoreErr := b.startCodex(b.ctx, restore); restoreErr == nil {
 			b.codexClient = restore
 			b.startEventProcessor(b.codexClient)
 		}
@@ -735,6 +1818,7 @@ func (b *Bridge) switchWorkingDir(chatID, newDir string) error {
 
 	b.codexClient = newClient
 	b.config.WorkingDir = absDir
+	b.forgetProjectPrompt(absDir)
 	b.startEventProcessor(b.codexClient)
 
 	// Reset the session for this chat to avoid resuming threads from the old server.
@@ -743,16 +1827,22 @@ func (b *Bridge) switchWorkingDir(chatID, newDir string) error {
 	state.mu.Lock()
 	state.ThreadID = ""
 	state.TurnID = ""
+	state.TurnStartedAt = time.Time{}
+	state.ChangedFiles = nil
 	if state.done != nil {
 		close(state.done)
 		state.done = nil
 	}
 	state.Buffer.Reset()
+	images := takeTurnImagesLocked(state)
 	state.mu.Unlock()
+	// The old app-server is gone, so nothing reads the images any more.
+	b.removeImages(images)
 
 	// Clear any stale in-flight state.
 	b.activeMu.Lock()
 	b.activeThreads = make(map[string]struct{})
+	b.loadedThreads = nil
 	b.activeMu.Unlock()
 
 	// Drop queued messages for this chat (they were intended for the previous workdir).
@@ -760,6 +1850,7 @@ func (b *Bridge) switchWorkingDir(chatID, newDir string) error {
 	if q, ok := b.chatQueues[chatID]; ok {
 		q.mu.Lock()
 		q.pending = nil
+		q.held = nil
 		q.mu.Unlock()
 		for {
 			select {
@@ -775,12 +1866,22 @@ func (b *Bridge) switchWorkingDir(chatID, newDir string) error {
 	return nil
 }
 
+// clearChatContext resets chatID's session. The clear takes effect at its
+// position in the chat's FIFO: messages queued before it (and the turn in
+// progress) are dropped, while messages enqueued afterwards are processed
+// on a fresh thread.
 func (b *Bridge) clearChatContext(chatID string) {
-	b.codexMu.Lock()
-	defer b.codexMu.Unlock()
-
 	state := b.getChatState(chatID)
 
+	b.queuesMu.Lock()
+	q := b.chatQueues[chatID]
+	b.queuesMu.Unlock()
+
+	// Hold q.mu across the reset so the worker can't start a queued message
+	// halfway through; see chatWorker.
+	if q != nil {
+		q.mu.Lock()
+	}
 	var threadID string
 	var msgID string
 	var reactionID string
@@ -796,43 +1897,44 @@ func (b *Bridge) clearChatContext(chatID string) {
 	state.Processing = false
 	state.ThreadID = ""
 	state.TurnID = ""
+	state.TurnStartedAt = time.Time{}
 	state.MsgID = ""
+	state.SenderID = ""
+	state.Prompt = ""
 	state.ProcessingReactionID = ""
 	state.LastItem = ""
+	state.ChangedFiles = nil
 	state.Buffer.Reset()
+	state.FinalText = ""
+	state.FinalTurnID = ""
+	state.pendingOutput = ""
+	images := takeTurnImagesLocked(state)
 	state.mu.Unlock()
-
-	if threadID != "" {
-		_ = b.codexClient.TurnInterrupt(b.ctx, threadID)
+	_ = b.sessionStore.Delete(chatID)
+	if q != nil {
+		// Stale entries left in q.ch are skipped by chatWorker since they
+		// are no longer pending.
+		q.pending = nil
+		q.held = nil
+		q.mu.Unlock()
 	}
+
 	if msgID != "" && reactionID != "" {
 		_ = b.feishuClient.RemoveReaction(msgID, reactionID)
 	}
 
-	_ = b.sessionStore.Delete(chatID)
-
 	b.activeMu.Lock()
 	if threadID != "" {
 		delete(b.activeThreads, threadID)
 	}
 	b.activeMu.Unlock()
 
-	// Drop queued messages for this chat.
-	b.queuesMu.Lock()
-	if q, ok := b.chatQueues[chatID]; ok {
-		q.mu.Lock()
-		q.pending = nil
-		q.mu.Unlock()
-		for {
-			select {
-			case <-q.ch:
-			default:
-				goto drained
-			}
-		}
-	drained:
+	if threadID != "" {
+		b.codexMu.Lock()
+		_ = b.codexClient.TurnInterrupt(b.ctx, threadID)
+		b.codexMu.Unlock()
 	}
-	b.queuesMu.Unlock()
+	b.removeImages(images)
 }
 
 func (b *Bridge) resetCodexAndClearAll() error {
@@ -863,10 +1965,14 @@ func (b *Bridge) resetCodexAndClearAll() error {
 		st.Processing = false
 		st.ThreadID = ""
 		st.TurnID = ""
+		st.TurnStartedAt = time.Time{}
 		st.MsgID = ""
+		st.SenderID = ""
+		st.Prompt = ""
 		st.ProcessingReactionID = ""
 		st.LastItem = ""
 		st.Buffer.Reset()
+		images := takeTurnImagesLocked(st)
 		st.mu.Unlock()
 
 		if done != nil {
@@ -875,6 +1981,7 @@ func (b *Bridge) resetCodexAndClearAll() error {
 		if threadID != "" {
 			_ = b.codexClient.TurnInterrupt(b.ctx, threadID)
 		}
+		b.removeImages(images)
 		if msgID != "" && reactionID != "" {
 			_ = b.feishuClient.RemoveReaction(msgID, reactionID)
 		}
@@ -884,13 +1991,15 @@ func (b *Bridge) resetCodexAndClearAll() error {
 	// Clear active threads and queues.
 	b.activeMu.Lock()
 	b.activeThreads = make(map[string]struct{})
+	b.loadedThreads = nil
 	b.activeMu.Unlock()
 	b.closeAllChatQueues()
 
 	// Restart Codex app-server.
-	_ = b.codexClient.Stop()
-	newClient := codex.NewClient(b.config.WorkingDir, b.config.CodexModel)
-	if err := newClient.Start(b.ctx); err != nil {
+	b.stopCodexClient()
+	b.abortSideTurns()
+	newClient := b.newCodexClient(b.config.WorkingDir)
+	if err := b.startCodex(b.ctx, newClient); err != nil {
 		return fmt.Errorf("启动 Codex 失败：%w", err)
 	}
 	b.codexClient = newClient
@@ -931,33 +2040,77 @@ func (b *Bridge) handleFeishuMessageRecalled(ev *feishu.MessageRecalled) {
 	b.debugf("Drop pending: in_chat_removed=%d all_chats_removed=%d", removedInChat, removedAll)
 }
 
+// handleFeishuMessageRejected tells the sender that their message type is
+// not enabled (see ACCEPTED_MSG_TYPES).
+func (b *Bridge) handleFeishuMessageRejected(msg *feishu.Message) {
+	if msg == nil || msg.MsgID == "" {
+		return
+	}
+	text := fmt.Sprintf(b.chatMessages(msg.ChatID).MsgTypeDisabled, msg.MsgType)
+	b.replyText(msg.ChatID, msg.MsgID, text, msg.ChatType == "group")
+}
+
+// defaultRecalledTTL bounds how long recalled message IDs are kept.
+const defaultRecalledTTL = 10 * time.Minute
+
+func (b *Bridge) recalledTTL() time.Duration {
+	if b.config.RecalledTTL > 0 {
+		return b.config.RecalledTTL
+	}
+	return defaultRecalledTTL
+}
+
 func (b *Bridge) markRecalled(chatID, msgID string) {
 	b.recalledMu.Lock()
 	defer b.recalledMu.Unlock()
-	b.recalledAll[msgID] = struct{}{}
+	now := time.Now()
+	b.pruneRecalledLocked(now)
+	b.recalledAll[msgID] = now
 	if chatID == "" {
 		return
 	}
 	m, ok := b.recalled[chatID]
 	if !ok {
-		m = make(map[string]struct{})
+		m = make(map[string]time.Time)
 		b.recalled[chatID] = m
 	}
-	m[msgID] = struct{}{}
+	m[msgID] = now
 }
 
 func (b *Bridge) isRecalled(chatID, msgID string) bool {
 	b.recalledMu.Lock()
 	defer b.recalledMu.Unlock()
-	if _, ok := b.recalledAll[msgID]; ok {
+	cutoff := time.Now().Add(-b.recalledTTL())
+	if at, ok := b.recalledAll[msgID]; ok && at.After(cutoff) {
 		return true
 	}
 	m, ok := b.recalled[chatID]
 	if !ok {
 		return false
 	}
-	_, ok = m[msgID]
-	return ok
+	at, ok := m[msgID]
+	return ok && at.After(cutoff)
+}
+
+// pruneRecalledLocked drops recall marks older than the TTL so the maps stay
+// bounded over long uptimes. Caller must hold recalledMu.
+func (b *Bridge) pruneRecalledLocked(now time.Time) {
+	cutoff := now.Add(-b.recalledTTL())
+	for msgID, at := range b.recalledAll {
+		if !at.After(cutoff) {
+			delete(b.recalledAll, msgID)
+		}
+	}
+	for chatID, m := range b.recalled {
+		for msgID, at := range m {
+			if !at.After(cutoff) {
+				delete(m, msgID)
+			}
+		}
+		if len(m) == 0 {
+			delete(b.recalled, chatID)
+		}
+	}
 }
 
 func (b *Bridge) clearRecalled(chatID, msgID string) {
@@ -995,7 +2148,7 @@ func (b *Bridge) formatQueueStatus(chatID string) string {
 		pending = append(pending, q.pending...)
 		q.mu.Unlock()
 	}
-	return fmt.Sprintf("待处理：%d", len(pending))
+	return fmt.Sprintf(b.chatMessages(chatID).Pending, len(pending))
 }
 
 func (b *Bridge) dropPendingMessage(chatID, msgID string) int {
@@ -1048,6 +2201,24 @@ func (b *Bridge) clearChatContextByMsgID(msgID string) {
 		}
 	}
 }
+
+// chatGen returns chatID's current generation.
+func (b *Bridge) chatGen(chatID string) uint64 {
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.Gen
+}
+
+func isPending(pending []*feishu.Message, msgID string) bool {
+	for _, m := range pending {
+		if m != nil && m.MsgID == msgID {
+			return true
+		}
+	}
+	return false
+}
+
 func removePendingByMsgID(pending []*feishu.Message, msgID string) []*feishu.Message {
 	if len(pending) == 0 {
 		return pending
@@ -1074,15 +2245,123 @@ func (b *Bridge) StartSessionCleanup(interval time.Duration) {
 		for {
 			select {
 			case <-ticker.C:
-				count, err := b.sessionStore.CleanupStale()
-				if err != nil {
-					fmt.Printf("[Bridge] Session cleanup error: %v\n", err)
-				} else if count > 0 {
-					fmt.Printf("[Bridge] Cleaned up %d stale sessions\n", count)
-				}
+				b.runSessionCleanup()
 			case <-b.ctx.Done():
 				return
 			}
 		}
 	}()
 }
+
+// cleanupResult reports what one session cleanup pass removed.
+type cleanupResult struct {
+	Sessions   int64
+	ChatStates int
+	Err        error
+}
+
+// runSessionCleanup removes stale sessions and idle chat states. It is used by
+// both the periodic cleaner and /cleanup.
+func (b *Bridge) runSessionCleanup() cleanupResult {
+	var res cleanupResult
+	res.Sessions, res.Err = b.sessionStore.CleanupStale()
+	if res.Err != nil {
+		fmt.Printf("[Bridge] Session cleanup error: %v\n", res.Err)
+	} else if res.Sessions > 0 {
+		fmt.Printf("[Bridge] Cleaned up %d stale sessions\n", res.Sessions)
+	}
+	res.ChatStates = b.evictIdleChatStates()
+	if res.ChatStates > 0 {
+		fmt.Printf("[Bridge] Evicted %d idle chat states\n", res.ChatStates)
+	}
+	return res
+}
+
+func (b *Bridge) formatCleanupResult(chatID string, res cleanupResult) string {
+	msgs := b.chatMessages(chatID)
+	if res.Err != nil {
+		return fmt.Sprintf(msgs.CleanupFailed, res.Err)
+	}
+	return fmt.Sprintf(msgs.CleanupDone, res.Sessions, res.ChatStates)
+}
+
+// isAdmin reports whether the message sender is in Config.AdminOpenIDs.
+func (b *Bridge) isAdmin(msg *feishu.Message) bool {
+	return msg.Sender != nil && b.isAdminID(msg.Sender.SenderID)
+}
+
+// isAdminID reports whether openID is one of Config.AdminOpenIDs.
+func (b *Bridge) isAdminID(openID string) bool {
+	if openID == "" {
+		return false
+	}
+	for _, id := range b.config.AdminOpenIDs {
+		if id == openID {
+			return true
+		}
+	}
+	return false
+}
+
+// evictIdleChatStates drops in-memory state for chats that are not processing,
+// have nothing queued and whose session is no longer fresh, so long-running
+// servers don't keep every chat they have ever seen. getChatState recreates
+// the state on the next message.
+func (b *Bridge) evictIdleChatStates() int {
+	b.chatStatesMu.RLock()
+	candidates := make([]string, 0, len(b.chatStates))
+	for chatID, state := range b.chatStates {
+		if b.chatStateIdle(state) {
+			candidates = append(candidates, chatID)
+		}
+	}
+	b.chatStatesMu.RUnlock()
+
+	evicted := 0
+	for _, chatID := range candidates {
+		if b.hasQueuedMessages(chatID) {
+			continue
+		}
+		entry, err := b.sessionStore.GetByChatID(chatID)
+		if err != nil || b.sessionStore.IsFresh(entry) {
+			continue
+		}
+
+		b.chatStatesMu.Lock()
+		// Re-check under the lock: a message may have arrived meanwhile.
+		if state, ok := b.chatStates[chatID]; ok && b.chatStateIdle(state) {
+			delete(b.chatStates, chatID)
+			evicted++
+		}
+		b.chatStatesMu.Unlock()
+	}
+	return evicted
+}
+
+// chatStateIdle reports whether state can be evicted: no turn is running and
+// it holds no per-chat setting. Paused chats and chats with a /lang, /model,
+// /approvals, /stream, /effort or non-default /verbose choice keep their
+// state so the setting isn't lost.
+func (b *Bridge) chatStateIdle(state *ChatState) bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.Processing || state.done != nil || state.Paused {
+		return false
+	}
+	if state.Language != "" || state.Model != "" || state.ApprovalPolicy != "" || state.StreamMode != "" || state.Effort != "" {
+		return false
+	}
+	return !state.verboseSet || state.Verbose == b.defaultVerbose(state.ChatType)
+}
+
+func (b *Bridge) hasQueuedMessages(chatID string) bool {
+	b.queuesMu.Lock()
+	q, ok := b.chatQueues[chatID]
+	b.queuesMu.Unlock()
+	if !ok {
+		return false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending) > 0
+}
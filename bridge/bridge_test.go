@@ -2,10 +2,15 @@ package bridge
 
 import (
 	"encoding/json"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+	"github.com/anthropics/feishu-codex-bridge/session"
 )
 
 func TestTruncate(t *testing.T) {
@@ -29,6 +34,26 @@ func TestTruncate(t *testing.T) {
 	}
 }
 
+func TestTruncateResponse(t *testing.T) {
+	tests := []struct {
+		input    string
+		max      int
+		expected string
+	}{
+		{"hello", 0, "hello"},
+		{"hello", 5, "hello"},
+		{"hello world", 5, "hello\n\n(truncated)"},
+		{"你好世界", 2, "你好\n\n(truncated)"},
+		{"line1\nline2", 6, "line1\n\n(truncated)"},
+	}
+
+	for _, tt := range tests {
+		if got := truncateResponse(tt.input, tt.max, "(truncated)"); got != tt.expected {
+			t.Errorf("truncateResponse(%q, %d) = %q, want %q", tt.input, tt.max, got, tt.expected)
+		}
+	}
+}
+
 func TestChatState(t *testing.T) {
 	state := &ChatState{}
 
@@ -129,6 +154,179 @@ func TestNew(t *testing.T) {
 	bridge.sessionStore.Close()
 }
 
+func TestNewWithStore(t *testing.T) {
+	store, err := session.NewMemoryStore(60, -1)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	store.Create("c1", "t1")
+
+	// SessionDBPath is ignored: nothing should be created on disk.
+	dbPath := filepath.Join(t.TempDir(), "unused", "sessions.db")
+	b, err := NewWithStore(Config{WorkingDir: t.TempDir(), SessionDBPath: dbPath}, store)
+	if err != nil {
+		t.Fatalf("NewWithStore: %v", err)
+	}
+	if b.sessionStore != store {
+		t.Fatal("bridge does not use the injected store")
+	}
+	if entry, _ := b.sessionStore.GetByChatID("c1"); entry == nil || entry.ThreadID != "t1" {
+		t.Errorf("session from injected store = %+v, want t1", entry)
+	}
+	if _, err := os.Stat(filepath.Dir(dbPath)); !os.IsNotExist(err) {
+		t.Errorf("NewWithStore touched SessionDBPath: %v", err)
+	}
+
+	if _, err := NewWithStore(Config{WorkingDir: t.TempDir()}, nil); err == nil {
+		t.Error("expected an error for a nil store")
+	}
+	if _, err := NewWithStore(Config{WorkingDir: t.TempDir(), AdminAPIToken: "secret"}, store); err == nil {
+		t.Error("expected an error for ADMIN_API_TOKEN without WEBHOOK_ADDR")
+	}
+	if _, err := NewWithStore(Config{WorkingDir: t.TempDir(), GroupCommandsRequireMention: true}, store); err == nil {
+		t.Error("expected an error for GROUP_COMMANDS_REQUIRE_MENTION without FEISHU_BOT_OPEN_ID")
+	}
+}
+
+func TestThreadStartParams(t *testing.T) {
+	b := &Bridge{config: Config{CodexModel: "gpt-5.2-codex"}}
+	if p := b.threadStartParams("chat1"); p != nil {
+		t.Fatalf("expected nil params without a provider, got %+v", p)
+	}
+
+	b.config.CodexModelProvider = "azure"
+	p := b.threadStartParams("chat1")
+	if p == nil || p.ModelProviderID != "azure" || p.Model != "gpt-5.2-codex" {
+		t.Fatalf("unexpected params: %+v", p)
+	}
+}
+
+func TestNew_InMemorySessionsConflictsWithDSN(t *testing.T) {
+	_, err := New(Config{
+		WorkingDir:       t.TempDir(),
+		InMemorySessions: true,
+		SessionDBDSN:     "postgres://localhost/bridge",
+		SessionResetHr:   -1,
+	})
+	if err == nil || !strings.Contains(err.Error(), "SESSION_DB_DSN") {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+}
+
+func TestNew_ProviderRequiresModel(t *testing.T) {
+	tmpDir := t.TempDir()
+	_, err := New(Config{
+		WorkingDir:         tmpDir,
+		CodexModelProvider: "azure",
+		SessionDBPath:      filepath.Join(tmpDir, "test.db"),
+		SessionResetHr:     -1,
+	})
+	if err == nil {
+		t.Fatal("expected error when provider is set without a model")
+	}
+}
+
+func TestGreetNewThread(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.WorkingDir = "/srv/proj"
+
+	var sent []string
+	sendReply := func(text string) bool {
+		sent = append(sent, text)
+		return true
+	}
+
+	b.greetNewThread("c1", sendReply)
+	if len(sent) != 0 {
+		t.Fatalf("expected no greeting when disabled, got %v", sent)
+	}
+
+	b.config.GreetOnNewThread = true
+	b.greetNewThread("c1", sendReply)
+	if len(sent) != 1 || sent[0] != "👋 新会话已开始（工作目录：/srv/proj）" {
+		t.Fatalf("unexpected greeting: %v", sent)
+	}
+
+	b.setLanguage("c1", "en")
+	b.greetNewThread("c1", sendReply)
+	if len(sent) != 2 || sent[1] != "👋 New session started (working directory: /srv/proj)" {
+		t.Fatalf("unexpected English greeting: %v", sent)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:           "512B",
+		2048:          "2KB",
+		20 << 20:      "20MB",
+		(3 << 20) / 2: "1.5MB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestWarnIdleSession(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.IdleWarnMinutes = 10
+
+	var sent []string
+	sendReply := func(text string) bool {
+		sent = append(sent, text)
+		return true
+	}
+
+	b.warnIdleSession("c1", &session.Entry{UpdatedAt: time.Now().Add(-30 * time.Minute)}, sendReply)
+	if len(sent) != 0 {
+		t.Fatalf("expected no warning for a recently active session, got %v", sent)
+	}
+
+	b.warnIdleSession("c1", &session.Entry{UpdatedAt: time.Now().Add(-55 * time.Minute)}, sendReply)
+	if len(sent) != 1 || sent[0] != "⏰ 提醒：本会话已空闲较久，距离自动重置上下文只差约 5 分钟" {
+		t.Fatalf("unexpected warning: %v", sent)
+	}
+
+	b.setLanguage("c1", "en")
+	b.warnIdleSession("c1", &session.Entry{UpdatedAt: time.Now().Add(-55 * time.Minute)}, sendReply)
+	if len(sent) != 2 || !strings.Contains(sent[1], "about 5 minutes") {
+		t.Fatalf("expected an English warning, got %v", sent)
+	}
+
+	b.config.IdleWarnMinutes = 0
+	b.warnIdleSession("c1", &session.Entry{UpdatedAt: time.Now().Add(-55 * time.Minute)}, sendReply)
+	if len(sent) != 2 {
+		t.Fatalf("expected no warning when disabled, got %v", sent)
+	}
+}
+
+func TestNew_ResolvesRelativeWorkingDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	if err := os.Mkdir("proj", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	bridge, err := New(Config{
+		WorkingDir:     "proj",
+		SessionDBPath:  filepath.Join(tmpDir, "test.db"),
+		SessionResetHr: -1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bridge: %v", err)
+	}
+	defer bridge.sessionStore.Close()
+
+	want, _ := filepath.Abs("proj")
+	if bridge.config.WorkingDir != want {
+		t.Errorf("WorkingDir = %q, want %q", bridge.config.WorkingDir, want)
+	}
+	if !filepath.IsAbs(bridge.config.WorkingDir) {
+		t.Errorf("WorkingDir should be absolute, got %q", bridge.config.WorkingDir)
+	}
+}
+
 func TestGetChatState(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -249,6 +447,26 @@ func TestHandleAgentDelta(t *testing.T) {
 	}
 }
 
+func TestHandleAgentDelta_DropsDuplicateDelta(t *testing.T) {
+	b := &Bridge{chatStates: make(map[string]*ChatState)}
+	state := b.getChatState("chat123")
+	state.ThreadID = "thread456"
+
+	send := func(itemID, delta string) {
+		b.handleAgentDelta(codex.AgentMessageDeltaParams{ThreadID: "thread456", ItemID: itemID, Delta: delta})
+	}
+	send("item1", "Hello there, ")
+	send("item1", "Hello there, ") // resent after a reconnect
+	send("item1", "-")
+	send("item1", "-") // short deltas may legitimately repeat
+	send("item2", "-") // new item
+	send("item2", "Hello there, ")
+
+	if got, want := state.Buffer.String(), "Hello there, ---Hello there, "; got != want {
+		t.Errorf("Buffer mismatch: got %q, want %q", got, want)
+	}
+}
+
 func TestHandleAgentDelta_NoChat(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -412,6 +630,45 @@ func TestChatStateMutex(t *testing.T) {
 	}
 }
 
+func TestHandleTurnCompleted_UsesCompletedItemWithoutDeltas(t *testing.T) {
+	b, m := newTestBridge(t)
+	state := b.getChatState("c1")
+	state.ThreadID = "t1"
+	state.MsgID = "m1"
+
+	// The whole message arrives as a completed item; no deltas.
+	b.handleEvent(codex.Event{
+		Method: codex.MethodItemCompleted,
+		Params: json.RawMessage(`{"threadId":"t1","turnId":"turn1","item":{"id":"i1","type":"agentMessage","text":"cached answer"}}`),
+	})
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "completed"})
+
+	if len(m.SentMessages) != 1 || m.SentMessages[0].Text != "cached answer" {
+		t.Fatalf("expected the completed item's text, got %+v", m.SentMessages)
+	}
+}
+
+func TestHandleItemCompleted_CapturesAgentMessagesPerTurn(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{feishuClient: m, chatStates: make(map[string]*ChatState)}
+	state := b.getChatState("c1")
+	state.ThreadID = "t1"
+
+	complete := func(turnID, text string) {
+		b.handleEvent(codex.Event{
+			Method: codex.MethodItemCompleted,
+			Params: json.RawMessage(`{"threadId":"t1","turnId":"` + turnID + `","item":{"id":"i","type":"agentMessage","text":"` + text + `"}}`),
+		})
+	}
+	complete("turn0", "stale")
+	complete("turn1", "first")
+	complete("turn1", "second")
+
+	if state.FinalTurnID != "turn1" || state.FinalText != "first\n\nsecond" {
+		t.Fatalf("expected turn1's messages only, got %q for %q", state.FinalText, state.FinalTurnID)
+	}
+}
+
 func TestHandleTurnCompleted_NoChat(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -510,3 +767,121 @@ func TestDebugModeEvents(t *testing.T) {
 	bridge.handleEvent(codex.Event{Method: codex.MethodItemStarted, Params: itemParams})
 	bridge.handleEvent(codex.Event{Method: codex.MethodItemCompleted, Params: itemParams})
 }
+
+func TestEvictIdleChatStates(t *testing.T) {
+	b, _ := newTestBridge(t)
+
+	// Idle chat without a fresh session: evicted.
+	b.getChatState("idle").ThreadID = "t-idle"
+	// Mid-turn chat: kept.
+	b.getChatState("busy").Processing = true
+	// Idle chat with a fresh session: kept.
+	b.getChatState("fresh")
+	if _, err := b.sessionStore.Create("fresh", "t-fresh"); err != nil {
+		t.Fatal(err)
+	}
+	// Idle chat with queued messages: kept.
+	b.getChatState("queued")
+	b.chatQueues["queued"] = &chatQueue{pending: []*feishu.Message{{MsgID: "m1"}}}
+
+	if n := b.evictIdleChatStates(); n != 1 {
+		t.Fatalf("expected 1 eviction, got %d", n)
+	}
+	if _, ok := b.chatStates["idle"]; ok {
+		t.Error("idle chat state should be evicted")
+	}
+	for _, chatID := range []string{"busy", "fresh", "queued"} {
+		if _, ok := b.chatStates[chatID]; !ok {
+			t.Errorf("chat state %s should be kept", chatID)
+		}
+	}
+	if b.findChatByThread("t-idle") != "" {
+		t.Error("evicted chat should no longer be found by thread")
+	}
+}
+
+func TestChatStateIdle_KeepsSettings(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.VerboseP2P = true
+
+	idle := b.getChatState("idle")
+	b.setVerbose("idle", "p2p", "on") // same as the p2p default
+	if !b.chatStateIdle(idle) {
+		t.Error("a chat with only default settings should be idle")
+	}
+
+	for name, set := range map[string]func(*ChatState){
+		"stream":  func(s *ChatState) { s.StreamMode = StreamModeChunks },
+		"effort":  func(s *ChatState) { s.Effort = "high" },
+		"verbose": func(s *ChatState) { s.verboseSet, s.Verbose, s.ChatType = true, false, "p2p" },
+	} {
+		state := b.getChatState(name)
+		set(state)
+		if b.chatStateIdle(state) {
+			t.Errorf("a chat with a %s setting should not be idle", name)
+		}
+	}
+}
+
+func TestCleanupCommand_AdminOnly(t *testing.T) {
+	b, m := newTestBridge(t, withAdmin)
+	b.getChatState("idle")
+
+	cleanup := func(sender string) string {
+		b.handleFeishuMessageV2(&feishu.Message{
+			ChatID:  "c1",
+			MsgID:   "m_" + sender,
+			MsgType: "text",
+			Content: "/cleanup",
+			Sender:  &feishu.Sender{SenderID: sender},
+		})
+		return m.SentMessages[len(m.SentMessages)-1].Text
+	}
+
+	if got := cleanup("ou_user"); got != "⚠️ 该命令仅管理员可用" {
+		t.Fatalf("expected non-admin to be rejected, got %q", got)
+	}
+	if _, ok := b.chatStates["idle"]; !ok {
+		t.Fatal("non-admin cleanup should not evict anything")
+	}
+
+	if got := cleanup("ou_admin"); got != "🧹 已清理 0 个过期会话，释放 1 个空闲 chat 状态" {
+		t.Fatalf("unexpected cleanup reply: %q", got)
+	}
+}
+
+func TestSaveThread_ConcurrentReplacementWins(t *testing.T) {
+	b, _ := newTestBridge(t)
+	store := b.sessionStore
+
+	// No previous session: the new thread is stored.
+	if got := b.saveThread("c1", nil, "thread-1"); got != "thread-1" {
+		t.Fatalf("saveThread = %s, want thread-1", got)
+	}
+
+	// Unchanged since read: replaced.
+	read, _ := store.GetByChatID("c1")
+	if got := b.saveThread("c1", read, "thread-2"); got != "thread-2" {
+		t.Fatalf("saveThread = %s, want thread-2", got)
+	}
+
+	// Another worker replaced it after this one read it: theirs wins.
+	read, _ = store.GetByChatID("c1")
+	store.Create("c1", "thread-other")
+	if got := b.saveThread("c1", read, "thread-3"); got != "thread-other" {
+		t.Fatalf("saveThread = %s, want thread-other", got)
+	}
+	if entry, _ := store.GetByChatID("c1"); entry.ThreadID != "thread-other" {
+		t.Errorf("stored thread = %s, want thread-other", entry.ThreadID)
+	}
+
+	// The session was deleted meanwhile (e.g. /new): the new thread is stored.
+	read, _ = store.GetByChatID("c1")
+	store.Delete("c1")
+	if got := b.saveThread("c1", read, "thread-4"); got != "thread-4" {
+		t.Fatalf("saveThread = %s, want thread-4", got)
+	}
+	if entry, _ := store.GetByChatID("c1"); entry == nil || entry.ThreadID != "thread-4" {
+		t.Errorf("stored session = %+v, want thread-4", entry)
+	}
+}
@@ -0,0 +1,70 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// maxTrackedChanges caps how many changed paths are remembered per chat; the
+// oldest entries are dropped first.
+const maxTrackedChanges = 200
+
+// maxListedChanges caps how many paths /changes prints.
+const maxListedChanges = 50
+
+// recordFileChanges adds the paths of a completed fileChange item to the
+// chat's session ledger, keeping first-seen order without duplicates.
+func (b *Bridge) recordFileChanges(chatID string, changes []codex.FileChange) {
+	if len(changes) == 0 {
+		return
+	}
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for _, ch := range changes {
+		if ch.Path == "" {
+			continue
+		}
+		seen := false
+		for _, p := range state.ChangedFiles {
+			if p == ch.Path {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			state.ChangedFiles = append(state.ChangedFiles, ch.Path)
+		}
+	}
+	if over := len(state.ChangedFiles) - maxTrackedChanges; over > 0 {
+		state.ChangedFiles = append([]string(nil), state.ChangedFiles[over:]...)
+	}
+}
+
+func (b *Bridge) formatChanges(chatID string) string {
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	files := append([]string(nil), state.ChangedFiles...)
+	state.mu.Unlock()
+
+	msgs := b.chatMessages(chatID)
+	if len(files) == 0 {
+		return msgs.ChangesNone
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, msgs.ChangesHeader, len(files))
+	for i, f := range files {
+		if i == maxListedChanges {
+			sb.WriteString("\n")
+			fmt.Fprintf(&sb, msgs.ListMore, len(files)-maxListedChanges)
+			break
+		}
+		sb.WriteString("\n- ")
+		sb.WriteString(f)
+	}
+	return sb.String()
+}
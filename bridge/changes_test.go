@@ -0,0 +1,63 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+func TestFileChangeItems_TrackedAcrossTurns(t *testing.T) {
+	b := &Bridge{chatStates: make(map[string]*ChatState)}
+	b.getChatState("c1").ThreadID = "t1"
+
+	if out := b.formatChanges("c1"); out != "本会话还没有修改过文件" {
+		t.Fatalf("unexpected empty output: %q", out)
+	}
+
+	for _, params := range []string{
+		`{"threadId":"t1","turnId":"turn1","item":{"type":"fileChange","id":"i1","changes":[{"path":"a.go"},{"path":"b.go"}]}}`,
+		`{"threadId":"t1","turnId":"turn2","item":{"type":"fileChange","id":"i2","changes":[{"path":"b.go"},{"path":"c.go"}]}}`,
+	} {
+		b.handleEvent(codex.Event{Method: codex.MethodItemCompleted, Params: json.RawMessage(params)})
+	}
+
+	want := "本会话修改过的文件（3）：\n- a.go\n- b.go\n- c.go"
+	if out := b.formatChanges("c1"); out != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+}
+
+func TestFormatChanges_UsesChatLanguage(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.setLanguage("c1", "en")
+	if out := b.formatChanges("c1"); out != englishMessages.ChangesNone {
+		t.Fatalf("unexpected empty output: %q", out)
+	}
+	b.recordFileChanges("c1", []codex.FileChange{{Path: "a.go"}})
+	if out := b.formatChanges("c1"); out != "Files changed in this session (1):\n- a.go" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestRecordFileChanges_Capped(t *testing.T) {
+	b := &Bridge{chatStates: make(map[string]*ChatState)}
+
+	var changes []codex.FileChange
+	for i := 0; i < maxTrackedChanges+10; i++ {
+		changes = append(changes, codex.FileChange{Path: fmt.Sprintf("f%d.go", i)})
+	}
+	b.recordFileChanges("c1", changes)
+
+	files := b.getChatState("c1").ChangedFiles
+	if len(files) != maxTrackedChanges || files[0] != "f10.go" {
+		t.Fatalf("expected oldest entries dropped, got %d starting at %s", len(files), files[0])
+	}
+
+	out := b.formatChanges("c1")
+	if !strings.Contains(out, fmt.Sprintf("…另有 %d 个", maxTrackedChanges-maxListedChanges)) {
+		t.Fatalf("expected listing to be capped, got %q", out[len(out)-40:])
+	}
+}
@@ -0,0 +1,93 @@
+package bridge
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// DefaultChatInfoCacheTTL is how long chat info and member lists are reused
+// when Config.ChatInfoCacheTTL is 0.
+const DefaultChatInfoCacheTTL = 10 * time.Minute
+
+// chatInfoCache wraps a FeishuClient and caches GetChatInfo and
+// GetChatMembers per chat for ttl. Errors are not cached. Entries are
+// dropped early by invalidate, e.g. when the chat's membership changes.
+type chatInfoCache struct {
+	feishu.FeishuClient
+	ttl time.Duration
+
+	mu      sync.Mutex
+	infos   map[string]cachedChatInfo
+	members map[string]cachedChatMembers
+}
+
+type cachedChatInfo struct {
+	info      *feishu.ChatInfo
+	fetchedAt time.Time
+}
+
+type cachedChatMembers struct {
+	members   []*feishu.ChatMember
+	fetchedAt time.Time
+}
+
+func newChatInfoCache(inner feishu.FeishuClient, ttl time.Duration) *chatInfoCache {
+	return &chatInfoCache{
+		FeishuClient: inner,
+		ttl:          ttl,
+		infos:        make(map[string]cachedChatInfo),
+		members:      make(map[string]cachedChatMembers),
+	}
+}
+
+func (c *chatInfoCache) GetChatInfo(chatID string) (*feishu.ChatInfo, error) {
+	c.mu.Lock()
+	cached, ok := c.infos[chatID]
+	c.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < c.ttl {
+		return cached.info, nil
+	}
+	info, err := c.FeishuClient.GetChatInfo(chatID)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.infos[chatID] = cachedChatInfo{info: info, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return info, nil
+}
+
+func (c *chatInfoCache) GetChatMembers(chatID string) ([]*feishu.ChatMember, error) {
+	c.mu.Lock()
+	cached, ok := c.members[chatID]
+	c.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < c.ttl {
+		return cached.members, nil
+	}
+	members, err := c.FeishuClient.GetChatMembers(chatID)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.members[chatID] = cachedChatMembers{members: members, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return members, nil
+}
+
+// invalidate drops the cached info and members of chatID.
+func (c *chatInfoCache) invalidate(chatID string) {
+	c.mu.Lock()
+	delete(c.infos, chatID)
+	delete(c.members, chatID)
+	c.mu.Unlock()
+}
+
+// handleChatChanged drops cached chat info when Feishu reports a membership
+// or chat change.
+func (b *Bridge) handleChatChanged(chatID string) {
+	if b.chatCache != nil {
+		b.chatCache.invalidate(chatID)
+	}
+}
@@ -0,0 +1,50 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestChatInfoCache_ReusesWithinTTL(t *testing.T) {
+	m := &MockFeishuClient{
+		ChatInfos:   map[string]*feishu.ChatInfo{"oc_1": {ChatID: "oc_1", Name: "研发组"}},
+		ChatMembers: map[string][]*feishu.ChatMember{"oc_1": {{MemberID: "ou_1", Name: "张三"}}},
+	}
+	c := newChatInfoCache(m, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		info, err := c.GetChatInfo("oc_1")
+		if err != nil || info == nil || info.Name != "研发组" {
+			t.Fatalf("GetChatInfo = %+v, %v", info, err)
+		}
+		members, err := c.GetChatMembers("oc_1")
+		if err != nil || len(members) != 1 {
+			t.Fatalf("GetChatMembers = %v, %v", members, err)
+		}
+	}
+	if m.ChatInfoCalls != 1 || m.ChatMembersCalls != 1 {
+		t.Fatalf("expected one call each within TTL, got info=%d members=%d", m.ChatInfoCalls, m.ChatMembersCalls)
+	}
+
+	b := &Bridge{chatCache: c}
+	b.handleChatChanged("oc_1")
+	_, _ = c.GetChatInfo("oc_1")
+	_, _ = c.GetChatMembers("oc_1")
+	if m.ChatInfoCalls != 2 || m.ChatMembersCalls != 2 {
+		t.Fatalf("expected a refetch after invalidation, got info=%d members=%d", m.ChatInfoCalls, m.ChatMembersCalls)
+	}
+}
+
+func TestChatInfoCache_ExpiresAfterTTL(t *testing.T) {
+	m := &MockFeishuClient{}
+	c := newChatInfoCache(m, time.Millisecond)
+
+	_, _ = c.GetChatInfo("oc_1")
+	time.Sleep(5 * time.Millisecond)
+	_, _ = c.GetChatInfo("oc_1")
+	if m.ChatInfoCalls != 2 {
+		t.Fatalf("expected a refetch after the TTL, got %d calls", m.ChatInfoCalls)
+	}
+}
@@ -0,0 +1,64 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// codexErrorHints maps known codex error signatures (lower-case substrings of
+// the error message) to actionable guidance from the chat's Messages. The
+// first match wins.
+var codexErrorHints = []struct {
+	signatures []string
+	hint       func(b *Bridge, m *Messages) string
+}{
+	{
+		signatures: []string{"not logged in", "unauthorized", "authentication", "invalid_api_key", "invalid api key", "refresh token", "token expired", "login required"},
+		hint:       func(_ *Bridge, m *Messages) string { return m.CodexNotLoggedIn },
+	},
+	{
+		signatures: []string{"usage limit", "quota", "insufficient_quota", "billing"},
+		hint:       func(_ *Bridge, m *Messages) string { return m.CodexQuotaExhausted },
+	},
+	{
+		signatures: []string{"rate limit", "rate_limit", "too many requests"},
+		hint:       func(_ *Bridge, m *Messages) string { return m.CodexRateLimited },
+	},
+	{
+		signatures: []string{"context window", "context length", "context_length_exceeded", "maximum context"},
+		hint: func(b *Bridge, m *Messages) string {
+			return fmt.Sprintf(m.CodexContextTooLong, b.withPrefix("/clear"))
+		},
+	},
+}
+
+// codexErrorHint returns guidance in chatID's language for a known codex
+// error message, or "".
+func (b *Bridge) codexErrorHint(chatID, message string) string {
+	lower := strings.ToLower(message)
+	for _, h := range codexErrorHints {
+		for _, sig := range h.signatures {
+			if strings.Contains(lower, sig) {
+				return h.hint(b, b.chatMessages(chatID))
+			}
+		}
+	}
+	return ""
+}
+
+// codexFailureText formats a failed codex request for the user, replacing
+// the raw error with guidance when its signature is recognized.
+func (b *Bridge) codexFailureText(chatID, action string, err error) string {
+	message := err.Error()
+	var rpcErr *codex.RPCError
+	if errors.As(err, &rpcErr) {
+		message = rpcErr.Message
+	}
+	if hint := b.codexErrorHint(chatID, message); hint != "" {
+		return "❌ " + action + b.chatMessages(chatID).ReasonSeparator + hint
+	}
+	return fmt.Sprintf("❌ %s: %v", action, err)
+}
@@ -0,0 +1,59 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+func TestCodexErrorHint(t *testing.T) {
+	b, _ := newTestBridge(t)
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"401 Unauthorized: missing bearer token", "Codex 未登录或登录已过期，请在服务器上运行 codex login 后重试"},
+		{"You are not logged in. Run `codex login`.", "Codex 未登录或登录已过期，请在服务器上运行 codex login 后重试"},
+		{"Your access token could not be refreshed because your refresh token has expired", "Codex 未登录或登录已过期，请在服务器上运行 codex login 后重试"},
+		{"You've hit your usage limit.", "Codex 额度已用完，请检查账号用量或稍后再试"},
+		{"Rate limit reached for requests", "Codex 请求过于频繁，请稍后再试"},
+		{"context_length_exceeded", "会话上下文过长，请发送 /clear 后重新开始"},
+		{"thread not found", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := b.codexErrorHint("c1", tt.message); got != tt.want {
+			t.Errorf("codexErrorHint(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+
+	b.config.CommandPrefix = "!"
+	if got := b.codexErrorHint("c1", "context_length_exceeded"); got != "会话上下文过长，请发送 !clear 后重新开始" {
+		t.Errorf("expected the hint to use the command prefix, got %q", got)
+	}
+
+	b.setLanguage("c1", "en")
+	if got := b.codexErrorHint("c1", "Rate limit reached for requests"); got != englishMessages.CodexRateLimited {
+		t.Errorf("expected the English rate limit hint, got %q", got)
+	}
+}
+
+func TestCodexFailureText(t *testing.T) {
+	b, _ := newTestBridge(t)
+	authErr := fmt.Errorf("turn/start: %w", &codex.RPCError{Code: -32000, Message: "not logged in"})
+	if got := b.codexFailureText("c1", "发送请求失败", authErr); got != "❌ 发送请求失败：Codex 未登录或登录已过期，请在服务器上运行 codex login 后重试" {
+		t.Errorf("unexpected auth failure text: %q", got)
+	}
+
+	other := errors.New("request turn/start timed out")
+	if got := b.codexFailureText("c1", "发送请求失败", other); got != "❌ 发送请求失败: request turn/start timed out" {
+		t.Errorf("unknown errors should be shown as-is, got %q", got)
+	}
+
+	b.setLanguage("c1", "en")
+	if got := b.codexFailureText("c1", "Failed to send request", authErr); got != "❌ Failed to send request: "+englishMessages.CodexNotLoggedIn {
+		t.Errorf("unexpected English auth failure text: %q", got)
+	}
+}
@@ -0,0 +1,79 @@
+package bridge
+
+import (
+	"fmt"
+	"time"
+)
+
+// Supported values for Config.CodexDownPolicy.
+const (
+	// CodexDownReject replies Messages.CodexUnavailable and drops the
+	// message.
+	CodexDownReject = "reject"
+	// CodexDownWait restarts codex and keeps the message (and the chat's
+	// queue behind it) until codex is back.
+	CodexDownWait = "wait"
+)
+
+// codexRestartDelay is the pause between restart attempts under
+// CodexDownWait.
+var codexRestartDelay = 5 * time.Second
+
+// codexReady reports whether codex can take a turn for chatID. When the
+// app-server is down (crashed or mid-restart) it applies
+// Config.CodexDownPolicy; with CodexDownWait it blocks until codex is
+// restarted, the chat is cleared (gen changes) or the bridge stops.
+func (b *Bridge) codexReady(chatID string, state *ChatState, gen uint64, sendReply func(string) bool) bool {
+	switch b.config.CodexDownPolicy {
+	case CodexDownReject:
+		if b.codexClient.IsRunning() {
+			return true
+		}
+		fmt.Printf("[Bridge] Codex is down, rejecting message for %s\n", chatID)
+		sendReply(b.chatMessages(chatID).CodexUnavailable)
+		return false
+	case CodexDownWait:
+		for !b.codexClient.IsRunning() {
+			err := b.restartCodexIfDown()
+			if err == nil {
+				break
+			}
+			fmt.Printf("[Bridge] Failed to restart codex: %v\n", err)
+			b.recordError(chatID, "restart codex: %v", err)
+			select {
+			case <-time.After(codexRestartDelay):
+			case <-b.ctx.Done():
+				return false
+			}
+			state.mu.Lock()
+			stale := state.Gen != gen
+			state.mu.Unlock()
+			if stale {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// restartCodexIfDown starts a new app-server unless another caller already
+// brought codex back. Sessions are kept; their threads are resumed or
+// recreated on the next turn.
+func (b *Bridge) restartCodexIfDown() error {
+	b.codexMu.Lock()
+	defer b.codexMu.Unlock()
+	if b.codexClient.IsRunning() {
+		return nil
+	}
+	fmt.Println("[Bridge] Codex is down, restarting")
+	b.stopCodexClient()
+	client := b.newCodexClient(b.config.WorkingDir)
+	if err := b.startCodex(b.ctx, client); err != nil {
+		return err
+	}
+	b.codexClient = client
+	b.startEventProcessor(client)
+	return nil
+}
@@ -0,0 +1,65 @@
+package bridge
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// codexDownPolicy is a newTestBridge option applying policy to a codex
+// that is down and can't be restarted.
+func codexDownPolicy(policy string) func(*Bridge) {
+	return func(b *Bridge) {
+		b.config.CodexDownPolicy = policy
+		// A missing working dir makes restart attempts fail.
+		b.config.WorkingDir = filepath.Join(b.config.WorkingDir, "missing")
+	}
+}
+
+func TestProcessQueuedMessage_CodexDownRejects(t *testing.T) {
+	b, m := newTestBridge(t, codexDownPolicy(CodexDownReject))
+
+	b.processQueuedMessage("c1", &feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: "hi"}, 0)
+
+	if reply := findReplyText(m, "m1"); reply != defaultMessages.CodexUnavailable {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	if entry, _ := b.sessionStore.GetByChatID("c1"); entry != nil {
+		t.Fatalf("expected no thread to be created, got %+v", entry)
+	}
+}
+
+func TestProcessQueuedMessage_CodexDownWaitsForRestart(t *testing.T) {
+	b, m := newTestBridge(t, codexDownPolicy(CodexDownWait))
+	ctx, cancel := context.WithCancel(context.Background())
+	b.ctx = ctx
+	old := codexRestartDelay
+	codexRestartDelay = time.Hour
+	t.Cleanup(func() { codexRestartDelay = old })
+
+	done := make(chan struct{})
+	go func() {
+		b.processQueuedMessage("c1", &feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: "hi"}, 0)
+		close(done)
+	}()
+
+	// The message waits for codex instead of failing.
+	select {
+	case <-done:
+		t.Fatal("expected the message to wait while codex is down")
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	<-done
+
+	if len(m.SentMessages) != 0 {
+		t.Fatalf("expected no reply while waiting, got %+v", m.SentMessages)
+	}
+	if errs := b.formatRecentErrors("c1", false); !strings.Contains(errs, "restart codex") {
+		t.Fatalf("expected the failed restart in recent errors, got %q", errs)
+	}
+}
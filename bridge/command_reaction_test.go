@@ -1,15 +1,12 @@
 package bridge
 
 import (
-	"context"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
-	"github.com/anthropics/feishu-codex-bridge/codex"
 	"github.com/anthropics/feishu-codex-bridge/feishu"
-	"github.com/anthropics/feishu-codex-bridge/session"
 )
 
 func TestQueueCommand_AddsDoneReaction(t *testing.T) {
@@ -42,21 +39,7 @@ func TestQueueCommand_AddsDoneReaction(t *testing.T) {
 }
 
 func TestClearCommand_ReplyTextIsShort(t *testing.T) {
-	tmpDir := t.TempDir()
-	store, err := session.NewStore(filepath.Join(tmpDir, "sessions.db"), 60, -1)
-	if err != nil {
-		t.Fatalf("failed to create session store: %v", err)
-	}
-	t.Cleanup(func() { store.Close() })
-
-	m := &MockFeishuClient{}
-	b := &Bridge{
-		config:       Config{},
-		feishuClient: m,
-		chatQueues:   make(map[string]*chatQueue),
-		chatStates:   make(map[string]*ChatState),
-		sessionStore: store,
-	}
+	b, m := newTestBridge(t)
 
 	b.handleFeishuMessageV2(&feishu.Message{
 		ChatID:   "c1",
@@ -76,32 +59,9 @@ func TestClearCommand_ReplyTextIsShort(t *testing.T) {
 }
 
 func TestSwitchDirCommand_ReplyTextIsNewFormat(t *testing.T) {
-	tmpDir := t.TempDir()
-	store, err := session.NewStore(filepath.Join(tmpDir, "sessions.db"), 60, -1)
-	if err != nil {
-		t.Fatalf("failed to create session store: %v", err)
-	}
-	t.Cleanup(func() { store.Close() })
-
-	m := &MockFeishuClient{}
-	b := &Bridge{
-		config: Config{
-			WorkingDir:      tmpDir,
-			SessionDBPath:   filepath.Join(tmpDir, "sessions.db"),
-			SessionIdleMin:  60,
-			SessionResetHr:  -1,
-			FeishuAppID:     "test",
-			FeishuAppSecret: "test",
-		},
-		feishuClient: m,
-		chatQueues:   make(map[string]*chatQueue),
-		chatStates:   make(map[string]*ChatState),
-		sessionStore: store,
-		codexClient:  codex.NewClient(tmpDir, "gpt-5.2-codex"),
-		ctx:          context.Background(),
-	}
-
-	newDir := filepath.Join(tmpDir, "new")
+	useFakeCodex(t)
+	b, m := newTestBridge(t)
+	newDir := filepath.Join(b.config.WorkingDir, "new")
 	if err := os.MkdirAll(newDir, 0o755); err != nil {
 		t.Fatalf("failed to create dir: %v", err)
 	}
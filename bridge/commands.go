@@ -1,8 +1,9 @@
 package bridge
 
 import (
-	"path/filepath"
 	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
 )
 
 type Command struct {
@@ -15,49 +16,103 @@ const (
 	CommandShowDir   = "show_dir"
 	CommandHelp      = "help"
 	CommandClear     = "clear"
+	CommandNew       = "new"
+	CommandMore      = "more"
 	CommandQueue     = "queue"
 	CommandStatus    = "status"
+	CommandElapsed   = "elapsed"
+	CommandStats     = "stats"
+	CommandVersion   = "version"
 	CommandReset     = "reset"
+	CommandCleanup   = "cleanup"
+	CommandChanges   = "changes"
+	CommandCommit    = "commit"
+	CommandAsk       = "ask"
+	CommandExport    = "export"
+	CommandPause     = "pause"
+	CommandResume    = "resume"
+	CommandVerbose   = "verbose"
+	CommandEffort    = "effort"
+	CommandStream    = "stream"
+	CommandErrors    = "errors"
+	CommandBind      = "bind"
+	CommandLang      = "lang"
+	CommandModel     = "model"
+	CommandApprovals = "approvals"
 )
 
+// HelpArgText forces /help to reply with plain text instead of a rich-text post.
+const HelpArgText = "text"
+
+// DefaultCommandPrefix is the prefix commands use unless configured otherwise.
+const DefaultCommandPrefix = "/"
+
 func ParseCommand(content string) (Command, bool) {
+	return ParseCommandWithPrefix(content, DefaultCommandPrefix)
+}
+
+// ParseCommandWithPrefix parses content as a command that starts with prefix
+// (e.g. "!" for "!help"). An empty prefix means DefaultCommandPrefix.
+func ParseCommandWithPrefix(content, prefix string) (Command, bool) {
 	s := strings.TrimSpace(content)
 	if s == "" {
 		return Command{}, false
 	}
-
-	if s == "/help" || s == "/h" {
-		return Command{Kind: CommandHelp}, true
+	if prefix != "" && prefix != DefaultCommandPrefix {
+		if !strings.HasPrefix(s, prefix) {
+			return Command{}, false
+		}
+		s = DefaultCommandPrefix + strings.TrimPrefix(s, prefix)
 	}
 
-	if s == "/clear" || s == "/c" {
-		return Command{Kind: CommandClear}, true
+	for _, spec := range commandSpecs {
+		for _, alias := range spec.Aliases {
+			if s == alias {
+				return Command{Kind: spec.Kind}, true
+			}
+			if !spec.TakesArg || !strings.HasPrefix(s, alias+" ") {
+				continue
+			}
+			arg := strings.TrimSpace(strings.TrimPrefix(s, alias))
+			if spec.parseArg != nil {
+				var ok bool
+				if arg, ok = spec.parseArg(arg); !ok {
+					return Command{}, false
+				}
+			}
+			return Command{Kind: spec.Kind, Arg: arg}, true
+		}
 	}
 
-	if s == "/queue" || s == "/q" {
-		return Command{Kind: CommandQueue}, true
-	}
+	return Command{}, false
+}
 
-	if s == "/status" || s == "/s" {
-		return Command{Kind: CommandStatus}, true
+// withPrefix rewrites a "/" alias such as "/more" for Config.CommandPrefix,
+// so replies name commands the way users must type them.
+func (b *Bridge) withPrefix(alias string) string {
+	if b.config.CommandPrefix == "" {
+		return alias
 	}
+	return b.config.CommandPrefix + strings.TrimPrefix(alias, DefaultCommandPrefix)
+}
 
-	if s == "/reset" || s == "/r" {
-		return Command{Kind: CommandReset}, true
+// parseCommand applies the configured prefix and, for group chats, the
+// mention requirement before parsing msg as a command.
+func (b *Bridge) parseCommand(msg *feishu.Message) (Command, bool) {
+	if msg.ChatType == "group" && b.config.GroupCommandsRequireMention && !b.mentionsBot(msg) {
+		return Command{}, false
 	}
+	return ParseCommandWithPrefix(msg.Content, b.config.CommandPrefix)
+}
 
-	if s == "/pwd" {
-		return Command{Kind: CommandShowDir}, true
+func (b *Bridge) mentionsBot(msg *feishu.Message) bool {
+	if b.config.FeishuBotOpenID == "" {
+		return false
 	}
-
-	if strings.HasPrefix(s, "/cd ") {
-		arg := strings.TrimSpace(strings.TrimPrefix(s, "/cd "))
-		if arg == "" {
-			return Command{}, false
+	for _, id := range msg.Mentions {
+		if id == b.config.FeishuBotOpenID {
+			return true
 		}
-		cleaned := filepath.Clean(arg)
-		return Command{Kind: CommandSwitchDir, Arg: cleaned}, true
 	}
-
-	return Command{}, false
+	return false
 }
@@ -1,6 +1,10 @@
 package bridge
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
 
 func TestParseCommand_ShowDir(t *testing.T) {
 	for _, in := range []string{"/pwd", "  /pwd  "} {
@@ -26,6 +30,21 @@ func TestParseCommand_Help(t *testing.T) {
 	}
 }
 
+func TestParseCommand_HelpText(t *testing.T) {
+	for _, in := range []string{"/help text", "/help raw", "/h text", "  /help   text  "} {
+		cmd, ok := ParseCommand(in)
+		if !ok {
+			t.Fatalf("expected ok for %q", in)
+		}
+		if cmd.Kind != CommandHelp || cmd.Arg != HelpArgText {
+			t.Fatalf("unexpected command for %q: %+v", in, cmd)
+		}
+	}
+	if _, ok := ParseCommand("/help me fix this"); ok {
+		t.Fatalf("expected unknown /help arg to not be a command")
+	}
+}
+
 func TestParseCommand_Clear(t *testing.T) {
 	for _, in := range []string{"/clear", "  /clear  ", "/c"} {
 		cmd, ok := ParseCommand(in)
@@ -62,6 +81,68 @@ func TestParseCommand_Status(t *testing.T) {
 	}
 }
 
+func TestParseCommand_Stats(t *testing.T) {
+	cmd, ok := ParseCommand("  /stats ")
+	if !ok || cmd.Kind != CommandStats {
+		t.Fatalf("expected stats command, got %+v ok=%v", cmd, ok)
+	}
+}
+
+func TestParseCommand_Changes(t *testing.T) {
+	cmd, ok := ParseCommand("/changes")
+	if !ok || cmd.Kind != CommandChanges {
+		t.Fatalf("expected changes command, got %+v ok=%v", cmd, ok)
+	}
+}
+
+func TestParseCommand_Commit(t *testing.T) {
+	cmd, ok := ParseCommand("/commit")
+	if !ok || cmd.Kind != CommandCommit || cmd.Arg != "" {
+		t.Fatalf("expected bare commit command, got %+v ok=%v", cmd, ok)
+	}
+	cmd, ok = ParseCommand("/commit  fix login redirect ")
+	if !ok || cmd.Kind != CommandCommit || cmd.Arg != "fix login redirect" {
+		t.Fatalf("expected commit with message, got %+v ok=%v", cmd, ok)
+	}
+}
+
+func TestParseCommand_Ask(t *testing.T) {
+	cmd, ok := ParseCommand("/ask  what does main.go do? ")
+	if !ok || cmd.Kind != CommandAsk || cmd.Arg != "what does main.go do?" {
+		t.Fatalf("expected ask command, got %+v ok=%v", cmd, ok)
+	}
+	cmd, ok = ParseCommand("/ask")
+	if !ok || cmd.Kind != CommandAsk || cmd.Arg != "" {
+		t.Fatalf("expected bare ask command, got %+v ok=%v", cmd, ok)
+	}
+	if _, ok := ParseCommand("/asking"); ok {
+		t.Fatal("expected /asking to not be a command")
+	}
+}
+
+func TestParseCommand_Export(t *testing.T) {
+	cmd, ok := ParseCommand(" /export ")
+	if !ok || cmd.Kind != CommandExport {
+		t.Fatalf("expected export command, got %+v ok=%v", cmd, ok)
+	}
+}
+
+func TestParseCommand_PauseResume(t *testing.T) {
+	if cmd, ok := ParseCommand("/pause"); !ok || cmd.Kind != CommandPause {
+		t.Fatalf("expected pause command, got %+v ok=%v", cmd, ok)
+	}
+	if cmd, ok := ParseCommand(" /resume "); !ok || cmd.Kind != CommandResume {
+		t.Fatalf("expected resume command, got %+v ok=%v", cmd, ok)
+	}
+}
+
+func TestParseCommand_Cleanup(t *testing.T) {
+	cmd, ok := ParseCommand(" /cleanup ")
+	if !ok || cmd.Kind != CommandCleanup {
+		t.Fatalf("expected cleanup command, got %+v ok=%v", cmd, ok)
+	}
+}
+
 func TestParseCommand_Reset(t *testing.T) {
 	for _, in := range []string{"/reset", "  /reset  ", "/r"} {
 		cmd, ok := ParseCommand(in)
@@ -94,3 +175,128 @@ func TestParseCommand_WorkdirWithoutArg_NotCommand(t *testing.T) {
 		}
 	}
 }
+
+func TestParseCommandWithPrefix(t *testing.T) {
+	cmd, ok := ParseCommandWithPrefix("!help", "!")
+	if !ok || cmd.Kind != CommandHelp {
+		t.Fatalf("expected help with custom prefix, got %+v ok=%v", cmd, ok)
+	}
+	cmd, ok = ParseCommandWithPrefix("  !cd /tmp ", "!")
+	if !ok || cmd.Kind != CommandSwitchDir || cmd.Arg != "/tmp" {
+		t.Fatalf("expected cd with custom prefix, got %+v ok=%v", cmd, ok)
+	}
+	if _, ok := ParseCommandWithPrefix("/help", "!"); ok {
+		t.Fatal("default prefix should not match when a custom prefix is set")
+	}
+	if _, ok := ParseCommandWithPrefix("/help", ""); !ok {
+		t.Fatal("empty prefix should fall back to the default")
+	}
+}
+
+func TestParseCommand_GroupRequiresMention(t *testing.T) {
+	b := &Bridge{config: Config{FeishuBotOpenID: "ou_bot", GroupCommandsRequireMention: true}}
+
+	group := &feishu.Message{ChatType: "group", Content: "/status"}
+	if _, ok := b.parseCommand(group); ok {
+		t.Fatal("group command without mention should be ignored")
+	}
+	group.Mentions = []string{"ou_bot"}
+	if _, ok := b.parseCommand(group); !ok {
+		t.Fatal("group command with bot mention should be parsed")
+	}
+	if _, ok := b.parseCommand(&feishu.Message{ChatType: "p2p", Content: "/status"}); !ok {
+		t.Fatal("p2p commands should not require a mention")
+	}
+}
+
+func TestParseCommand_Verbose(t *testing.T) {
+	for in, want := range map[string]string{"/verbose": "", "/verbose on": "on", " /verbose OFF ": "off"} {
+		cmd, ok := ParseCommand(in)
+		if !ok || cmd.Kind != CommandVerbose || cmd.Arg != want {
+			t.Fatalf("expected verbose %q for %q, got %+v ok=%v", want, in, cmd, ok)
+		}
+	}
+	if _, ok := ParseCommand("/verbose please"); ok {
+		t.Fatal("expected unknown /verbose arg to not be a command")
+	}
+}
+
+func TestParseCommand_Effort(t *testing.T) {
+	for in, want := range map[string]string{"/effort": "", "/effort High": "high", " /effort  low ": "low", "/effort max": "max"} {
+		cmd, ok := ParseCommand(in)
+		if !ok || cmd.Kind != CommandEffort || cmd.Arg != want {
+			t.Fatalf("expected effort %q for %q, got %+v ok=%v", want, in, cmd, ok)
+		}
+	}
+}
+
+func TestParseCommand_Errors(t *testing.T) {
+	cmd, ok := ParseCommand(" /errors ")
+	if !ok || cmd.Kind != CommandErrors {
+		t.Fatalf("expected errors command, got %+v ok=%v", cmd, ok)
+	}
+}
+
+func TestParseCommand_Lang(t *testing.T) {
+	for in, want := range map[string]string{"/lang": "", "/lang EN": "en", " /lang  zh ": "zh"} {
+		cmd, ok := ParseCommand(in)
+		if !ok || cmd.Kind != CommandLang || cmd.Arg != want {
+			t.Fatalf("expected lang %q for %q, got %+v ok=%v", want, in, cmd, ok)
+		}
+	}
+}
+
+func TestParseCommand_Model(t *testing.T) {
+	for in, want := range map[string]string{"/model": "", "/model o3": "o3", " /model  gpt-5.2-codex ": "gpt-5.2-codex"} {
+		cmd, ok := ParseCommand(in)
+		if !ok || cmd.Kind != CommandModel || cmd.Arg != want {
+			t.Fatalf("expected model %q for %q, got %+v ok=%v", want, in, cmd, ok)
+		}
+	}
+}
+
+func TestParseCommand_Approvals(t *testing.T) {
+	for in, want := range map[string]string{"/approvals": "", "/approvals Ask": "ask", " /approvals  global  readonly ": "global  readonly"} {
+		cmd, ok := ParseCommand(in)
+		if !ok || cmd.Kind != CommandApprovals || cmd.Arg != want {
+			t.Fatalf("expected approvals %q for %q, got %+v ok=%v", want, in, cmd, ok)
+		}
+	}
+}
+
+func TestParseCommand_SpecParity(t *testing.T) {
+	seen := make(map[string]string)
+	for _, c := range commandSpecs {
+		for _, alias := range c.Aliases {
+			if other, dup := seen[alias]; dup {
+				t.Errorf("alias %q registered for both %s and %s", alias, other, c.Kind)
+			}
+			seen[alias] = c.Kind
+
+			if cmd, ok := ParseCommand(alias); !ok || cmd.Kind != c.Kind {
+				t.Errorf("%q: expected %s, got %+v ok=%v", alias, c.Kind, cmd, ok)
+			}
+
+			if c.TakesArg {
+				arg := "/tmp"
+				switch c.Kind {
+				case CommandHelp:
+					arg = "text"
+				case CommandVerbose:
+					arg = "on"
+				}
+				in := alias + " " + arg
+				if cmd, ok := ParseCommand(in); !ok || cmd.Kind != c.Kind || cmd.Arg == "" {
+					t.Errorf("%q: expected %s with an argument, got %+v ok=%v", in, c.Kind, cmd, ok)
+				}
+			} else if cmd, ok := ParseCommand(alias + " extra"); ok {
+				t.Errorf("%q takes no argument but parsed %+v", alias+" extra", cmd)
+			}
+		}
+		for _, lang := range []string{LanguageZH, LanguageEN} {
+			if c.Description[lang] == "" {
+				t.Errorf("%s has no %s description", c.Aliases[0], lang)
+			}
+		}
+	}
+}
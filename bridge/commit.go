@@ -0,0 +1,94 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// maxCommitPreviewFiles caps how many paths the /commit confirmation lists.
+const maxCommitPreviewFiles = 20
+
+// defaultCommitMessage summarizes files for a /commit without a message.
+func defaultCommitMessage(files []string) string {
+	const maxNamed = 3
+	if len(files) <= maxNamed {
+		return "Update " + strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("Update %s and %d more files", strings.Join(files[:maxNamed], ", "), len(files)-maxNamed)
+}
+
+// handleCommitCommand asks for confirmation via reaction, then stages and
+// commits every change in the working directory. It blocks until the user
+// responds, so callers run it in its own goroutine.
+func (b *Bridge) handleCommitCommand(msg *feishu.Message, message string) {
+	replyInThread := msg.ChatType == "group"
+	reply := func(text string) {
+		if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+			_ = b.feishuClient.SendText(msg.ChatID, text)
+		}
+	}
+
+	msgs := b.chatMessages(msg.ChatID)
+	dir := b.config.WorkingDir
+	if !isGitRepo(dir) {
+		reply(msgs.CommitNotRepo)
+		return
+	}
+	files, err := gitChangedFiles(dir)
+	if err != nil {
+		reply(fmt.Sprintf(msgs.CommitStatusFailed, err))
+		return
+	}
+	if len(files) == 0 {
+		reply(msgs.CommitNothing)
+		return
+	}
+	if message == "" {
+		message = defaultCommitMessage(files)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, msgs.CommitConfirm, len(files))
+	for i, f := range files {
+		if i == maxCommitPreviewFiles {
+			sb.WriteString("\n")
+			fmt.Fprintf(&sb, msgs.ListMore, len(files)-maxCommitPreviewFiles)
+			break
+		}
+		sb.WriteString("\n- ")
+		sb.WriteString(f)
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, msgs.CommitConfirmFooter, message)
+
+	promptID, err := b.postForConfirmation(msg.ChatID, msg.MsgID, sb.String(), replyInThread)
+	if err != nil {
+		fmt.Printf("[Bridge] Failed to post commit confirmation: %v\n", err)
+		return
+	}
+
+	requester := ""
+	if msg.Sender != nil {
+		requester = msg.Sender.SenderID
+	}
+	switch b.awaitReaction(promptID, requester) {
+	case "accept":
+	case "decline":
+		reply(msgs.CommitCancelled)
+		return
+	case "timeout":
+		reply(msgs.CommitTimeout)
+		return
+	default:
+		return
+	}
+
+	hash, err := gitCommitAll(dir, message)
+	if err != nil {
+		reply(fmt.Sprintf(msgs.CommitFailed, err))
+		return
+	}
+	reply(fmt.Sprintf(msgs.CommitDone, hash))
+}
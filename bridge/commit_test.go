@@ -0,0 +1,106 @@
+package bridge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// inRepo is a newTestBridge option for commit tests in dir.
+func inRepo(dir string) func(*Bridge) {
+	return func(b *Bridge) {
+		b.config.WorkingDir = dir
+		b.config.ApprovalTimeout = time.Minute
+	}
+}
+
+func runCommit(b *Bridge, message string) func() {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b.handleCommitCommand(&feishu.Message{ChatID: "c1", MsgID: "m1", ChatType: "p2p", Sender: &feishu.Sender{SenderID: "ou_user"}}, message)
+	}()
+	return wg.Wait
+}
+
+func TestDefaultCommitMessage(t *testing.T) {
+	if got := defaultCommitMessage([]string{"a.go", "b.go"}); got != "Update a.go, b.go" {
+		t.Fatalf("unexpected message: %q", got)
+	}
+	if got := defaultCommitMessage([]string{"a", "b", "c", "d", "e"}); got != "Update a, b, c and 2 more files" {
+		t.Fatalf("unexpected message: %q", got)
+	}
+}
+
+func TestCommitCommand_CommitsAfterApproval(t *testing.T) {
+	dir := initGitRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, m := newTestBridge(t, inRepo(dir))
+
+	wait := runCommit(b, "add main")
+	promptID := waitForPendingApproval(t, b)
+	b.handleFeishuReaction(&feishu.ReactionEvent{MsgID: promptID, EmojiType: approveEmoji, OperatorType: "user", OperatorID: "ou_user"})
+	wait()
+
+	hash, err := runGit(dir, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, _ := runGit(dir, "log", "-1", "--format=%s"); subject != "add main" {
+		t.Fatalf("unexpected commit subject %q", subject)
+	}
+	if last := m.SentMessages[len(m.SentMessages)-1].Text; last != "✅ 已提交："+hash {
+		t.Fatalf("expected commit hash reply, got %q", last)
+	}
+	if !strings.Contains(m.SentMessages[0].Text, "main.go") {
+		t.Fatalf("expected confirmation to list main.go, got %q", m.SentMessages[0].Text)
+	}
+}
+
+func TestCommitCommand_DeclineKeepsChanges(t *testing.T) {
+	dir := initGitRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, m := newTestBridge(t, inRepo(dir))
+
+	wait := runCommit(b, "")
+	promptID := waitForPendingApproval(t, b)
+	b.handleFeishuReaction(&feishu.ReactionEvent{MsgID: promptID, EmojiType: declineEmoji, OperatorType: "user", OperatorID: "ou_user"})
+	wait()
+
+	if files, _ := gitChangedFiles(dir); len(files) != 1 {
+		t.Fatalf("expected change to stay uncommitted, got %v", files)
+	}
+	if last := m.SentMessages[len(m.SentMessages)-1].Text; last != "已取消提交" {
+		t.Fatalf("expected cancel reply, got %q", last)
+	}
+}
+
+func TestCommitCommand_RefusesWithoutChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{"not a repo", t.TempDir(), "❌ 当前工作目录不是 git 仓库"},
+		{"clean repo", initGitRepo(t), "没有需要提交的改动"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, m := newTestBridge(t, inRepo(tt.dir))
+			runCommit(b, "msg")()
+			if len(m.SentMessages) != 1 || m.SentMessages[0].Text != tt.want {
+				t.Fatalf("expected %q, got %+v", tt.want, m.SentMessages)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package bridge
+
+import "context"
+
+// acquireTurnSlot blocks until fewer than Config.MaxConcurrentTurns chat
+// turns are running, so a burst of messages across chats queues here
+// instead of all hitting codex at once. It returns a release func, or false
+// if ctx ended while waiting. Without a limit it never blocks.
+func (b *Bridge) acquireTurnSlot(ctx context.Context) (release func(), ok bool) {
+	if b.turnSlots == nil {
+		return func() {}, true
+	}
+	select {
+	case b.turnSlots <- struct{}{}:
+		return b.releaseTurnSlot, true
+	default:
+	}
+
+	b.stats.turnsWaiting.Add(1)
+	defer b.stats.turnsWaiting.Add(-1)
+	select {
+	case b.turnSlots <- struct{}{}:
+		return b.releaseTurnSlot, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+func (b *Bridge) releaseTurnSlot() {
+	<-b.turnSlots
+}
+
+// pendingMessageCount is the number of queued messages across all chats.
+func (b *Bridge) pendingMessageCount() int {
+	b.queuesMu.Lock()
+	qs := make([]*chatQueue, 0, len(b.chatQueues))
+	for _, q := range b.chatQueues {
+		qs = append(qs, q)
+	}
+	b.queuesMu.Unlock()
+
+	n := 0
+	for _, q := range qs {
+		q.mu.Lock()
+		n += len(q.pending)
+		q.mu.Unlock()
+	}
+	return n
+}
+
+// activeTurnCount is the number of codex turns in flight, including /ask.
+func (b *Bridge) activeTurnCount() int {
+	b.activeMu.Lock()
+	defer b.activeMu.Unlock()
+	return len(b.activeThreads)
+}
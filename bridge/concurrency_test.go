@@ -0,0 +1,141 @@
+package bridge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestAcquireTurnSlot_WaitsAtLimit(t *testing.T) {
+	b := &Bridge{turnSlots: make(chan struct{}, 1)}
+
+	release, ok := b.acquireTurnSlot(context.Background())
+	if !ok {
+		t.Fatal("expected first slot")
+	}
+
+	acquired := make(chan func())
+	go func() {
+		r, _ := b.acquireTurnSlot(context.Background())
+		acquired <- r
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for b.Stats().WaitingTurns != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected second turn to wait for a slot")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	release()
+	select {
+	case r := <-acquired:
+		r()
+	case <-time.After(time.Second):
+		t.Fatal("expected waiting turn to get the released slot")
+	}
+	if got := b.Stats().WaitingTurns; got != 0 {
+		t.Fatalf("expected no waiting turns, got %d", got)
+	}
+}
+
+func TestAcquireTurnSlot_CanceledWhileWaiting(t *testing.T) {
+	b := &Bridge{turnSlots: make(chan struct{}, 1)}
+	b.turnSlots <- struct{}{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := b.acquireTurnSlot(ctx); ok {
+		t.Fatal("expected canceled wait to fail")
+	}
+}
+
+func TestAcquireTurnSlot_Unlimited(t *testing.T) {
+	b := &Bridge{}
+	for i := 0; i < 3; i++ {
+		if _, ok := b.acquireTurnSlot(context.Background()); !ok {
+			t.Fatal("expected unlimited slots")
+		}
+	}
+}
+
+func TestStats_LoadCounts(t *testing.T) {
+	b := &Bridge{
+		config:        Config{MaxConcurrentTurns: 2},
+		chatQueues:    map[string]*chatQueue{"c1": {pending: make([]*feishu.Message, 2)}, "c2": {pending: make([]*feishu.Message, 1)}},
+		activeThreads: map[string]struct{}{"t1": {}},
+	}
+	snap := b.Stats()
+	if snap.PendingMessages != 3 || snap.ActiveTurns != 1 || snap.MaxConcurrentTurns != 2 {
+		t.Fatalf("unexpected load counts: %+v", snap)
+	}
+	if out := b.formatStats("c1"); !strings.Contains(out, "排队消息：3\n进行中：1（上限 2，等待 0）") {
+		t.Fatalf("unexpected stats output: %q", out)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	b := &Bridge{
+		config:        Config{AdminAPIToken: "tok"},
+		activeThreads: map[string]struct{}{"t1": {}},
+	}
+	b.stats.turnsStarted.Add(4)
+
+	rec := httptest.NewRecorder()
+	b.webhookHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without the admin token, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer tok")
+	rec = httptest.NewRecorder()
+	b.webhookHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"bridge_turns_started_total 4\n", "bridge_active_turns 1\n", "# TYPE bridge_pending_messages gauge\n"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in metrics:\n%s", want, body)
+		}
+	}
+}
+
+func TestProcessQueuedMessage_WaitsForSlotBeforeProcessing(t *testing.T) {
+	b, m := newTestBridge(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	b.ctx = ctx
+	b.turnSlots = make(chan struct{}, 1)
+	b.turnSlots <- struct{}{}
+
+	done := make(chan struct{})
+	go func() {
+		b.processQueuedMessage("c1", &feishu.Message{ChatID: "c1", MsgID: "m1", MsgType: "text", Content: "hi"}, 0)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for b.Stats().WaitingTurns != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the message to wait for a slot")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	state := b.getChatState("c1")
+	state.mu.Lock()
+	processing := state.Processing
+	state.mu.Unlock()
+	if processing || len(m.Reactions) != 0 {
+		t.Fatalf("expected no processing state or reaction while waiting, processing=%v reactions=%+v", processing, m.Reactions)
+	}
+
+	cancel()
+	<-done
+}
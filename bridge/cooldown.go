@@ -0,0 +1,64 @@
+package bridge
+
+import "time"
+
+// maxCommandCooldownEntries bounds the cooldown map; expired entries are
+// pruned when it grows past this.
+const maxCommandCooldownEntries = 1024
+
+// cooldownCommands lists the command kinds that restart codex, start a turn
+// or change the chat's state or working tree; read-only commands such as
+// /status and /more are never rate limited.
+var cooldownCommands = map[string]bool{
+	CommandReset:     true,
+	CommandSwitchDir: true,
+	CommandClear:     true,
+	CommandNew:       true,
+	CommandModel:     true,
+	CommandAsk:       true,
+	CommandCommit:    true,
+	CommandExport:    true,
+	CommandBind:      true,
+	CommandCleanup:   true,
+}
+
+// commandCooldown returns the minimum gap between two uses of a command
+// kind in one chat: Config.ResetCooldown for /reset, Config.CommandCooldown
+// for the other cooldownCommands and 0 (no cooldown) for the rest.
+func (b *Bridge) commandCooldown(kind string) time.Duration {
+	if kind == CommandReset {
+		return b.config.ResetCooldown
+	}
+	if !cooldownCommands[kind] {
+		return 0
+	}
+	return b.config.CommandCooldown
+}
+
+// commandAllowed reports whether chatID may run a command of kind at now,
+// and if so records the use.
+func (b *Bridge) commandAllowed(chatID, kind string, now time.Time) bool {
+	cooldown := b.commandCooldown(kind)
+	if cooldown <= 0 {
+		return true
+	}
+	key := chatID + "\x00" + kind
+
+	b.commandUsesMu.Lock()
+	defer b.commandUsesMu.Unlock()
+	if last, ok := b.commandUses[key]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+	if b.commandUses == nil {
+		b.commandUses = make(map[string]time.Time)
+	}
+	if len(b.commandUses) >= maxCommandCooldownEntries {
+		for k, last := range b.commandUses {
+			if now.Sub(last) >= b.config.ResetCooldown && now.Sub(last) >= b.config.CommandCooldown {
+				delete(b.commandUses, k)
+			}
+		}
+	}
+	b.commandUses[key] = now
+	return true
+}
@@ -0,0 +1,70 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestCommandAllowed_Cooldown(t *testing.T) {
+	b := &Bridge{config: Config{CommandCooldown: 2 * time.Second, ResetCooldown: 30 * time.Second}}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if !b.commandAllowed("c1", CommandClear, now) {
+		t.Fatal("first /clear should be allowed")
+	}
+	if b.commandAllowed("c1", CommandClear, now.Add(time.Second)) {
+		t.Fatal("repeated /clear within the cooldown should be rejected")
+	}
+	if !b.commandAllowed("c1", CommandNew, now.Add(time.Second)) {
+		t.Fatal("other commands have their own cooldown")
+	}
+	if !b.commandAllowed("c2", CommandClear, now.Add(time.Second)) {
+		t.Fatal("other chats have their own cooldown")
+	}
+	if !b.commandAllowed("c1", CommandClear, now.Add(3*time.Second)) {
+		t.Fatal("/clear should be allowed after the cooldown")
+	}
+
+	if !b.commandAllowed("c1", CommandReset, now) {
+		t.Fatal("first /reset should be allowed")
+	}
+	if b.commandAllowed("c1", CommandReset, now.Add(10*time.Second)) {
+		t.Fatal("/reset has a longer cooldown")
+	}
+	if !b.commandAllowed("c1", CommandReset, now.Add(31*time.Second)) {
+		t.Fatal("/reset should be allowed after its cooldown")
+	}
+
+	for i := 0; i < 3; i++ {
+		if !b.commandAllowed("c1", CommandStatus, now) || !b.commandAllowed("c1", CommandMore, now) {
+			t.Fatal("read-only commands should never be rate limited")
+		}
+	}
+
+	b.config = Config{}
+	for i := 0; i < 3; i++ {
+		if !b.commandAllowed("c1", CommandClear, now) {
+			t.Fatal("a zero cooldown should never reject")
+		}
+	}
+}
+
+func TestCommandCooldown_RepliesTooFrequent(t *testing.T) {
+	b, m := newTestBridge(t)
+	b.config.CommandCooldown = time.Minute
+
+	b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: "/clear"})
+	b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m2", MsgType: "text", Content: "/clear"})
+
+	if reply := findReplyText(m, "m2"); reply != defaultMessages.CommandTooFrequent {
+		t.Fatalf("expected a too-frequent reply, got %q", reply)
+	}
+
+	b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m3", MsgType: "text", Content: "/pwd"})
+	b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m4", MsgType: "text", Content: "/pwd"})
+	if reply := findReplyText(m, "m4"); reply == defaultMessages.CommandTooFrequent {
+		t.Fatal("read-only commands should not be rate limited")
+	}
+}
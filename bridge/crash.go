@@ -0,0 +1,100 @@
+package bridge
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// superviseCodex waits for client's app-server to exit or fail a keep-alive
+// ping. If it crashed while still being the bridge's client, chats with an
+// in-flight turn are told (Messages.CodexCrashed) and codex is restarted,
+// retrying every codexRestartDelay until it comes back or the bridge stops.
+func (b *Bridge) superviseCodex(client *codex.Client) {
+	select {
+	case <-client.Exited():
+	case <-client.Unhealthy():
+	case <-b.ctx.Done():
+		return
+	}
+	if !client.Crashed() {
+		return
+	}
+	b.codexMu.Lock()
+	current := b.codexClient == client
+	b.codexMu.Unlock()
+	if !current {
+		return
+	}
+
+	fmt.Println("[Bridge] Codex app-server exited or stopped responding")
+	b.notifyCodexCrash()
+	b.abortSideTurns()
+	for {
+		err := b.restartCodexIfDown()
+		if err == nil {
+			return
+		}
+		fmt.Printf("[Bridge] Failed to restart codex: %v\n", err)
+		select {
+		case <-time.After(codexRestartDelay):
+		case <-b.ctx.Done():
+			return
+		}
+	}
+}
+
+// notifyCodexCrash ends the in-flight turn of every chat in activeThreads:
+// the processing reaction is removed, the chat gets Messages.CodexCrashed
+// and its worker is released to take the next queued message. The chat's
+// thread is forgotten in memory so the next turn resumes it from the
+// session store on the new app-server.
+func (b *Bridge) notifyCodexCrash() {
+	b.activeMu.Lock()
+	threads := make([]string, 0, len(b.activeThreads))
+	for threadID := range b.activeThreads {
+		threads = append(threads, threadID)
+	}
+	b.activeThreads = make(map[string]struct{})
+	b.loadedThreads = nil
+	b.activeMu.Unlock()
+
+	for _, threadID := range threads {
+		chatID := b.findChatByThread(threadID)
+		if chatID == "" {
+			continue
+		}
+		b.finishChatLiveOutputs(chatID)
+
+		state := b.getChatState(chatID)
+		state.mu.Lock()
+		msgID := state.MsgID
+		reactionID := state.ProcessingReactionID
+		replyInThread := state.ChatType == "group"
+		done := state.done
+		state.done = nil
+		state.Processing = false
+		state.ProcessingReactionID = ""
+		state.ThreadID = ""
+		state.TurnID = ""
+		state.TurnStartedAt = time.Time{}
+		state.LastItem = ""
+		state.Buffer.Reset()
+		state.FinalText = ""
+		state.FinalTurnID = ""
+		images := takeTurnImagesLocked(state)
+		state.mu.Unlock()
+		b.removeImages(images)
+
+		b.recordError(chatID, "codex exited during turn in thread %s", threadID)
+		if msgID != "" && reactionID != "" {
+			_ = b.feishuClient.RemoveReaction(msgID, reactionID)
+		}
+		text := b.chatMessages(chatID).CodexCrashed
+		b.replyText(chatID, msgID, text, replyInThread)
+		if done != nil {
+			close(done)
+		}
+	}
+}
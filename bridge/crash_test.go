@@ -0,0 +1,63 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotifyCodexCrash(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		feishuClient:  m,
+		chatStates:    make(map[string]*ChatState),
+		activeThreads: map[string]struct{}{"thread-1": {}},
+	}
+	state := b.getChatState("chat1")
+	done := make(chan struct{})
+	state.mu.Lock()
+	state.ThreadID = "thread-1"
+	state.TurnID = "turn-1"
+	state.MsgID = "om_1"
+	state.ChatType = "group"
+	state.Processing = true
+	state.ProcessingReactionID = "reaction-1"
+	state.done = done
+	state.Buffer.WriteString("partial")
+	state.mu.Unlock()
+
+	// A chat without an in-flight turn is left alone.
+	idle := b.getChatState("chat2")
+	idle.mu.Lock()
+	idle.ThreadID = "thread-2"
+	idle.mu.Unlock()
+
+	b.notifyCodexCrash()
+
+	select {
+	case <-done:
+	default:
+		t.Fatal("expected the waiting turn to be released")
+	}
+	if text := findReplyText(m, "om_1"); !strings.Contains(text, "意外退出") {
+		t.Fatalf("expected crash notice in reply, got %q (sent %+v)", text, m.SentMessages)
+	}
+	if len(m.Reactions) != 1 || !m.Reactions[0].IsRemove || m.Reactions[0].ReactionID != "reaction-1" {
+		t.Errorf("expected the processing reaction to be removed, got %+v", m.Reactions)
+	}
+	state.mu.Lock()
+	if state.Processing || state.ThreadID != "" || state.done != nil || state.Buffer.Len() != 0 {
+		t.Errorf("state not reset: processing=%v thread=%q", state.Processing, state.ThreadID)
+	}
+	state.mu.Unlock()
+	if len(b.activeThreads) != 0 {
+		t.Errorf("expected active threads to be cleared, got %v", b.activeThreads)
+	}
+	if len(m.SentMessages) != 1 {
+		t.Errorf("expected only the affected chat to be notified, got %+v", m.SentMessages)
+	}
+	idle.mu.Lock()
+	if idle.ThreadID != "thread-2" {
+		t.Errorf("idle chat was reset")
+	}
+	idle.mu.Unlock()
+}
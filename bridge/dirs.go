@@ -0,0 +1,86 @@
+package bridge
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// dirAllowed reports whether dir is one of roots or inside one of them.
+// Paths are compared after Abs/Clean and symlink resolution, and only at
+// path-element boundaries, so /srv/app does not admit /srv/app-evil and a
+// symlink inside a root can't point outside it. An empty roots list allows
+// everything.
+func dirAllowed(dir string, roots []string) bool {
+	if len(roots) == 0 {
+		return true
+	}
+	target := resolveDir(dir)
+	for _, root := range roots {
+		if root = strings.TrimSpace(root); root == "" {
+			continue
+		}
+		r := resolveDir(root)
+		if target == r {
+			return true
+		}
+		rel, err := filepath.Rel(r, target)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveDir returns the absolute, cleaned, symlink-free form of dir, or
+// the best it can when dir doesn't exist.
+func resolveDir(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = filepath.Clean(dir)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
+
+// threadDirMismatch reports whether threadID was created under a different
+// working directory than the current one. Threads the current app-server
+// already started or resumed are not checked again, and threads whose cwd
+// can't be read are treated as matching so a flaky thread/resume doesn't
+// drop context.
+func (b *Bridge) threadDirMismatch(ctx context.Context, client codex.CodexClient, threadID string) bool {
+	if b.threadLoaded(threadID) {
+		return false
+	}
+	thread, err := client.ThreadResume(ctx, threadID)
+	if err != nil || thread == nil {
+		return false
+	}
+	b.markThreadLoaded(threadID)
+	if thread.Cwd == "" {
+		return false
+	}
+	return resolveDir(thread.Cwd) != resolveDir(b.config.WorkingDir)
+}
+
+// markThreadLoaded records that the current app-server has threadID loaded.
+// The set is dropped whenever codex restarts.
+func (b *Bridge) markThreadLoaded(threadID string) {
+	b.activeMu.Lock()
+	defer b.activeMu.Unlock()
+	if b.loadedThreads == nil {
+		b.loadedThreads = make(map[string]struct{})
+	}
+	b.loadedThreads[threadID] = struct{}{}
+}
+
+func (b *Bridge) threadLoaded(threadID string) bool {
+	b.activeMu.Lock()
+	defer b.activeMu.Unlock()
+	_, ok := b.loadedThreads[threadID]
+	return ok
+}
@@ -0,0 +1,115 @@
+package bridge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirAllowed(t *testing.T) {
+	base := t.TempDir()
+	allowed := filepath.Join(base, "allowed")
+	for _, d := range []string{"allowed/sub", "allowed-evil", "other"} {
+		if err := os.MkdirAll(filepath.Join(base, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	roots := []string{allowed + "/"}
+
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{allowed, true},
+		{filepath.Join(allowed, "sub"), true},
+		{filepath.Join(allowed, "sub", ".."), true},
+		{filepath.Join(base, "allowed-evil"), false},
+		{filepath.Join(allowed, "..", "other"), false},
+		{base, false},
+	}
+	for _, tt := range tests {
+		if got := dirAllowed(tt.dir, roots); got != tt.want {
+			t.Errorf("dirAllowed(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+	if !dirAllowed(filepath.Join(base, "other"), nil) {
+		t.Error("expected an empty allowlist to allow everything")
+	}
+}
+
+func TestDirAllowed_SymlinkEscape(t *testing.T) {
+	base := t.TempDir()
+	allowed := filepath.Join(base, "allowed")
+	outside := filepath.Join(base, "outside")
+	for _, d := range []string{allowed, outside} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := filepath.Join(allowed, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if dirAllowed(link, []string{allowed}) {
+		t.Fatal("expected a symlink pointing outside the root to be rejected")
+	}
+}
+
+func TestSwitchWorkingDir_RejectsOutsideAllowlist(t *testing.T) {
+	base := t.TempDir()
+	allowed := filepath.Join(base, "allowed")
+	if err := os.MkdirAll(allowed, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	b := &Bridge{
+		config:        Config{WorkingDir: allowed, AllowedDirs: []string{allowed}},
+		activeThreads: make(map[string]struct{}),
+	}
+
+	err := b.switchWorkingDir("c1", base)
+	if err == nil || !strings.Contains(err.Error(), "目录不在允许范围内") {
+		t.Fatalf("expected allowlist rejection, got %v", err)
+	}
+	if b.config.WorkingDir != allowed {
+		t.Fatalf("working dir changed to %q", b.config.WorkingDir)
+	}
+}
+
+func TestThreadDirMismatch(t *testing.T) {
+	b := &Bridge{config: Config{WorkingDir: t.TempDir()}}
+	ctx := context.Background()
+
+	elsewhere := &MockCodexClient{ThreadCwd: t.TempDir()}
+	if !b.threadDirMismatch(ctx, elsewhere, "t1") {
+		t.Fatal("expected a thread from another directory to mismatch")
+	}
+	same := &MockCodexClient{ThreadCwd: b.config.WorkingDir}
+	if b.threadDirMismatch(ctx, same, "t2") {
+		t.Fatal("expected a thread from the working directory to match")
+	}
+	if b.threadDirMismatch(ctx, &MockCodexClient{}, "t3") {
+		t.Fatal("expected a thread without a cwd to match")
+	}
+
+	// A thread this app-server already started or resumed isn't checked again.
+	b.markThreadLoaded("t4")
+	if b.threadDirMismatch(ctx, elsewhere, "t4") {
+		t.Fatal("expected a loaded thread to be trusted")
+	}
+	if len(elsewhere.ResumedThreads) != 1 {
+		t.Fatalf("expected one thread/resume, got %v", elsewhere.ResumedThreads)
+	}
+
+	// Once resumed, t2 isn't resumed again; after a codex restart it is.
+	b.threadDirMismatch(ctx, same, "t2")
+	if len(same.ResumedThreads) != 1 {
+		t.Fatalf("expected t2 to be resumed once, got %v", same.ResumedThreads)
+	}
+	b.notifyCodexCrash()
+	b.threadDirMismatch(ctx, same, "t2")
+	if len(same.ResumedThreads) != 2 {
+		t.Fatalf("expected t2 to be checked again after a restart, got %v", same.ResumedThreads)
+	}
+}
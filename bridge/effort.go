@@ -0,0 +1,67 @@
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// reasoningEfforts are the values /effort accepts.
+var reasoningEfforts = map[string]bool{"low": true, "medium": true, "high": true}
+
+// loadChatEfforts restores the reasoning efforts chats chose with /effort
+// before a restart.
+func (b *Bridge) loadChatEfforts() {
+	efforts, err := b.sessionStore.ChatEfforts()
+	if err != nil {
+		fmt.Printf("[Bridge] Failed to load chat efforts: %v\n", err)
+		return
+	}
+	for chatID, effort := range efforts {
+		if !reasoningEfforts[effort] {
+			continue
+		}
+		state := b.getChatState(chatID)
+		state.mu.Lock()
+		state.Effort = effort
+		state.mu.Unlock()
+	}
+}
+
+// setEffort handles /effort: an empty arg reports the chat's current
+// reasoning effort, otherwise arg is validated and stored for later turns.
+func (b *Bridge) setEffort(chatID, arg string) string {
+	state := b.getChatState(chatID)
+	if arg == "" {
+		state.mu.Lock()
+		effort := state.Effort
+		state.mu.Unlock()
+		if effort == "" {
+			effort = b.chatMessages(chatID).EffortDefault
+		}
+		return fmt.Sprintf(b.chatMessages(chatID).EffortCurrent, effort, b.withPrefix("/effort"))
+	}
+	if !reasoningEfforts[arg] {
+		return fmt.Sprintf(b.chatMessages(chatID).EffortUsage, b.withPrefix("/effort"))
+	}
+	if err := b.sessionStore.SetEffort(chatID, arg); err != nil {
+		fmt.Printf("[Bridge] Failed to persist effort for %s: %v\n", chatID, err)
+	}
+	state.mu.Lock()
+	state.Effort = arg
+	state.mu.Unlock()
+	return fmt.Sprintf(b.chatMessages(chatID).EffortSet, arg)
+}
+
+// turnOptions returns the per-turn overrides for chatID, or nil if none
+// are set.
+func (b *Bridge) turnOptions(chatID string) *codex.TurnOptions {
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	effort := state.Effort
+	state.mu.Unlock()
+	if effort == "" {
+		return nil
+	}
+	return &codex.TurnOptions{Effort: effort}
+}
@@ -0,0 +1,35 @@
+package bridge
+
+import "testing"
+
+func TestSetEffort(t *testing.T) {
+	b, _ := newTestBridge(t)
+
+	if got := b.setEffort("c1", ""); got != "当前推理强度：默认（可用 /effort low|medium|high 修改）" {
+		t.Fatalf("unexpected current effort reply: %q", got)
+	}
+	if got := b.setEffort("c1", "max"); got != "⚠️ 用法：/effort <low|medium|high>" {
+		t.Fatalf("expected usage for invalid effort, got %q", got)
+	}
+	b.config.CommandPrefix = "!"
+	if got := b.setEffort("c1", "max"); got != "⚠️ 用法：!effort <low|medium|high>" {
+		t.Fatalf("expected the configured prefix in usage, got %q", got)
+	}
+	b.config.CommandPrefix = ""
+	if b.turnOptions("c1") != nil {
+		t.Fatal("expected no turn options before /effort")
+	}
+	if got := b.setEffort("c1", "high"); got != "✅ 推理强度已设为 high，从下一轮开始生效" {
+		t.Fatalf("unexpected set reply: %q", got)
+	}
+	if opts := b.turnOptions("c1"); opts == nil || opts.Effort != "high" {
+		t.Fatalf("expected high effort options, got %+v", opts)
+	}
+
+	// The effort survives a restart.
+	restarted, _ := newTestBridge(t, restartOf(b))
+	restarted.loadChatEfforts()
+	if opts := restarted.turnOptions("c1"); opts == nil || opts.Effort != "high" {
+		t.Fatalf("expected high effort after a restart, got %+v", opts)
+	}
+}
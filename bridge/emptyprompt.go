@@ -0,0 +1,27 @@
+package bridge
+
+import (
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// fillEmptyPrompt handles a message without text for codex. An image-only
+// message (no text, or just feishu.ImageContent) gets Config.ImagePrompt
+// instead, or Messages.ImagePrompt when that's empty; a message with
+// neither text nor images is answered with Messages.EmptyPrompt and
+// dropped (false).
+func (b *Bridge) fillEmptyPrompt(chatID string, msg *feishu.Message, sendReply func(string) bool) (string, bool) {
+	text := strings.TrimSpace(msg.Content)
+	if text != "" && text != feishu.ImageContent {
+		return msg.Content, true
+	}
+	if len(msg.ImageKeys) == 0 {
+		sendReply(b.chatMessages(chatID).EmptyPrompt)
+		return "", false
+	}
+	if b.config.ImagePrompt != "" {
+		return b.config.ImagePrompt, true
+	}
+	return b.chatMessages(chatID).ImagePrompt, true
+}
@@ -0,0 +1,64 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestFillEmptyPrompt(t *testing.T) {
+	b := &Bridge{chatStates: make(map[string]*ChatState)}
+	var replies []string
+	sendReply := func(text string) bool {
+		replies = append(replies, text)
+		return true
+	}
+
+	// Image-only messages get the default prompt, or the configured one.
+	for _, msg := range []*feishu.Message{
+		{MsgType: "image", Content: feishu.ImageContent, ImageKeys: []string{"img1"}},
+		{MsgType: "post", Content: " \n", ImageKeys: []string{"img1", "img2"}},
+	} {
+		if got, ok := b.fillEmptyPrompt("c1", msg, sendReply); !ok || got != defaultMessages.ImagePrompt {
+			t.Errorf("%s: expected the default image prompt, got %q ok=%v", msg.MsgType, got, ok)
+		}
+	}
+	en, _ := newTestBridge(t)
+	en.setLanguage("c1", "en")
+	imageOnly := &feishu.Message{MsgType: "image", Content: feishu.ImageContent, ImageKeys: []string{"img1"}}
+	if got, ok := en.fillEmptyPrompt("c1", imageOnly, sendReply); !ok || got != englishMessages.ImagePrompt {
+		t.Errorf("expected the English image prompt, got %q ok=%v", got, ok)
+	}
+	b.config.ImagePrompt = "这张截图里的报错是什么意思？"
+	msg := &feishu.Message{MsgType: "image", Content: feishu.ImageContent, ImageKeys: []string{"img1"}}
+	if got, ok := b.fillEmptyPrompt("c1", msg, sendReply); !ok || got != b.config.ImagePrompt {
+		t.Errorf("expected the configured image prompt, got %q ok=%v", got, ok)
+	}
+	if len(replies) != 0 {
+		t.Fatalf("expected no replies for image-only messages, got %v", replies)
+	}
+
+	// Text, with or without images, is kept as is.
+	msg = &feishu.Message{MsgType: "post", Content: "看下这张图", ImageKeys: []string{"img1"}}
+	if got, ok := b.fillEmptyPrompt("c1", msg, sendReply); !ok || got != "看下这张图" {
+		t.Errorf("expected the text to be kept, got %q ok=%v", got, ok)
+	}
+}
+
+func TestFillEmptyPrompt_EmptyText(t *testing.T) {
+	b := &Bridge{chatStates: make(map[string]*ChatState)}
+	var replies []string
+	sendReply := func(text string) bool {
+		replies = append(replies, text)
+		return true
+	}
+
+	for _, content := range []string{"", "  \n\t"} {
+		if _, ok := b.fillEmptyPrompt("c1", &feishu.Message{MsgType: "text", Content: content}, sendReply); ok {
+			t.Errorf("expected %q to be dropped", content)
+		}
+	}
+	if len(replies) != 2 || replies[0] != "🤔 没有收到文字内容，请直接输入想问的问题" {
+		t.Errorf("expected a nudge per empty message, got %v", replies)
+	}
+}
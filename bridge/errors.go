@@ -0,0 +1,59 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxRecentErrors bounds the /errors ring buffer.
+const maxRecentErrors = 50
+
+// maxListedErrors caps how many entries /errors prints, newest first.
+const maxListedErrors = 20
+
+// recentError is one entry in the /errors buffer.
+type recentError struct {
+	At     time.Time
+	ChatID string
+	Text   string
+}
+
+// recordError remembers a failure for /errors, so admins can see why a chat
+// isn't getting replies without access to the server logs. Callers still
+// log the error themselves.
+func (b *Bridge) recordError(chatID, format string, args ...any) {
+	e := recentError{At: time.Now(), ChatID: chatID, Text: fmt.Sprintf(format, args...)}
+	b.recentErrorsMu.Lock()
+	defer b.recentErrorsMu.Unlock()
+	b.recentErrors = append(b.recentErrors, e)
+	if over := len(b.recentErrors) - maxRecentErrors; over > 0 {
+		b.recentErrors = append([]recentError(nil), b.recentErrors[over:]...)
+	}
+}
+
+// formatRecentErrors renders the /errors reply in chatID's language. Only
+// chatID's own errors are listed unless allChats is set, so a group never
+// sees what failed in other chats.
+func (b *Bridge) formatRecentErrors(chatID string, allChats bool) string {
+	b.recentErrorsMu.Lock()
+	var errs []recentError
+	for _, e := range b.recentErrors {
+		if allChats || e.ChatID == chatID {
+			errs = append(errs, e)
+		}
+	}
+	b.recentErrorsMu.Unlock()
+
+	if len(errs) == 0 {
+		return b.chatMessages(chatID).ErrorsNone
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, b.chatMessages(chatID).ErrorsHeader, len(errs))
+	for i := len(errs) - 1; i >= 0 && i >= len(errs)-maxListedErrors; i-- {
+		e := errs[i]
+		fmt.Fprintf(&sb, "\n%s [%s] %s", e.At.Format("01-02 15:04:05"), e.ChatID, truncateRunes(e.Text, 200))
+	}
+	return sb.String()
+}
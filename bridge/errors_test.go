@@ -0,0 +1,95 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestRecordError_Bounded(t *testing.T) {
+	b := &Bridge{}
+	for i := 0; i < maxRecentErrors+5; i++ {
+		b.recordError("c1", "error %d", i)
+	}
+	if len(b.recentErrors) != maxRecentErrors {
+		t.Fatalf("expected %d entries, got %d", maxRecentErrors, len(b.recentErrors))
+	}
+	if got := b.recentErrors[0].Text; got != "error 5" {
+		t.Fatalf("expected oldest entries to be dropped, first is %q", got)
+	}
+
+	out := b.formatRecentErrors("c1", false)
+	lines := strings.Split(out, "\n")
+	if len(lines) != maxListedErrors+1 {
+		t.Fatalf("expected header plus %d entries, got %d lines", maxListedErrors, len(lines))
+	}
+	if !strings.HasSuffix(lines[1], fmt.Sprintf("[c1] error %d", maxRecentErrors+4)) {
+		t.Fatalf("expected newest error first, got %q", lines[1])
+	}
+}
+
+func TestHandleTurnCompleted_RecordsFailure(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.getChatState("c1").ThreadID = "t1"
+
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "completed"})
+	b.handleTurnCompleted(codex.TurnCompletedParams{
+		ThreadID: "t1", TurnID: "turn2", Status: "failed",
+		Error: &codex.TurnError{Message: "context window exceeded"},
+	})
+
+	if len(b.recentErrors) != 1 || b.recentErrors[0].ChatID != "c1" || !strings.Contains(b.recentErrors[0].Text, "context window exceeded") {
+		t.Fatalf("expected the failed turn to be recorded, got %+v", b.recentErrors)
+	}
+}
+
+func TestErrorsCommand_AdminOnly(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{AdminOpenIDs: []string{"ou_admin"}},
+		feishuClient: m,
+	}
+	errorsCmd := func(sender string) string {
+		b.handleFeishuMessageV2(&feishu.Message{
+			ChatID:   "c1",
+			ChatType: "group",
+			MsgID:    "m_" + sender,
+			MsgType:  "text",
+			Content:  "/errors",
+			Sender:   &feishu.Sender{SenderID: sender},
+		})
+		return m.SentMessages[len(m.SentMessages)-1].Text
+	}
+
+	if got := errorsCmd("ou_user"); got != "⚠️ 该命令仅管理员可用" {
+		t.Fatalf("expected non-admin to be rejected, got %q", got)
+	}
+	if got := errorsCmd("ou_admin"); got != "最近没有错误" {
+		t.Fatalf("unexpected empty reply: %q", got)
+	}
+	b.recordError("c1", "turn/start: boom")
+	b.recordError("c2", "turn/start: secret")
+	got := errorsCmd("ou_admin")
+	if !strings.Contains(got, "[c1] turn/start: boom") {
+		t.Fatalf("expected recorded error in reply, got %q", got)
+	}
+	if strings.Contains(got, "[c2]") {
+		t.Fatalf("expected other chats' errors to be hidden in a group, got %q", got)
+	}
+
+	// A private chat with an admin lists every chat.
+	b.handleFeishuMessageV2(&feishu.Message{
+		ChatID:   "p1",
+		ChatType: "p2p",
+		MsgID:    "m_p2p",
+		MsgType:  "text",
+		Content:  "/errors",
+		Sender:   &feishu.Sender{SenderID: "ou_admin"},
+	})
+	if got := m.SentMessages[len(m.SentMessages)-1].Text; !strings.Contains(got, "[c1]") || !strings.Contains(got, "[c2]") {
+		t.Fatalf("expected all chats' errors in p2p, got %q", got)
+	}
+}
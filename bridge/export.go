@@ -0,0 +1,223 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+const (
+	// exportChunkChars is the size (in runes) of each message an exported
+	// transcript is split into.
+	exportChunkChars = 4000
+	// maxExportChunks caps how many messages one /export sends.
+	maxExportChunks = 20
+)
+
+// handleExportCommand resumes the chat's current thread and sends its
+// transcript, one rich-text post per turn. If Feishu rejects a post, the
+// remaining turns are sent as markdown instead, split across several
+// messages when long.
+func (b *Bridge) handleExportCommand(msg *feishu.Message) {
+	replyInThread := msg.ChatType == "group"
+	reply := func(text string) {
+		if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+			_ = b.feishuClient.SendText(msg.ChatID, text)
+		}
+	}
+
+	msgs := b.chatMessages(msg.ChatID)
+	thread, err := b.resumeChatThread(msg.ChatID)
+	if errors.Is(err, errNoThread) {
+		reply(msgs.ExportNone)
+		return
+	}
+	if err != nil {
+		reply(fmt.Sprintf(msgs.ExportFailed, err))
+		return
+	}
+
+	text := formatTranscript(thread)
+	if len(thread.Turns) > 0 {
+		failed, err := b.replyTranscriptPosts(msg, thread, replyInThread)
+		if err == nil {
+			return
+		}
+		fmt.Printf("[Bridge] Failed to send transcript post, falling back to markdown: %v\n", err)
+		if failed > 0 {
+			var sb strings.Builder
+			writeTranscriptTurns(&sb, thread.Turns[failed:], failed)
+			text = strings.TrimPrefix(sb.String(), "\n")
+		}
+	}
+
+	chunks := splitMessage(text, exportChunkChars)
+	if len(chunks) > maxExportChunks {
+		chunks = append(chunks[:maxExportChunks], fmt.Sprintf(msgs.ExportTruncated, maxExportChunks))
+	}
+	for i, chunk := range chunks {
+		if len(chunks) > 1 {
+			chunk = fmt.Sprintf("[%d/%d]\n%s", i+1, len(chunks), chunk)
+		}
+		reply(chunk)
+	}
+}
+
+// replyTranscriptPosts sends thread's turns as rich-text posts, one per turn,
+// stopping after maxExportChunks posts. If Feishu rejects a post it returns
+// that turn's index and the error, so the caller can send the rest another
+// way.
+func (b *Bridge) replyTranscriptPosts(msg *feishu.Message, thread *codex.Thread, replyInThread bool) (int, error) {
+	msgs := b.chatMessages(msg.ChatID)
+	sent := 0
+	for i, turn := range thread.Turns {
+		content := transcriptTurnPost(turn)
+		if len(content) == 0 {
+			continue
+		}
+		if sent == maxExportChunks {
+			b.replyText(msg.ChatID, msg.MsgID, fmt.Sprintf(msgs.ExportTruncated, sent), replyInThread)
+			break
+		}
+		title := fmt.Sprintf(msgs.ExportTurnTitle, i+1)
+		if err := b.feishuClient.ReplyRichText(msg.MsgID, title, content, replyInThread); err != nil {
+			return i, err
+		}
+		sent++
+	}
+	return len(thread.Turns), nil
+}
+
+// transcriptTurnPost renders one turn for /export: its items, followed by
+// the error if the turn failed.
+func transcriptTurnPost(turn codex.Turn) [][]map[string]interface{} {
+	content := renderItemsPost(turn.Items)
+	if turn.Error != nil && turn.Error.Message != "" {
+		if len(content) > 0 {
+			content = append(content, []map[string]interface{}{})
+		}
+		content = append(content, feishu.NewPostBuilder().TextRun("❌ "+turn.Error.Message).Content()...)
+	}
+	return content
+}
+
+// errNoThread is returned when a chat has no current thread to resume.
+var errNoThread = errors.New("no thread for chat")
+
+// GetThreadTranscript returns the turn history of chatID's current thread,
+// as reported by thread/resume.
+func (b *Bridge) GetThreadTranscript(chatID string) ([]codex.Turn, error) {
+	thread, err := b.resumeChatThread(chatID)
+	if err != nil {
+		return nil, err
+	}
+	return thread.Turns, nil
+}
+
+// resumeChatThread resumes the chat's current thread (the in-memory one, or
+// the stored session if it is still fresh) to read its history.
+func (b *Bridge) resumeChatThread(chatID string) (*codex.Thread, error) {
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	threadID := state.ThreadID
+	state.mu.Unlock()
+	if threadID == "" {
+		if entry, err := b.sessionStore.GetByChatID(chatID); err == nil && entry != nil && b.sessionStore.IsFresh(entry) {
+			threadID = entry.ThreadID
+		}
+	}
+	if threadID == "" {
+		return nil, errNoThread
+	}
+	return b.codexClient.ThreadResume(b.ctx, threadID)
+}
+
+// formatTranscript renders a thread's turns as a markdown document. Reasoning
+// and other internal items are left out.
+func formatTranscript(thread *codex.Thread) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# 会话记录 %s\n", thread.ID)
+	if thread.Cwd != "" {
+		fmt.Fprintf(&sb, "工作目录：%s\n", thread.Cwd)
+	}
+	if len(thread.Turns) == 0 {
+		sb.WriteString("\n（暂无对话）\n")
+		return sb.String()
+	}
+	writeTranscriptTurns(&sb, thread.Turns, 0)
+	return sb.String()
+}
+
+// writeTranscriptTurns writes turns as markdown sections, numbered from
+// first+1.
+func writeTranscriptTurns(sb *strings.Builder, turns []codex.Turn, first int) {
+	for i, turn := range turns {
+		fmt.Fprintf(sb, "\n## 第 %d 轮", first+i+1)
+		if turn.Status != "" && turn.Status != "completed" {
+			fmt.Fprintf(sb, "（%s）", turn.Status)
+		}
+		sb.WriteString("\n")
+		for _, item := range turn.Items {
+			switch item.Type {
+			case "userMessage":
+				if text := stripSenderContext(item.UserText()); text != "" {
+					fmt.Fprintf(sb, "\n**用户**：\n%s\n", text)
+				}
+			case "agentMessage":
+				if item.Text != "" {
+					fmt.Fprintf(sb, "\n**Codex**：\n%s\n", item.Text)
+				}
+			case "commandExecution":
+				fmt.Fprintf(sb, "\n```\n$ %s\n```\n", item.Command)
+			case "fileChange":
+				for _, ch := range item.Changes {
+					fmt.Fprintf(sb, "\n- 修改文件：%s", ch.Path)
+				}
+				sb.WriteString("\n")
+			}
+		}
+		if turn.Error != nil && turn.Error.Message != "" {
+			fmt.Fprintf(sb, "\n❌ %s\n", turn.Error.Message)
+		}
+	}
+}
+
+// splitMessage splits text into chunks of at most max runes, preferring to
+// break at line boundaries.
+func splitMessage(text string, max int) []string {
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return []string{text}
+	}
+
+	var chunks []string
+	var cur strings.Builder
+	curLen := 0
+	flush := func() {
+		if curLen > 0 {
+			chunks = append(chunks, strings.TrimRight(cur.String(), "\n"))
+			cur.Reset()
+			curLen = 0
+		}
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		n := utf8.RuneCountInString(line)
+		if curLen+n > max {
+			flush()
+		}
+		// A single line longer than max is hard-split.
+		for n > max {
+			r := []rune(line)
+			chunks = append(chunks, string(r[:max]))
+			line = string(r[max:])
+			n -= max
+		}
+		cur.WriteString(line)
+		curLen += n
+	}
+	flush()
+	return chunks
+}
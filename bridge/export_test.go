@@ -0,0 +1,134 @@
+package bridge
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestFormatTranscript(t *testing.T) {
+	thread := &codex.Thread{
+		ID:  "t1",
+		Cwd: "/srv/proj",
+		Turns: []codex.Turn{
+			{
+				Status: "completed",
+				Items: []codex.ThreadItem{
+					{Type: "userMessage", Content: json.RawMessage(`[{"type":"text","text":"run the tests"}]`)},
+					{Type: "reasoning", Summary: []string{"thinking"}},
+					{Type: "commandExecution", Command: "go test ./..."},
+					{Type: "fileChange", Changes: []codex.FileChange{{Path: "a.go"}}},
+					{Type: "agentMessage", Text: "All green"},
+				},
+			},
+			{Status: "failed", Error: &codex.TurnError{Message: "overloaded"}},
+		},
+	}
+
+	out := formatTranscript(thread)
+	for _, want := range []string{
+		"# 会话记录 t1",
+		"工作目录：/srv/proj",
+		"## 第 1 轮\n",
+		"**用户**：\nrun the tests",
+		"$ go test ./...",
+		"- 修改文件：a.go",
+		"**Codex**：\nAll green",
+		"## 第 2 轮（failed）",
+		"❌ overloaded",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("transcript missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "thinking") {
+		t.Errorf("reasoning should not be exported:\n%s", out)
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	if got := splitMessage("short", 10); len(got) != 1 || got[0] != "short" {
+		t.Fatalf("unexpected split: %q", got)
+	}
+
+	text := "第一行\n第二行\n" + strings.Repeat("长", 25) + "\n尾"
+	chunks := splitMessage(text, 10)
+	if len(chunks) != 4 || chunks[0] != "第一行\n第二行" || chunks[3] != "长长长长长\n尾" {
+		t.Fatalf("unexpected chunks: %q", chunks)
+	}
+	for _, c := range chunks {
+		if n := utf8.RuneCountInString(c); n > 10 {
+			t.Fatalf("chunk too long (%d): %q", n, c)
+		}
+	}
+	if strings.ReplaceAll(strings.Join(chunks, ""), "\n", "") != strings.ReplaceAll(text, "\n", "") {
+		t.Fatalf("chunks lost content: %q", chunks)
+	}
+}
+
+func TestExportCommand_NoThread(t *testing.T) {
+	b, m := newTestBridge(t)
+
+	b.handleExportCommand(&feishu.Message{ChatID: "c1", MsgID: "m1"})
+
+	if len(m.SentMessages) != 1 || m.SentMessages[0].Text != "当前没有可导出的会话" {
+		t.Fatalf("expected no-session reply, got %+v", m.SentMessages)
+	}
+
+	if _, err := b.GetThreadTranscript("c1"); !errors.Is(err, errNoThread) {
+		t.Fatalf("expected errNoThread, got %v", err)
+	}
+}
+
+func exportTestThread() *codex.Thread {
+	return &codex.Thread{
+		ID: "t1",
+		Turns: []codex.Turn{
+			{Status: "completed", Items: []codex.ThreadItem{
+				{Type: "userMessage", Content: json.RawMessage(`[{"type":"text","text":"run the tests"}]`)},
+				{Type: "agentMessage", Text: "All green"},
+			}},
+			{Status: "completed", Items: []codex.ThreadItem{{Type: "reasoning", Summary: []string{"thinking"}}}},
+			{Status: "failed", Items: []codex.ThreadItem{{Type: "agentMessage", Text: "Trying"}}, Error: &codex.TurnError{Message: "overloaded"}},
+		},
+	}
+}
+
+func TestReplyTranscriptPosts(t *testing.T) {
+	b, m := newTestBridge(t)
+	b.setLanguage("c1", "en")
+
+	failed, err := b.replyTranscriptPosts(&feishu.Message{ChatID: "c1", MsgID: "m1"}, exportTestThread(), false)
+	if err != nil || failed != 3 {
+		t.Fatalf("replyTranscriptPosts = %d, %v", failed, err)
+	}
+	if len(m.SentMessages) != 2 || !m.SentMessages[0].IsRich || m.SentMessages[0].Title != "Turn 1" || m.SentMessages[1].Title != "Turn 3" {
+		t.Fatalf("expected posts for turns 1 and 3, got %+v", m.SentMessages)
+	}
+	if got := m.SentMessages[0].Content; !reflect.DeepEqual(got, transcriptTurnPost(exportTestThread().Turns[0])) {
+		t.Errorf("unexpected first post: %#v", got)
+	}
+	last := m.SentMessages[1].Content
+	if got := last[len(last)-1][0]["text"]; got != "❌ overloaded" {
+		t.Errorf("expected the turn error at the end of the post, got %v", got)
+	}
+}
+
+func TestReplyTranscriptPosts_RejectedPost(t *testing.T) {
+	b, m := newTestBridge(t)
+	m.FailRichTitles = map[string]bool{"第 3 轮": true}
+
+	failed, err := b.replyTranscriptPosts(&feishu.Message{ChatID: "c1", MsgID: "m1"}, exportTestThread(), false)
+	if err == nil || failed != 2 {
+		t.Fatalf("expected turn 3 to be rejected, got %d, %v", failed, err)
+	}
+	if len(m.SentMessages) != 1 {
+		t.Fatalf("expected only the first post to be sent, got %+v", m.SentMessages)
+	}
+}
@@ -0,0 +1,90 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitTimeout bounds each git invocation so a slow or hung repo can't block a
+// command reply.
+const gitTimeout = 5 * time.Second
+
+// runGit runs git with args in dir and returns its trimmed stdout.
+func runGit(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// isGitRepo reports whether dir is inside a git work tree.
+func isGitRepo(dir string) bool {
+	out, err := runGit(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// formatGitStatus returns a status line with the current branch and whether
+// the work tree has uncommitted changes, or "" if dir is not a git repo.
+func formatGitStatus(dir string, msgs *Messages) string {
+	if dir == "" || !isGitRepo(dir) {
+		return ""
+	}
+	branch, err := runGit(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		// Fresh repo without commits.
+		branch = msgs.GitNoCommits
+	}
+	porcelain, err := runGit(dir, "status", "--porcelain")
+	if err != nil {
+		return fmt.Sprintf(msgs.GitBranch, branch)
+	}
+	if porcelain != "" {
+		return fmt.Sprintf(msgs.GitBranchDirty, branch)
+	}
+	return fmt.Sprintf(msgs.GitBranchClean, branch)
+}
+
+// gitChangedFiles returns the paths reported by `git status --porcelain`.
+func gitChangedFiles(dir string) ([]string, error) {
+	out, err := runGit(dir, "status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		// Renames are reported as "old -> new".
+		if _, newPath, ok := strings.Cut(path, " -> "); ok {
+			path = newPath
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// gitCommitAll stages every change in dir, commits it with message and
+// returns the short hash of the new commit.
+func gitCommitAll(dir, message string) (string, error) {
+	if _, err := runGit(dir, "add", "-A"); err != nil {
+		return "", err
+	}
+	if _, err := runGit(dir, "commit", "-q", "-m", message); err != nil {
+		return "", err
+	}
+	return runGit(dir, "rev-parse", "--short", "HEAD")
+}
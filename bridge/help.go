@@ -1,38 +1,86 @@
 package bridge
 
-func buildHelpPost() (title string, content [][]map[string]interface{}) {
-	text := func(s string, styles ...string) map[string]interface{} {
-		m := map[string]interface{}{
-			"tag":  "text",
-			"text": s,
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// helpEntry is one command line in /help: the usage and what it does.
+type helpEntry struct {
+	usage string
+	desc  string
+}
+
+type helpText struct {
+	header  string
+	or      string // between a command's names, e.g. "/status 或 /s"
+	sep     string // between usage and desc in the rich-text post
+	textSep string // between usage and desc in the plain-text fallback
+}
+
+var helpTexts = map[string]helpText{
+	LanguageZH: {
+		header:  "可用命令：",
+		or:      " 或 ",
+		sep:     " —— ",
+		textSep: "：",
+	},
+	LanguageEN: {
+		header:  "Available commands:",
+		or:      " or ",
+		sep:     " — ",
+		textSep: ": ",
+	},
+}
+
+func helpTextFor(lang string) helpText {
+	if h, ok := helpTexts[lang]; ok {
+		return h
+	}
+	return helpTexts[LanguageZH]
+}
+
+// helpEntries renders the command registry for lang, naming commands with
+// the configured prefix.
+func (b *Bridge) helpEntries(lang string) []helpEntry {
+	if _, ok := helpTexts[lang]; !ok {
+		lang = LanguageZH
+	}
+	h := helpTexts[lang]
+	entries := make([]helpEntry, 0, len(commandSpecs))
+	for _, c := range commandSpecs {
+		aliases := make([]string, len(c.Aliases))
+		for i, alias := range c.Aliases {
+			aliases[i] = b.withPrefix(alias)
 		}
-		if len(styles) > 0 {
-			m["style"] = styles
+		usage := strings.Join(aliases, h.or)
+		if arg := c.ArgHint[lang]; arg != "" {
+			usage = aliases[0] + " " + arg
+			if len(aliases) > 1 {
+				usage += h.or + strings.Join(aliases[1:], h.or)
+			}
 		}
-		return m
+		entries = append(entries, helpEntry{usage: usage, desc: c.Description[lang]})
 	}
+	return entries
+}
 
-	title = ""
-	content = [][]map[string]interface{}{
-		{text("可用命令：")},
-		{text("1) "), text("/help 或 /h"), text(" —— 查看帮助")},
-		{text("2) "), text("/pwd"), text(" —— 查看当前工作目录")},
-		{text("3) "), text("/cd <绝对路径>"), text(" —— 切换工作目录")},
-		{text("4) "), text("/status 或 /s"), text(" —— 查看当前状态")},
-		{text("5) "), text("/queue 或 /q"), text(" —— 查看队列")},
-		{text("6) "), text("/clear 或 /c"), text(" —— 清空当前会话上下文")},
-		{text("7) "), text("/reset 或 /r"), text(" —— 重启 Codex")},
+func (b *Bridge) buildHelpPost(lang string) (title string, content [][]map[string]interface{}) {
+	h := helpTextFor(lang)
+	post := feishu.NewPostBuilder().TextRun(h.header).NewLine()
+	for i, e := range b.helpEntries(lang) {
+		post.TextRun(fmt.Sprintf("%d) ", i+1)).TextRun(e.usage).TextRun(h.sep + e.desc).NewLine()
 	}
-	return title, content
+	return "", post.Content()
 }
 
-func buildHelpFallbackText() string {
-	return "可用命令：\n" +
-		"/help 或 /h：查看帮助\n" +
-		"/pwd：查看当前工作目录\n" +
-		"/cd <绝对路径>：切换工作目录\n" +
-		"/status 或 /s：查看当前状态\n" +
-		"/queue 或 /q：查看队列\n" +
-		"/clear 或 /c：清空当前会话上下文\n" +
-		"/reset 或 /r：重启 Codex"
+func (b *Bridge) buildHelpFallbackText(lang string) string {
+	h := helpTextFor(lang)
+	lines := []string{h.header}
+	for _, e := range b.helpEntries(lang) {
+		lines = append(lines, e.usage+h.textSep+e.desc)
+	}
+	return strings.Join(lines, "\n")
 }
@@ -38,8 +38,59 @@ func TestHelpCommand_UsesRichTextPost(t *testing.T) {
 	}
 }
 
+func TestHelpCommand_TextArgUsesPlainText(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{WorkingDir: "."},
+		feishuClient: m,
+	}
+
+	b.handleFeishuMessageV2(&feishu.Message{
+		ChatID:   "c1",
+		ChatType: "p2p",
+		MsgID:    "m1",
+		MsgType:  "text",
+		Content:  "/help text",
+	})
+
+	if len(m.SentMessages) != 1 {
+		t.Fatalf("expected exactly one reply, got %d", len(m.SentMessages))
+	}
+	last := m.SentMessages[0]
+	if !last.IsReply || last.IsRich {
+		t.Fatalf("expected plain text reply, got %+v", last)
+	}
+	if last.Text != b.buildHelpFallbackText(LanguageZH) {
+		t.Fatalf("unexpected help text: %q", last.Text)
+	}
+}
+
+func TestHelpCommand_PlainTextOnly(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{WorkingDir: ".", PlainTextOnly: true},
+		feishuClient: m,
+	}
+
+	b.handleFeishuMessageV2(&feishu.Message{
+		ChatID:   "c1",
+		ChatType: "p2p",
+		MsgID:    "m1",
+		MsgType:  "text",
+		Content:  "/help",
+	})
+
+	if len(m.SentMessages) != 1 {
+		t.Fatalf("expected exactly one reply, got %d", len(m.SentMessages))
+	}
+	if last := m.SentMessages[0]; !last.IsReply || last.IsRich || last.Text != b.buildHelpFallbackText(LanguageZH) {
+		t.Fatalf("expected plain text help reply, got %+v", last)
+	}
+}
+
 func TestBuildHelpPost_NumberedLines(t *testing.T) {
-	_, content := buildHelpPost()
+	b := &Bridge{}
+	_, content := b.buildHelpPost(LanguageZH)
 	if len(content) < 6 {
 		t.Fatalf("expected multiple lines")
 	}
@@ -99,3 +150,26 @@ func hasBoldStyle(line []map[string]interface{}) bool {
 	}
 	return false
 }
+
+func TestHelpEntries_FromRegistry(t *testing.T) {
+	b := &Bridge{}
+	entries := b.helpEntries(LanguageZH)
+	if len(entries) != len(commandSpecs) {
+		t.Fatalf("expected one help line per command, got %d for %d commands", len(entries), len(commandSpecs))
+	}
+	want := map[string]bool{"/status 或 /s": true, "/cd <绝对路径>": true, "/help 或 /h": true}
+	for _, e := range entries {
+		delete(want, e.usage)
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing help usages %v in %+v", want, entries)
+	}
+	if got := b.helpEntries(LanguageEN)[0].usage; got != "/help or /h" {
+		t.Fatalf("unexpected English usage %q", got)
+	}
+
+	b.config.CommandPrefix = "!"
+	if got := b.helpEntries(LanguageEN)[0].usage; got != "!help or !h" {
+		t.Fatalf("expected the configured prefix in usage, got %q", got)
+	}
+}
@@ -0,0 +1,71 @@
+package bridge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// handleImageViewItem sends the image referenced by a completed imageView item
+// back to the chat, when enabled and the file lives inside the working dir.
+func (b *Bridge) handleImageViewItem(chatID string, item *codex.ThreadItem) {
+	if !b.config.SendImages || item.Path == "" {
+		return
+	}
+	path, ok := resolveInsideDir(b.config.WorkingDir, item.Path)
+	if !ok {
+		fmt.Printf("[Bridge] Skip image outside working dir: %s\n", item.Path)
+		return
+	}
+	if err := b.sendImageToChat(chatID, path); err != nil {
+		fmt.Printf("[Bridge] Failed to send image %s: %v\n", path, err)
+	}
+}
+
+// resolveInsideDir resolves path (relative paths are taken from dir) with
+// symlinks followed, and reports whether it is a regular file inside dir.
+func resolveInsideDir(dir, path string) (string, bool) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", false
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(realDir, realPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	info, err := os.Stat(realPath)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	return realPath, true
+}
+
+// sendImageToChat sends a local image into chatID, replying to the message
+// currently being processed when there is one and falling back to a plain
+// send if the reply fails.
+func (b *Bridge) sendImageToChat(chatID, path string) error {
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	msgID := state.MsgID
+	replyInThread := state.ChatType == "group"
+	state.mu.Unlock()
+
+	if canReplyTo(msgID) {
+		err := b.feishuClient.ReplyImage(msgID, path, replyInThread)
+		if err == nil {
+			return nil
+		}
+		fmt.Printf("[Bridge] Failed to reply image: %v\n", err)
+	}
+	return b.feishuClient.SendImage(chatID, path)
+}
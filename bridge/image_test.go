@@ -0,0 +1,195 @@
+package bridge
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestSendImageToChat_RepliesToCurrentMessage(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{feishuClient: m, chatStates: make(map[string]*ChatState)}
+	b.getChatState("c1").MsgID = "m1"
+
+	if err := b.sendImageToChat("c1", "/work/chart.png"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.SentMessages) != 1 {
+		t.Fatalf("expected 1 message, got %+v", m.SentMessages)
+	}
+	sent := m.SentMessages[0]
+	if !sent.IsReply || sent.MsgID != "m1" || sent.Image != "/work/chart.png" {
+		t.Fatalf("unexpected message: %+v", sent)
+	}
+}
+
+func TestSendImageToChat_FallsBackToSend(t *testing.T) {
+	m := &MockFeishuClient{ReplyImageError: errors.New("reply failed")}
+	b := &Bridge{feishuClient: m, chatStates: make(map[string]*ChatState)}
+	b.getChatState("c1").MsgID = "m1"
+
+	if err := b.sendImageToChat("c1", "/work/chart.png"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.SentMessages) != 1 || m.SentMessages[0].IsReply || m.SentMessages[0].ChatID != "c1" {
+		t.Fatalf("expected fallback send, got %+v", m.SentMessages)
+	}
+}
+
+func TestResolveInsideDir(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	inside := filepath.Join(dir, "out", "chart.png")
+	if err := os.MkdirAll(filepath.Dir(inside), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{inside, filepath.Join(outside, "secret.png")} {
+		if err := os.WriteFile(p, []byte("png"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret.png"), filepath.Join(dir, "link.png")); err != nil {
+		t.Fatal(err)
+	}
+
+	realInside, _ := filepath.EvalSymlinks(inside)
+	tests := []struct {
+		path string
+		ok   bool
+	}{
+		{inside, true},
+		{"out/chart.png", true},
+		{"../" + filepath.Base(outside) + "/secret.png", false},
+		{filepath.Join(outside, "secret.png"), false},
+		{"link.png", false},
+		{"out", false},
+		{"missing.png", false},
+	}
+	for _, tt := range tests {
+		got, ok := resolveInsideDir(dir, tt.path)
+		if ok != tt.ok {
+			t.Errorf("resolveInsideDir(%q) ok = %v, want %v", tt.path, ok, tt.ok)
+		}
+		if ok && got != realInside {
+			t.Errorf("resolveInsideDir(%q) = %q, want %q", tt.path, got, realInside)
+		}
+	}
+}
+
+func TestHandleEvent_ImageViewSendsImage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "chart.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{WorkingDir: dir},
+		feishuClient: m,
+		chatStates:   make(map[string]*ChatState),
+	}
+	b.getChatState("c1").ThreadID = "t1"
+
+	event := codex.Event{
+		Method: codex.MethodItemCompleted,
+		Params: json.RawMessage(`{"threadId":"t1","item":{"type":"imageView","id":"i1","path":"chart.png"}}`),
+	}
+
+	b.handleEvent(event)
+	if len(m.SentMessages) != 0 {
+		t.Fatalf("expected no image when disabled, got %+v", m.SentMessages)
+	}
+
+	b.config.SendImages = true
+	b.handleEvent(event)
+	if len(m.SentMessages) != 1 || filepath.Base(m.SentMessages[0].Image) != "chart.png" {
+		t.Fatalf("expected chart.png to be sent, got %+v", m.SentMessages)
+	}
+}
+
+func TestCapImageKeys(t *testing.T) {
+	keys := []string{"a", "b", "c"}
+	if got, dropped := capImageKeys(keys, 0); len(got) != 3 || dropped != 0 {
+		t.Fatalf("expected no cap with 0, got %v dropped=%d", got, dropped)
+	}
+	if got, dropped := capImageKeys(keys, 2); len(got) != 2 || got[1] != "b" || dropped != 1 {
+		t.Fatalf("expected first 2 kept, got %v dropped=%d", got, dropped)
+	}
+}
+
+func TestProcessQueuedMessage_CapsImages(t *testing.T) {
+	// codex is never started: the turn fails after the images are downloaded.
+	b, m := newTestBridge(t)
+	b.config.MaxImagesPerMessage = 2
+
+	msg := &feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "post", Content: "look", ImageKeys: []string{"k1", "k2", "k3", "k4", "k5"}}
+	b.processQueuedMessage("c1", msg, 0)
+
+	if len(m.DownloadedImages) != 2 {
+		t.Fatalf("expected 2 downloads, got %v", m.DownloadedImages)
+	}
+	if reply := findReplyText(m, "m1"); reply != "⚠️ 图片过多，只处理前 2 张，其余 3 张已忽略" {
+		t.Fatalf("unexpected notice %q", reply)
+	}
+}
+
+func TestTurnImages_DeletedOnlyAfterCompletion(t *testing.T) {
+	img := filepath.Join(t.TempDir(), "k1.png")
+	if err := os.WriteFile(img, []byte("png"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	exists := func() bool {
+		_, err := os.Stat(img)
+		return err == nil
+	}
+
+	b, _ := newTestBridge(t)
+	b.config.CleanupImages = true
+	state := b.getChatState("c1")
+	state.mu.Lock()
+	state.ThreadID = "t1"
+	state.MsgID = "m1"
+	b.setTurnImagesLocked(state, []string{img})
+	state.mu.Unlock()
+
+	// Events during the turn leave the image alone.
+	b.handleEvent(codex.Event{
+		Method: codex.MethodItemCompleted,
+		Params: json.RawMessage(`{"threadId":"t1","turnId":"turn1","item":{"id":"i1","type":"agentMessage","text":"looking"}}`),
+	})
+	if !exists() {
+		t.Fatal("image deleted before the turn completed")
+	}
+	// A turn of another thread doesn't release it either.
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "other", TurnID: "x", Status: "completed"})
+	if !exists() {
+		t.Fatal("image deleted by another thread's turn")
+	}
+
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "completed"})
+	if exists() {
+		t.Fatal("expected image to be deleted after turn/completed")
+	}
+}
+
+func TestProcessQueuedMessage_DeletesImagesWhenTurnNotStarted(t *testing.T) {
+	// codex is never started: the turn fails after the images are downloaded.
+	b, m := newTestBridge(t)
+	b.config.CleanupImages = true
+	m.DownloadDir = t.TempDir()
+
+	msg := &feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "image", ImageKeys: []string{"k1"}}
+	b.processQueuedMessage("c1", msg, 0)
+
+	if len(m.DownloadedImages) != 1 {
+		t.Fatalf("expected 1 download, got %v", m.DownloadedImages)
+	}
+	if _, err := os.Stat(m.DownloadedImages[0]); !os.IsNotExist(err) {
+		t.Fatalf("expected image of a turn that never started to be deleted, stat err = %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package bridge
+
+import (
+	"fmt"
+	"os"
+)
+
+// Downloaded images are handed to codex as localImage paths, which codex may
+// read at any point of the turn. With Config.CleanupImages they are deleted
+// only once the turn is over: after turn/completed for the chat's thread,
+// after the turn was interrupted by a clear or reset, after the app-server
+// exited, or right away when the turn never started.
+
+// setTurnImagesLocked records the images of the turn that just started.
+// state.mu must be held.
+func (b *Bridge) setTurnImagesLocked(state *ChatState, paths []string) {
+	if !b.config.CleanupImages {
+		return
+	}
+	state.turnImages = append(state.turnImages, paths...)
+}
+
+// takeTurnImagesLocked returns and forgets the images of the chat's current
+// turn. state.mu must be held.
+func takeTurnImagesLocked(state *ChatState) []string {
+	paths := state.turnImages
+	state.turnImages = nil
+	return paths
+}
+
+// removeImages deletes downloaded images once no turn can read them.
+func (b *Bridge) removeImages(paths []string) {
+	if !b.config.CleanupImages {
+		return
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("[Bridge] Failed to remove image %s: %v\n", path, err)
+		}
+	}
+}
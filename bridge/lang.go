@@ -0,0 +1,73 @@
+package bridge
+
+import (
+	"fmt"
+)
+
+// languageNames are how /lang reports each language.
+var languageNames = map[string]string{
+	LanguageZH: "中文 (zh)",
+	LanguageEN: "English (en)",
+}
+
+// loadChatLanguages restores the languages chats chose with /lang before a
+// restart.
+func (b *Bridge) loadChatLanguages() {
+	langs, err := b.sessionStore.ChatLanguages()
+	if err != nil {
+		fmt.Printf("[Bridge] Failed to load chat languages: %v\n", err)
+		return
+	}
+	for chatID, lang := range langs {
+		if lang, err = normalizeLanguage(lang); err != nil {
+			continue
+		}
+		state := b.getChatState(chatID)
+		state.mu.Lock()
+		state.Language = lang
+		state.mu.Unlock()
+	}
+}
+
+// chatLanguage returns the language set with /lang for chatID, or
+// Config.Language.
+func (b *Bridge) chatLanguage(chatID string) string {
+	b.chatStatesMu.RLock()
+	state := b.chatStates[chatID]
+	b.chatStatesMu.RUnlock()
+	var lang string
+	if state != nil {
+		state.mu.Lock()
+		lang = state.Language
+		state.mu.Unlock()
+	}
+	if lang == "" {
+		return b.config.Language
+	}
+	return lang
+}
+
+// setLanguage handles /lang: an empty arg reports the chat's language,
+// otherwise it is switched and persisted. The reply is in the new language.
+func (b *Bridge) setLanguage(chatID, arg string) string {
+	if arg == "" {
+		lang := b.chatLanguage(chatID)
+		name, ok := languageNames[lang]
+		if !ok {
+			name = languageNames[LanguageZH]
+		}
+		return fmt.Sprintf(b.chatMessages(chatID).LangCurrent, name, b.withPrefix("/lang"))
+	}
+	lang, err := normalizeLanguage(arg)
+	if err != nil {
+		return fmt.Sprintf(b.chatMessages(chatID).LangUsage, b.withPrefix("/lang"))
+	}
+	if err := b.sessionStore.SetLanguage(chatID, lang); err != nil {
+		fmt.Printf("[Bridge] Failed to persist language for %s: %v\n", chatID, err)
+	}
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	state.Language = lang
+	state.mu.Unlock()
+	return b.chatMessages(chatID).LangSet
+}
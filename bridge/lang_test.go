@@ -0,0 +1,66 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestLangCommand_SwitchesRepliesPerChat(t *testing.T) {
+	b, m := newTestBridge(t)
+	b.config.WorkingDir = "/tmp"
+
+	send := func(chatID, content string) string {
+		b.handleFeishuMessageV2(&feishu.Message{ChatID: chatID, ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: content})
+		return m.SentMessages[len(m.SentMessages)-1].Text
+	}
+
+	if got := send("c1", "/lang en"); got != englishMessages.LangSet {
+		t.Fatalf("unexpected /lang en reply: %q", got)
+	}
+	if got := send("c1", "/pwd"); got != "Working directory: /tmp" {
+		t.Fatalf("expected English /pwd in c1, got %q", got)
+	}
+	if got := send("c1", "/help text"); !strings.HasPrefix(got, "Available commands:") {
+		t.Fatalf("expected English help in c1, got %q", got)
+	}
+	if got := send("c2", "/pwd"); got != "当前工作目录：/tmp" {
+		t.Fatalf("expected default language in c2, got %q", got)
+	}
+	if got, _ := b.turnResponse("c1", "", codex.TurnCompletedParams{Status: "failed"}); got != englishMessages.TurnFailed {
+		t.Fatalf("expected an English failure notice in c1, got %q", got)
+	}
+	if got := b.formatRecentErrors("c1", false); got != englishMessages.ErrorsNone {
+		t.Fatalf("expected English /errors in c1, got %q", got)
+	}
+	if got := send("c1", "/lang fr"); got != "⚠️ Usage: /lang <zh|en>" {
+		t.Fatalf("unexpected reply to unsupported language: %q", got)
+	}
+	if got := send("c1", "/lang"); got != "Language: English (en) (switch with /lang zh|en)" {
+		t.Fatalf("unexpected /lang reply: %q", got)
+	}
+
+	// The choice survives a restart.
+	b2, _ := newTestBridge(t, restartOf(b))
+	b2.loadChatLanguages()
+	if got := b2.chatLanguage("c1"); got != LanguageEN {
+		t.Fatalf("expected persisted language en, got %q", got)
+	}
+}
+
+func TestHandleFeishuMessageRejected_UsesChatLanguage(t *testing.T) {
+	b, m := newTestBridge(t)
+	b.setLanguage("c1", "en")
+
+	b.handleFeishuMessageRejected(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "file"})
+	b.handleFeishuMessageRejected(&feishu.Message{ChatID: "c2", ChatType: "p2p", MsgID: "m2", MsgType: "file"})
+
+	if got := findReplyText(m, "m1"); got != "⚠️ file messages are not enabled here" {
+		t.Fatalf("unexpected English notice: %q", got)
+	}
+	if got := findReplyText(m, "m2"); got != "⚠️ 当前未开启 file 类型消息的处理" {
+		t.Fatalf("unexpected default notice: %q", got)
+	}
+}
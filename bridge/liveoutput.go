@@ -0,0 +1,232 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+const (
+	// liveOutputInterval is the minimum gap between edits of a live output
+	// message; Feishu rate-limits message updates.
+	liveOutputInterval = 3 * time.Second
+	// liveOutputMaxEdits caps edits per message (Feishu allows 20); the last
+	// one is kept for the final output.
+	liveOutputMaxEdits = 20
+	// liveOutputTailLines is how many trailing output lines are shown.
+	liveOutputTailLines = 15
+	// liveOutputMaxBytes caps the buffered output per command.
+	liveOutputMaxBytes = 16 << 10
+)
+
+// liveOutput is the live-updating message for one running command.
+type liveOutput struct {
+	mu            sync.Mutex
+	chatID        string
+	replyTo       string
+	replyInThread bool
+	command       string
+	output        string
+	exitCode      *int
+	msgID         string // the posted message; empty until the first flush
+	sent          string // last text posted or edited in
+	edits         int
+	lastEdit      time.Time
+	timer         *time.Timer
+	done          bool
+}
+
+// trackLiveOutput starts buffering output for a commandExecution item when
+// Config.LiveCommandOutput is set.
+func (b *Bridge) trackLiveOutput(chatID string, item *codex.ThreadItem) {
+	if !b.config.LiveCommandOutput || item == nil || item.Type != "commandExecution" || item.ID == "" {
+		return
+	}
+	b.liveOutput(chatID, item.ID).setCommand(item.Command)
+}
+
+func (b *Bridge) liveOutput(chatID, itemID string) *liveOutput {
+	b.liveOutputsMu.Lock()
+	defer b.liveOutputsMu.Unlock()
+	if lo, ok := b.liveOutputs[itemID]; ok {
+		return lo
+	}
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	lo := &liveOutput{
+		chatID:        chatID,
+		replyTo:       state.MsgID,
+		replyInThread: state.ChatType == "group",
+	}
+	state.mu.Unlock()
+	if b.liveOutputs == nil {
+		b.liveOutputs = make(map[string]*liveOutput)
+	}
+	b.liveOutputs[itemID] = lo
+	return lo
+}
+
+func (lo *liveOutput) setCommand(command string) {
+	lo.mu.Lock()
+	lo.command = command
+	lo.mu.Unlock()
+}
+
+// handleCommandOutputDelta appends streamed command output and refreshes the
+// live message, at most once per liveOutputInterval.
+func (b *Bridge) handleCommandOutputDelta(params codex.CommandExecutionOutputDeltaParams) {
+	if !b.config.LiveCommandOutput || params.ItemID == "" || params.Delta == "" {
+		return
+	}
+	chatID := b.findChatByThread(params.ThreadID)
+	if chatID == "" {
+		return
+	}
+	lo := b.liveOutput(chatID, params.ItemID)
+	lo.mu.Lock()
+	lo.output += params.Delta
+	if over := len(lo.output) - liveOutputMaxBytes; over > 0 {
+		lo.output = lo.output[over:]
+		for len(lo.output) > 0 && !utf8.RuneStart(lo.output[0]) {
+			lo.output = lo.output[1:]
+		}
+	}
+	lo.mu.Unlock()
+	b.flushLiveOutput(lo, false)
+}
+
+// finishLiveOutput posts the final state of the item's live message and
+// stops tracking it. It reports whether a live message was shown, so the
+// caller can skip forwarding the same command again.
+func (b *Bridge) finishLiveOutput(item *codex.ThreadItem) bool {
+	if item == nil {
+		return false
+	}
+	b.liveOutputsMu.Lock()
+	lo := b.liveOutputs[item.ID]
+	delete(b.liveOutputs, item.ID)
+	b.liveOutputsMu.Unlock()
+	if lo == nil {
+		return false
+	}
+
+	lo.mu.Lock()
+	if item.Command != "" {
+		lo.command = item.Command
+	}
+	lo.exitCode = item.ExitCode
+	lo.mu.Unlock()
+	b.flushLiveOutput(lo, true)
+
+	lo.mu.Lock()
+	defer lo.mu.Unlock()
+	return lo.msgID != ""
+}
+
+// finishChatLiveOutputs finalizes the live messages of a chat whose turn
+// ended without item/completed for every command (e.g. interrupted).
+func (b *Bridge) finishChatLiveOutputs(chatID string) {
+	b.liveOutputsMu.Lock()
+	var outputs []*liveOutput
+	for id, lo := range b.liveOutputs {
+		if lo.chatID == chatID {
+			outputs = append(outputs, lo)
+			delete(b.liveOutputs, id)
+		}
+	}
+	b.liveOutputsMu.Unlock()
+	for _, lo := range outputs {
+		b.flushLiveOutput(lo, true)
+	}
+}
+
+// flushLiveOutput posts or edits the live message. Non-final flushes within
+// liveOutputInterval of the last edit are deferred to a timer instead.
+func (b *Bridge) flushLiveOutput(lo *liveOutput, final bool) {
+	lo.mu.Lock()
+	defer lo.mu.Unlock()
+	if lo.done || lo.replyTo == "" || lo.output == "" {
+		if final {
+			lo.done = true
+		}
+		return
+	}
+	if final {
+		lo.done = true
+		if lo.timer != nil {
+			lo.timer.Stop()
+			lo.timer = nil
+		}
+	} else if lo.msgID != "" {
+		if lo.edits >= liveOutputMaxEdits-1 {
+			return
+		}
+		if wait := liveOutputInterval - time.Since(lo.lastEdit); wait > 0 {
+			if lo.timer == nil {
+				lo.timer = time.AfterFunc(wait, func() {
+					lo.mu.Lock()
+					lo.timer = nil
+					lo.mu.Unlock()
+					b.flushLiveOutput(lo, false)
+				})
+			}
+			return
+		}
+	}
+
+	text := formatLiveOutput(lo.command, lo.output, lo.exitCode, final)
+	if text == lo.sent {
+		return
+	}
+	if lo.msgID == "" {
+		id, err := b.replyTextWithID(lo.chatID, lo.replyTo, text, lo.replyInThread)
+		if err != nil {
+			b.debugf("Failed to post live output: %v", err)
+			return
+		}
+		lo.msgID = id
+	} else {
+		if lo.edits >= liveOutputMaxEdits {
+			return
+		}
+		if err := b.feishuClient.UpdateText(lo.msgID, text); err != nil {
+			b.debugf("Failed to update live output %s: %v", lo.msgID, err)
+			return
+		}
+		lo.edits++
+	}
+	lo.sent = text
+	lo.lastEdit = time.Now()
+}
+
+// formatLiveOutput renders the command and the last liveOutputTailLines
+// lines of its output.
+func formatLiveOutput(command, output string, exitCode *int, final bool) string {
+	var sb strings.Builder
+	if final {
+		sb.WriteString("🖥 $ ")
+	} else {
+		sb.WriteString("⏳ $ ")
+	}
+	sb.WriteString(truncateRunes(strings.Join(strings.Fields(command), " "), maxStepDetailChars))
+	if final && exitCode != nil && *exitCode != 0 {
+		fmt.Fprintf(&sb, " (exit %d)", *exitCode)
+	}
+	sb.WriteString("\n")
+	sb.WriteString(tailLines(strings.TrimRight(output, "\n"), liveOutputTailLines))
+	return sb.String()
+}
+
+// tailLines returns the last n lines of s, prefixed with "…" when earlier
+// lines were dropped.
+func tailLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) <= n {
+		return s
+	}
+	return "…\n" + strings.Join(lines[len(lines)-n:], "\n")
+}
@@ -0,0 +1,77 @@
+package bridge
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+func TestFormatLiveOutput(t *testing.T) {
+	exit := 1
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, strings.Repeat("x", i))
+	}
+	got := formatLiveOutput("go   test ./...", strings.Join(lines, "\n")+"\n", &exit, true)
+	want := "🖥 $ go test ./... (exit 1)\n…\n" + strings.Join(lines[5:], "\n")
+	if got != want {
+		t.Fatalf("formatLiveOutput = %q, want %q", got, want)
+	}
+	if got := formatLiveOutput("make", "ok", nil, false); got != "⏳ $ make\nok" {
+		t.Fatalf("unexpected running output %q", got)
+	}
+}
+
+func TestHandleEvent_LiveCommandOutput(t *testing.T) {
+	b, m := newTestBridge(t)
+	b.config.LiveCommandOutput = true
+	b.config.VerboseP2P = true
+	state := b.getChatState("c1")
+	state.ThreadID = "t1"
+	state.MsgID = "m1"
+	state.Processing = true
+	state.Verbose = true
+
+	event := func(method string, params any) codex.Event {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return codex.Event{Method: method, Params: raw}
+	}
+
+	b.handleEvent(event(codex.MethodItemStarted, codex.ItemStartedParams{
+		ThreadID: "t1",
+		Item:     &codex.ThreadItem{Type: "commandExecution", ID: "i1", Command: "go test"},
+	}))
+	b.handleEvent(event(codex.MethodCommandExecutionOutputDelta, codex.CommandExecutionOutputDeltaParams{
+		ThreadID: "t1", ItemID: "i1", Delta: "=== RUN TestA\n",
+	}))
+	// Within liveOutputInterval: deferred, not edited yet.
+	b.handleEvent(event(codex.MethodCommandExecutionOutputDelta, codex.CommandExecutionOutputDeltaParams{
+		ThreadID: "t1", ItemID: "i1", Delta: "--- FAIL: TestA\n",
+	}))
+	if len(m.SentMessages) != 1 || m.SentMessages[0].Text != "⏳ $ go test\n=== RUN TestA" {
+		t.Fatalf("expected one live output reply, got %+v", m.SentMessages)
+	}
+	if len(m.UpdatedMessages) != 0 {
+		t.Fatalf("expected edits to be rate-limited, got %+v", m.UpdatedMessages)
+	}
+
+	exit := 1
+	b.handleEvent(event(codex.MethodItemCompleted, codex.ItemCompletedParams{
+		ThreadID: "t1",
+		Item:     &codex.ThreadItem{Type: "commandExecution", ID: "i1", Command: "go test", ExitCode: &exit},
+	}))
+	if len(m.UpdatedMessages) != 1 || m.UpdatedMessages[0].Text != "🖥 $ go test (exit 1)\n=== RUN TestA\n--- FAIL: TestA" {
+		t.Fatalf("expected final edit, got %+v", m.UpdatedMessages)
+	}
+	if len(m.SentMessages) != 1 {
+		t.Fatalf("expected verbose forwarding to be skipped for a live command, got %+v", m.SentMessages)
+	}
+	if len(b.liveOutputs) != 0 {
+		t.Fatalf("expected live output to be dropped, got %d", len(b.liveOutputs))
+	}
+}
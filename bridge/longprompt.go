@@ -0,0 +1,50 @@
+package bridge
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Supported values for Config.LongPromptPolicy.
+const (
+	// LongPromptTrim cuts the middle out of an over-long message and tells
+	// the chat (the default).
+	LongPromptTrim = "trim"
+	// LongPromptReject drops an over-long message and asks for a shorter one.
+	LongPromptReject = "reject"
+)
+
+// limitPrompt applies Config.MaxPromptChars to a message's text. It returns
+// the text to send and false when the message was rejected.
+func (b *Bridge) limitPrompt(chatID, text string, sendReply func(string) bool) (string, bool) {
+	max := b.config.MaxPromptChars
+	if max <= 0 {
+		return text, true
+	}
+	n := utf8.RuneCountInString(text)
+	if n <= max {
+		return text, true
+	}
+	msgs := b.chatMessages(chatID)
+	if b.config.LongPromptPolicy == LongPromptReject {
+		sendReply(fmt.Sprintf(msgs.PromptTooLong, n, max))
+		return "", false
+	}
+	trimmed, dropped := trimPrompt(text, max)
+	sendReply(fmt.Sprintf(msgs.PromptTrimmed, n, dropped))
+	return trimmed, true
+}
+
+// trimPrompt keeps the first and last max/2 runes of s, which usually hold
+// the question and the end of a pasted log, and marks the cut. It returns
+// the trimmed text and how many runes were dropped.
+func trimPrompt(s string, max int) (string, int) {
+	runes := []rune(s)
+	if max <= 0 || len(runes) <= max {
+		return s, 0
+	}
+	head := max / 2
+	tail := max - head
+	dropped := len(runes) - max
+	return fmt.Sprintf("%s\n…（中间省略 %d 字）…\n%s", string(runes[:head]), dropped, string(runes[len(runes)-tail:])), dropped
+}
@@ -0,0 +1,55 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTrimPrompt_RuneAware(t *testing.T) {
+	s := strings.Repeat("日志", 10) // 20 runes, 60 bytes
+	got, dropped := trimPrompt(s, 8)
+	if dropped != 12 {
+		t.Fatalf("dropped = %d, want 12", dropped)
+	}
+	if !utf8.ValidString(got) {
+		t.Fatalf("trimmed prompt is not valid UTF-8: %q", got)
+	}
+	want := "日志日志\n…（中间省略 12 字）…\n日志日志"
+	if got != want {
+		t.Fatalf("trimPrompt = %q, want %q", got, want)
+	}
+
+	if got, dropped := trimPrompt("短消息", 8); got != "短消息" || dropped != 0 {
+		t.Fatalf("short prompt changed: %q dropped=%d", got, dropped)
+	}
+}
+
+func TestLimitPrompt(t *testing.T) {
+	b := &Bridge{config: Config{MaxPromptChars: 4}, chatStates: make(map[string]*ChatState)}
+	var replies []string
+	sendReply := func(text string) bool {
+		replies = append(replies, text)
+		return true
+	}
+
+	if got, ok := b.limitPrompt("c1", "四个字符", sendReply); !ok || got != "四个字符" || len(replies) != 0 {
+		t.Fatalf("prompt at the limit should pass untouched, got %q ok=%v replies=%v", got, ok, replies)
+	}
+
+	got, ok := b.limitPrompt("c1", "一二三四五六", sendReply)
+	if !ok || got != "一二\n…（中间省略 2 字）…\n五六" {
+		t.Fatalf("expected trimmed prompt, got %q ok=%v", got, ok)
+	}
+	if len(replies) != 1 || replies[0] != "⚠️ 消息过长（6 字），已省略中间 2 字后发给 Codex" {
+		t.Fatalf("unexpected notice %v", replies)
+	}
+
+	b.config.LongPromptPolicy = LongPromptReject
+	if _, ok := b.limitPrompt("c1", "一二三四五六", sendReply); ok {
+		t.Fatal("expected the prompt to be rejected")
+	}
+	if len(replies) != 2 || !strings.Contains(replies[1], "上限 4 字") {
+		t.Fatalf("unexpected rejection %v", replies)
+	}
+}
@@ -0,0 +1,658 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Supported values for Config.Language.
+const (
+	LanguageZH = "zh"
+	LanguageEN = "en"
+)
+
+// normalizeLanguage maps a configured language (e.g. "en-US") to one of the
+// supported languages; empty means LanguageZH.
+func normalizeLanguage(lang string) (string, error) {
+	l := strings.ToLower(strings.TrimSpace(lang))
+	l, _, _ = strings.Cut(strings.ReplaceAll(l, "_", "-"), "-")
+	switch l {
+	case "", LanguageZH:
+		return LanguageZH, nil
+	case LanguageEN:
+		return LanguageEN, nil
+	}
+	return "", fmt.Errorf("unsupported language %q (want zh or en)", lang)
+}
+
+// Messages holds user-facing strings. Config.Language picks the built-in
+// set, and Config.MessagesFile can override individual strings on top of it.
+// Fields noted as format strings are passed through fmt with the listed verb.
+// Command names are formatted in too (%s, after any other argument), so
+// replies follow Config.CommandPrefix.
+type Messages struct {
+	// NoTextResponse is sent when a turn completes without any text.
+	NoTextResponse string `json:"no_text_response"`
+	// TurnFailed is appended to the output of a failed turn; TurnInterrupted
+	// leads that of an interrupted one.
+	TurnFailed      string `json:"turn_failed"`
+	TurnInterrupted string `json:"turn_interrupted"`
+	// CreateThreadFailed and SendRequestFailed label codex request errors.
+	CreateThreadFailed string `json:"create_thread_failed"`
+	SendRequestFailed  string `json:"send_request_failed"`
+	// ReasonSeparator joins a failure to its reason or hint.
+	ReasonSeparator string `json:"reason_separator"`
+	// CodexNotLoggedIn, CodexQuotaExhausted, CodexRateLimited and
+	// CodexContextTooLong (format, %s = the /clear command) replace the raw
+	// text of a recognized codex error.
+	CodexNotLoggedIn    string `json:"codex_not_logged_in"`
+	CodexQuotaExhausted string `json:"codex_quota_exhausted"`
+	CodexRateLimited    string `json:"codex_rate_limited"`
+	CodexContextTooLong string `json:"codex_context_too_long"`
+	// GaveUpAfter is appended to a failure once retries are exhausted
+	// (format, %d = attempts).
+	GaveUpAfter string `json:"gave_up_after"`
+	// CodexUnavailable rejects a message while codex is down (see
+	// Config.CodexDownPolicy).
+	CodexUnavailable string `json:"codex_unavailable"`
+	// CodexWarmingUp answers a message while codex is starting, under
+	// WarmupReply.
+	CodexWarmingUp string `json:"codex_warming_up"`
+	// CodexCrashed tells a chat with an in-flight turn that codex died.
+	CodexCrashed string `json:"codex_crashed"`
+	// IdleWarning warns that a resumed session is close to its idle reset
+	// (format, %d = minutes left; see Config.IdleWarnMinutes).
+	IdleWarning string `json:"idle_warning"`
+	// NewThreadGreeting announces a fresh thread under
+	// Config.GreetOnNewThread (format, %s = working directory).
+	NewThreadGreeting string `json:"new_thread_greeting"`
+	// PromptTrimmed (format, %d = length, dropped runes) and PromptTooLong
+	// (format, %d = length, limit) apply Config.MaxPromptChars.
+	PromptTrimmed string `json:"prompt_trimmed"`
+	PromptTooLong string `json:"prompt_too_long"`
+	// MoreNone answers /more when no truncated reply is pending.
+	MoreNone string `json:"more_none"`
+	// ResponseTruncated ends a truncated reply (format, %s = the /more
+	// command with the configured prefix).
+	ResponseTruncated string `json:"response_truncated"`
+	// EmptyPrompt answers a message with neither text nor images.
+	EmptyPrompt string `json:"empty_prompt"`
+	// ImagePrompt is sent to codex in place of the text of an image-only
+	// message, unless Config.ImagePrompt is set.
+	ImagePrompt string `json:"image_prompt"`
+	// ImagesCapped (format, %d = kept, dropped) and ImageTooLarge (format,
+	// %s = size limit) note images that weren't sent to codex.
+	ImagesCapped  string `json:"images_capped"`
+	ImageTooLarge string `json:"image_too_large"`
+	// MsgTypeDisabled answers a message whose type isn't in
+	// ACCEPTED_MSG_TYPES (format, %s = message type).
+	MsgTypeDisabled string `json:"msg_type_disabled"`
+	// QueueFull is sent when a chat's queue can't take more messages.
+	QueueFull string `json:"queue_full"`
+	// Queued acknowledges a queued message (format, %d = messages ahead).
+	Queued string `json:"queued"`
+	// CommandTooFrequent rejects a command repeated within its cooldown.
+	CommandTooFrequent string `json:"command_too_frequent"`
+	// ClearDone confirms /clear.
+	ClearDone string `json:"clear_done"`
+	// NewDone confirms /new; NewBusy (format, %s = the /clear command)
+	// refuses it while a turn runs.
+	NewDone string `json:"new_done"`
+	NewBusy string `json:"new_busy"`
+	// Replies to /bind. BindDone (%s = thread), BindFailed (%s = thread,
+	// %v = error), BindInUse (%s = thread) and BindUsage (%s = the command)
+	// are format strings; BindBusy refuses it while a turn runs.
+	BindDone   string `json:"bind_done"`
+	BindFailed string `json:"bind_failed"`
+	BindInUse  string `json:"bind_in_use"`
+	BindBusy   string `json:"bind_busy"`
+	BindUsage  string `json:"bind_usage"`
+	// ResetDone and ResetFailed (format, %v = error) report /reset.
+	ResetDone   string `json:"reset_done"`
+	ResetFailed string `json:"reset_failed"`
+	// SwitchDirDone (format, %s = new dir) and SwitchDirFailed (format,
+	// %v = error) report /cd.
+	SwitchDirDone   string `json:"switch_dir_done"`
+	SwitchDirFailed string `json:"switch_dir_failed"`
+	// SwitchDirUsage (format, %s = the command) answers a bare /cd without
+	// workspaces. The picker card
+	// has WorkspacePickerTitle and WorkspacePicker (format, %s = current dir);
+	// a click shows WorkspaceSwitching (format, %s = name), or
+	// WorkspaceUnknown for a workspace no longer configured,
+	// WorkspaceSwitchBusy while another switch runs and
+	// WorkspaceNotRequester for a click by someone other than the member
+	// who @-mentioned the bot.
+	SwitchDirUsage        string `json:"switch_dir_usage"`
+	WorkspacePickerTitle  string `json:"workspace_picker_title"`
+	WorkspacePicker       string `json:"workspace_picker"`
+	WorkspaceSwitching    string `json:"workspace_switching"`
+	WorkspaceUnknown      string `json:"workspace_unknown"`
+	WorkspaceSwitchBusy   string `json:"workspace_switch_busy"`
+	WorkspaceNotRequester string `json:"workspace_not_requester"`
+	// AdminOnly rejects admin commands from other users.
+	AdminOnly string `json:"admin_only"`
+	// ShowDir answers /pwd (format, %s = dir).
+	ShowDir string `json:"show_dir"`
+
+	// Status lines for /status and /queue. Pending is a format string
+	// (%d = queued messages), StatusStep too (%s = current step).
+	StatusIdle        string `json:"status_idle"`
+	StatusPaused      string `json:"status_paused"`
+	StatusProcessing  string `json:"status_processing"`
+	StatusStep        string `json:"status_step"`
+	StatusDefaultStep string `json:"status_default_step"`
+	Pending           string `json:"pending"`
+	// Elapsed answers /elapsed during a turn (format, %s = running time,
+	// %s = current step); ElapsedStarting while a message is being handled
+	// but its turn hasn't started yet; ElapsedIdle otherwise.
+	Elapsed         string `json:"elapsed"`
+	ElapsedStarting string `json:"elapsed_starting"`
+	ElapsedIdle     string `json:"elapsed_idle"`
+	// Git lines in /status (format, %s = branch).
+	GitBranch      string `json:"git_branch"`
+	GitBranchDirty string `json:"git_branch_dirty"`
+	GitBranchClean string `json:"git_branch_clean"`
+	GitNoCommits   string `json:"git_no_commits"`
+	// Progress is the periodic update posted during a long turn (format,
+	// %s = running time, %s = current step).
+	Progress string `json:"progress"`
+	// Step labels name what codex is doing in /status, /elapsed and progress
+	// updates; StatusDefaultStep labels a reply being generated.
+	StepReasoning  string `json:"step_reasoning"`
+	StepCommand    string `json:"step_command"`
+	StepFileChange string `json:"step_file_change"`
+	StepToolCall   string `json:"step_tool_call"`
+	StepWebSearch  string `json:"step_web_search"`
+	StepImageView  string `json:"step_image_view"`
+
+	// Replies to /pause and /resume. PauseFailed and ResumeFailed are format
+	// strings (%v = error), ResumedWithHeld too (%d = held messages), and
+	// PauseDone (%s = the /resume command).
+	PauseAlready    string `json:"pause_already"`
+	PauseFailed     string `json:"pause_failed"`
+	PauseDone       string `json:"pause_done"`
+	ResumeNotPaused string `json:"resume_not_paused"`
+	ResumeFailed    string `json:"resume_failed"`
+	ResumeDone      string `json:"resume_done"`
+	ResumedWithHeld string `json:"resumed_with_held"`
+	// PausedHold acknowledges a message held while the chat is paused
+	// (format, %s = the /resume command).
+	PausedHold string `json:"paused_hold"`
+
+	// VerboseOn and VerboseOff confirm /verbose.
+	VerboseOn  string `json:"verbose_on"`
+	VerboseOff string `json:"verbose_off"`
+
+	// Replies to /effort. EffortSet (%s = effort), EffortCurrent (%s =
+	// effort, command) and EffortUsage (%s = command) are format strings;
+	// EffortDefault names the unset effort.
+	EffortSet     string `json:"effort_set"`
+	EffortCurrent string `json:"effort_current"`
+	EffortDefault string `json:"effort_default"`
+	EffortUsage   string `json:"effort_usage"`
+
+	// Replies to /stream. StreamSet (%s = mode), StreamCurrent (%s = mode,
+	// command) and StreamUsage (%s = command) are format strings.
+	StreamSet     string `json:"stream_set"`
+	StreamCurrent string `json:"stream_current"`
+	StreamUsage   string `json:"stream_usage"`
+
+	// Replies to /lang. LangCurrent (%s = language, command) and LangUsage
+	// (%s = command) are format strings.
+	LangSet     string `json:"lang_set"`
+	LangCurrent string `json:"lang_current"`
+	LangUsage   string `json:"lang_usage"`
+
+	// Replies to /model. ModelSet (%s = model), ModelCurrent (%s = model,
+	// command) and ModelUsage (%s = command) are format strings;
+	// ModelDefault names codex's own default model.
+	ModelSet     string `json:"model_set"`
+	ModelCurrent string `json:"model_current"`
+	ModelDefault string `json:"model_default"`
+	ModelUsage   string `json:"model_usage"`
+
+	// Replies to /approvals. ApprovalsSet and ApprovalsGlobalSet (%s =
+	// policy), ApprovalsCurrent (%s = policy, command) and ApprovalsUsage
+	// (%s = command) are format strings.
+	ApprovalsCurrent   string `json:"approvals_current"`
+	ApprovalsSet       string `json:"approvals_set"`
+	ApprovalsGlobalSet string `json:"approvals_global_set"`
+	ApprovalsUsage     string `json:"approvals_usage"`
+	// Approval prompts under APPROVAL_POLICY=ask: ApprovalCommand (%s =
+	// command), ApprovalCwd (%s = directory) and ApprovalFileChange (%s =
+	// one path per line) are format strings; ApprovalFooter ends the prompt.
+	// ApprovalAccepted, ApprovalDeclined and ApprovalTimedOut answer it.
+	ApprovalCommand    string `json:"approval_command"`
+	ApprovalCwd        string `json:"approval_cwd"`
+	ApprovalFileChange string `json:"approval_file_change"`
+	ApprovalFooter     string `json:"approval_footer"`
+	ApprovalAccepted   string `json:"approval_accepted"`
+	ApprovalDeclined   string `json:"approval_declined"`
+	ApprovalTimedOut   string `json:"approval_timed_out"`
+
+	// ErrorsHeader (format, %d = count) and ErrorsNone answer /errors.
+	ErrorsHeader string `json:"errors_header"`
+	ErrorsNone   string `json:"errors_none"`
+	// VersionInfo (format, %s = bridge version, codex version) answers
+	// /version; VersionUnknown stands in for an undetected codex version.
+	VersionInfo    string `json:"version_info"`
+	VersionUnknown string `json:"version_unknown"`
+
+	// Replies to /ask. AskUsage and AskBusy, which answers while the chat's
+	// previous /ask runs, are format strings (%s = the command).
+	AskDisabled string `json:"ask_disabled"`
+	AskUsage    string `json:"ask_usage"`
+	AskBusy     string `json:"ask_busy"`
+
+	// Replies to /commit. CommitStatusFailed and CommitFailed are format
+	// strings (%v = error), as are CommitConfirm (%d = files),
+	// CommitConfirmFooter (%s = commit message) and CommitDone (%s = hash).
+	CommitNotRepo       string `json:"commit_not_repo"`
+	CommitStatusFailed  string `json:"commit_status_failed"`
+	CommitNothing       string `json:"commit_nothing"`
+	CommitConfirm       string `json:"commit_confirm"`
+	CommitConfirmFooter string `json:"commit_confirm_footer"`
+	CommitCancelled     string `json:"commit_cancelled"`
+	CommitTimeout       string `json:"commit_timeout"`
+	CommitFailed        string `json:"commit_failed"`
+	CommitDone          string `json:"commit_done"`
+	// ListMore ends a capped file list in /changes and /commit (format,
+	// %d = files left out).
+	ListMore string `json:"list_more"`
+	// ChangesNone and ChangesHeader (format, %d = files) answer /changes.
+	ChangesNone   string `json:"changes_none"`
+	ChangesHeader string `json:"changes_header"`
+	// Replies to /export. ExportFailed is a format string (%v = error),
+	// ExportTruncated too (%d = parts sent). ExportTurnTitle titles the post
+	// of each turn (format, %d = turn number).
+	ExportNone      string `json:"export_none"`
+	ExportFailed    string `json:"export_failed"`
+	ExportTruncated string `json:"export_truncated"`
+	ExportTurnTitle string `json:"export_turn_title"`
+
+	// Stats answers /stats (format, %s = uptime, then %d = turns started,
+	// completed, failed, interrupted, queued messages, running turns).
+	// StatsLimit follows it under MAX_CONCURRENT_TURNS (%d = limit,
+	// waiting); StatsNotStarted stands in for the uptime before Start.
+	Stats           string `json:"stats"`
+	StatsLimit      string `json:"stats_limit"`
+	StatsNotStarted string `json:"stats_not_started"`
+	// CleanupDone (format, %d = sessions, chat states) and CleanupFailed
+	// (format, %v = error) answer /cleanup.
+	CleanupDone   string `json:"cleanup_done"`
+	CleanupFailed string `json:"cleanup_failed"`
+
+	// Welcome opens the intro posted when the bot joins a group; the
+	// command list follows it.
+	Welcome string `json:"welcome"`
+	// StartupSummary is posted to Config.AuditChatID once the bridge is up
+	// (format, %s = version, model, working dir, session DB).
+	StartupSummary string `json:"startup_summary"`
+	// ShuttingDown is posted to Config.AuditChatID on shutdown.
+	ShuttingDown string `json:"shutting_down"`
+}
+
+var defaultMessages = Messages{
+	NoTextResponse:      "✅（无文字回应）",
+	TurnFailed:          "❌ 本轮任务失败",
+	TurnInterrupted:     "⏹ 本轮任务已中断",
+	CreateThreadFailed:  "创建会话失败",
+	SendRequestFailed:   "发送请求失败",
+	ReasonSeparator:     "：",
+	CodexNotLoggedIn:    "Codex 未登录或登录已过期，请在服务器上运行 codex login 后重试",
+	CodexQuotaExhausted: "Codex 额度已用完，请检查账号用量或稍后再试",
+	CodexRateLimited:    "Codex 请求过于频繁，请稍后再试",
+	CodexContextTooLong: "会话上下文过长，请发送 %s 后重新开始",
+	GaveUpAfter:         "（已尝试 %d 次仍失败，已跳过这条消息）",
+	CodexUnavailable:    "⚠️ 服务暂时不可用，请稍后重试",
+	CodexWarmingUp:      "启动中，请稍候",
+	CodexCrashed:        "⚠️ Codex 意外退出，正在重启，请稍后重试",
+	IdleWarning:         "⏰ 提醒：本会话已空闲较久，距离自动重置上下文只差约 %d 分钟",
+	NewThreadGreeting:   "👋 新会话已开始（工作目录：%s）",
+	PromptTrimmed:       "⚠️ 消息过长（%d 字），已省略中间 %d 字后发给 Codex",
+	PromptTooLong:       "⚠️ 消息过长（%d 字，上限 %d 字），请精简后重试，长日志只发送相关部分即可",
+	MoreNone:            "没有更多内容了",
+	ResponseTruncated:   "（回复过长，已截断，发送 %s 查看后续）",
+	EmptyPrompt:         "🤔 没有收到文字内容，请直接输入想问的问题",
+	ImagePrompt:         "请描述并分析图片内容",
+	ImagesCapped:        "⚠️ 图片过多，只处理前 %d 张，其余 %d 张已忽略",
+	ImageTooLarge:       "⚠️ 图片超过大小限制（%s），已忽略",
+	MsgTypeDisabled:     "⚠️ 当前未开启 %s 类型消息的处理",
+	QueueFull:           "⚠️ 排队消息过多，请稍后再试。",
+	Queued:              "⏳ 已加入队列（前面还有 %d 条）",
+	CommandTooFrequent:  "⚠️ 操作过于频繁，请稍后再试",
+	ClearDone:           "✅ 已清空当前会话上下文",
+	NewDone:             "✅ 已开启新会话，本会话的设置保持不变",
+	NewBusy:             "⚠️ 当前任务还在处理中，请等它结束后再开启新会话（或用 %s 中断）",
+	BindDone:            "🔗 已将本会话绑定到 Codex 线程 %s，后续消息会在该线程中继续",
+	BindFailed:          "❌ 无法恢复线程 %s：%v",
+	BindInUse:           "⚠️ 线程 %s 正被其他会话使用",
+	BindBusy:            "⚠️ 当前任务还在处理中，请等它结束后再绑定",
+	BindUsage:           "⚠️ 用法：%s <线程 ID>",
+	ResetDone:           "✅ 已重置",
+	ResetFailed:         "❌ 重置失败：%v",
+	SwitchDirDone:       "✅ 已切换到新的工作目录：%s",
+	SwitchDirFailed:     "❌ 切换工作目录失败：%v",
+	AdminOnly:           "⚠️ 该命令仅管理员可用",
+	ShowDir:             "当前工作目录：%s",
+
+	SwitchDirUsage:        "⚠️ 用法：%s <绝对路径>",
+	WorkspacePickerTitle:  "切换工作目录",
+	WorkspacePicker:       "当前：%s\n点击按钮切换到以下工作区：",
+	WorkspaceSwitching:    "正在切换到 %s…",
+	WorkspaceUnknown:      "该工作区已不在配置中",
+	WorkspaceSwitchBusy:   "正在切换工作目录，请稍候",
+	WorkspaceNotRequester: "只有发起请求的成员可以选择工作区",
+
+	StatusIdle:        "状态：空闲",
+	StatusPaused:      "状态：已暂停",
+	StatusProcessing:  "状态：处理中",
+	StatusStep:        "当前步骤：%s",
+	StatusDefaultStep: "生成回复",
+	Pending:           "待处理：%d",
+	Elapsed:           "已运行 %s，当前步骤：%s",
+	ElapsedStarting:   "⏳ 任务正在启动，尚未开始计时",
+	ElapsedIdle:       "当前无进行中的任务",
+	GitBranch:         "分支：%s",
+	GitBranchDirty:    "分支：%s（有未提交改动）",
+	GitBranchClean:    "分支：%s（工作区干净）",
+	GitNoCommits:      "（无提交）",
+	Progress:          "⏳ 处理中 (%s) — %s",
+	StepReasoning:     "思考",
+	StepCommand:       "执行命令",
+	StepFileChange:    "修改文件",
+	StepToolCall:      "调用工具",
+	StepWebSearch:     "搜索网络",
+	StepImageView:     "查看图片",
+
+	PauseAlready:    "本会话已处于暂停状态",
+	PauseFailed:     "❌ 暂停失败：%v",
+	PauseDone:       "⏸ 已暂停本会话的消息处理，发送 %s 恢复",
+	ResumeNotPaused: "本会话未暂停",
+	ResumeFailed:    "❌ 恢复失败：%v",
+	ResumeDone:      "▶️ 已恢复消息处理",
+	ResumedWithHeld: "▶️ 已恢复消息处理，继续处理暂停期间的 %d 条消息",
+	PausedHold:      "⏸ 本会话已暂停，消息会在发送 %s 恢复后处理",
+
+	VerboseOn:  "🔊 已开启详细模式：会转发思考摘要和执行的命令",
+	VerboseOff: "🔇 已关闭详细模式",
+
+	EffortSet:     "✅ 推理强度已设为 %s，从下一轮开始生效",
+	EffortCurrent: "当前推理强度：%s（可用 %s low|medium|high 修改）",
+	EffortDefault: "默认",
+	EffortUsage:   "⚠️ 用法：%s <low|medium|high>",
+
+	StreamSet:     "✅ 输出方式已设为 %s，从下一轮开始生效",
+	StreamCurrent: "当前输出方式：%s（可用 %s final|stream|chunks 修改：final 完成后一次性回复，stream 实时更新同一条回复，chunks 每写完一段就发送）",
+	StreamUsage:   "⚠️ 用法：%s <final|stream|chunks>",
+
+	LangSet:     "🌐 本会话已切换为中文",
+	LangCurrent: "当前语言：%s（可用 %s zh|en 切换）",
+	LangUsage:   "⚠️ 用法：%s <zh|en>",
+
+	ModelSet:     "✅ 本会话模型已设为 %s，已清空上下文，下一条消息将开启新会话",
+	ModelCurrent: "当前模型：%[1]s（可用 %[2]s <名称> 切换，%[2]s default 恢复默认）",
+	ModelDefault: "codex 默认",
+	ModelUsage:   "⚠️ 用法：%s <模型名称|default>",
+
+	ApprovalsCurrent:   "当前审批策略：%s（管理员可用 %s auto|ask|readonly 修改）",
+	ApprovalsSet:       "✅ 本会话的审批策略已设为 %s",
+	ApprovalsGlobalSet: "✅ 默认审批策略已设为 %s（已单独设置的会话不受影响）",
+	ApprovalsUsage:     "⚠️ 用法：%s [global] <auto|ask|readonly>",
+	ApprovalCommand:    "🔐 Codex 请求执行命令：\n%s",
+	ApprovalCwd:        "目录：%s",
+	ApprovalFileChange: "🔐 Codex 请求修改文件：\n%s",
+	ApprovalFooter:     "回应 ✅ 批准，❌ 拒绝（超时自动拒绝）",
+	ApprovalAccepted:   "✅ 已批准",
+	ApprovalDeclined:   "❌ 已拒绝",
+	ApprovalTimedOut:   "⌛ 超时未确认，已拒绝",
+
+	ErrorsHeader: "最近的错误（共 %d 条，最新在前）：",
+	ErrorsNone:   "最近没有错误",
+
+	VersionInfo:    "Bridge 版本：%s\nCodex 版本：%s",
+	VersionUnknown: "未知",
+
+	AskDisabled: "⚠️ 未开启并行提问（PARALLEL_ASK=true）",
+	AskUsage:    "用法：%s <问题>",
+	AskBusy:     "⏳ 上一个 %s 还在处理中，请稍后再试",
+
+	CommitNotRepo:       "❌ 当前工作目录不是 git 仓库",
+	CommitStatusFailed:  "❌ 读取 git 状态失败：%v",
+	CommitNothing:       "没有需要提交的改动",
+	CommitConfirm:       "📝 确认提交以下 %d 个改动？",
+	CommitConfirmFooter: "提交说明：%s\n\n回应 ✅ 提交，❌ 取消（超时自动取消）",
+	CommitCancelled:     "已取消提交",
+	CommitTimeout:       "⌛ 超时未确认，已取消提交",
+	CommitFailed:        "❌ 提交失败：%v",
+	CommitDone:          "✅ 已提交：%s",
+	ListMore:            "…另有 %d 个",
+	ChangesNone:         "本会话还没有修改过文件",
+	ChangesHeader:       "本会话修改过的文件（%d）：",
+	ExportNone:          "当前没有可导出的会话",
+	ExportFailed:        "❌ 读取会话失败：%v",
+	ExportTruncated:     "（会话记录过长，仅导出前 %d 段）",
+	ExportTurnTitle:     "第 %d 轮",
+
+	Stats:           "运行时长：%s\n已开始：%d\n已完成：%d\n失败：%d\n中断：%d\n排队消息：%d\n进行中：%d",
+	StatsLimit:      "（上限 %d，等待 %d）",
+	StatsNotStarted: "未启动",
+	CleanupDone:     "🧹 已清理 %d 个过期会话，释放 %d 个空闲 chat 状态",
+	CleanupFailed:   "❌ 清理失败：%v",
+
+	Welcome: "👋 大家好，我会把消息转给 Codex 处理，@我 发送任务即可开始。",
+
+	StartupSummary: "🚀 已启动\n版本：%s\n模型：%s\n工作目录：%s\n会话库：%s",
+	ShuttingDown:   "🛑 正在关闭",
+}
+
+var englishMessages = Messages{
+	NoTextResponse:      "✅ (no text response)",
+	TurnFailed:          "❌ This turn failed",
+	TurnInterrupted:     "⏹ This turn was interrupted",
+	CreateThreadFailed:  "Failed to create session",
+	SendRequestFailed:   "Failed to send request",
+	ReasonSeparator:     ": ",
+	CodexNotLoggedIn:    "Codex is not logged in or the login has expired; run codex login on the server and try again",
+	CodexQuotaExhausted: "Codex usage quota is exhausted; check the account's usage or try again later",
+	CodexRateLimited:    "Too many requests to Codex; please try again later",
+	CodexContextTooLong: "The session context is too long; send %s to start over",
+	GaveUpAfter:         " (gave up after %d attempts, skipping this message)",
+	CodexUnavailable:    "⚠️ The service is temporarily unavailable, please try again later",
+	CodexWarmingUp:      "Starting up, please wait",
+	CodexCrashed:        "⚠️ Codex exited unexpectedly and is restarting, please try again shortly",
+	IdleWarning:         "⏰ Heads-up: this session has been idle for a while; its context resets automatically in about %d minutes",
+	NewThreadGreeting:   "👋 New session started (working directory: %s)",
+	PromptTrimmed:       "⚠️ Message too long (%d chars); sent to Codex with %d chars cut from the middle",
+	PromptTooLong:       "⚠️ Message too long (%d chars, limit %d); please shorten it, e.g. send only the relevant part of a log",
+	MoreNone:            "Nothing more to show",
+	ResponseTruncated:   "(Reply too long and truncated; send %s for the rest)",
+	EmptyPrompt:         "🤔 The message had no text; just type what you'd like to ask",
+	ImagePrompt:         "Please describe and analyze this image",
+	ImagesCapped:        "⚠️ Too many images; only the first %d were used, %d ignored",
+	ImageTooLarge:       "⚠️ Image ignored: larger than the size limit (%s)",
+	MsgTypeDisabled:     "⚠️ %s messages are not enabled here",
+	QueueFull:           "⚠️ Too many queued messages, please try again later.",
+	Queued:              "⏳ Queued (%d ahead)",
+	CommandTooFrequent:  "⚠️ Too many commands, please slow down",
+	ClearDone:           "✅ Session context cleared",
+	NewDone:             "✅ Started a new thread; this chat's settings are kept",
+	NewBusy:             "⚠️ A task is still running; start a new thread once it finishes (or use %s to interrupt it)",
+	BindDone:            "🔗 This chat is now bound to Codex thread %s; later messages continue it",
+	BindFailed:          "❌ Could not resume thread %s: %v",
+	BindInUse:           "⚠️ Thread %s is in use by another chat",
+	BindBusy:            "⚠️ A task is still running; bind once it finishes",
+	BindUsage:           "⚠️ Usage: %s <thread id>",
+	ResetDone:           "✅ Reset done",
+	ResetFailed:         "❌ Reset failed: %v",
+	SwitchDirDone:       "✅ Switched working directory to: %s",
+	SwitchDirFailed:     "❌ Failed to switch working directory: %v",
+	AdminOnly:           "⚠️ This command is for admins only",
+	ShowDir:             "Working directory: %s",
+
+	SwitchDirUsage:        "⚠️ Usage: %s <absolute path>",
+	WorkspacePickerTitle:  "Switch working directory",
+	WorkspacePicker:       "Current: %s\nClick a workspace to switch to it:",
+	WorkspaceSwitching:    "Switching to %s…",
+	WorkspaceUnknown:      "That workspace is no longer configured",
+	WorkspaceSwitchBusy:   "A workspace switch is already in progress",
+	WorkspaceNotRequester: "Only the member who asked can pick a workspace",
+
+	StatusIdle:        "Status: idle",
+	StatusPaused:      "Status: paused",
+	StatusProcessing:  "Status: processing",
+	StatusStep:        "Current step: %s",
+	StatusDefaultStep: "generating reply",
+	Pending:           "Pending: %d",
+	Elapsed:           "Running for %s, current step: %s",
+	ElapsedStarting:   "⏳ The task is starting; no running time yet",
+	ElapsedIdle:       "No task in progress",
+	GitBranch:         "Branch: %s",
+	GitBranchDirty:    "Branch: %s (uncommitted changes)",
+	GitBranchClean:    "Branch: %s (clean)",
+	GitNoCommits:      "(no commits)",
+	Progress:          "⏳ Working (%s) — %s",
+	StepReasoning:     "thinking",
+	StepCommand:       "running command",
+	StepFileChange:    "editing files",
+	StepToolCall:      "calling tool",
+	StepWebSearch:     "searching the web",
+	StepImageView:     "viewing image",
+
+	PauseAlready:    "This chat is already paused",
+	PauseFailed:     "❌ Failed to pause: %v",
+	PauseDone:       "⏸ Message processing paused for this chat, send %s to continue",
+	ResumeNotPaused: "This chat is not paused",
+	ResumeFailed:    "❌ Failed to resume: %v",
+	ResumeDone:      "▶️ Message processing resumed",
+	ResumedWithHeld: "▶️ Message processing resumed, handling %d messages held while paused",
+	PausedHold:      "⏸ This chat is paused; your message will be handled after %s",
+
+	VerboseOn:  "🔊 Verbose mode on: reasoning summaries and commands will be forwarded",
+	VerboseOff: "🔇 Verbose mode off",
+
+	EffortSet:     "✅ Reasoning effort set to %s, starting with the next turn",
+	EffortCurrent: "Reasoning effort: %s (change with %s low|medium|high)",
+	EffortDefault: "default",
+	EffortUsage:   "⚠️ Usage: %s <low|medium|high>",
+
+	StreamSet:     "✅ Reply mode set to %s, starting with the next turn",
+	StreamCurrent: "Reply mode: %s (change with %s final|stream|chunks: final replies once at the end, stream live-updates one reply, chunks sends each paragraph as it is written)",
+	StreamUsage:   "⚠️ Usage: %s <final|stream|chunks>",
+
+	LangSet:     "🌐 This chat now uses English",
+	LangCurrent: "Language: %s (switch with %s zh|en)",
+	LangUsage:   "⚠️ Usage: %s <zh|en>",
+
+	ModelSet:     "✅ This chat now uses model %s; context cleared, the next message starts a new thread",
+	ModelCurrent: "Model: %[1]s (switch with %[2]s <name>, %[2]s default to reset)",
+	ModelDefault: "codex default",
+	ModelUsage:   "⚠️ Usage: %s <name|default>",
+
+	ApprovalsCurrent:   "Approval policy: %s (admins can change it with %s auto|ask|readonly)",
+	ApprovalsSet:       "✅ Approval policy for this chat set to %s",
+	ApprovalsGlobalSet: "✅ Default approval policy set to %s (chats with their own policy are unaffected)",
+	ApprovalsUsage:     "⚠️ Usage: %s [global] <auto|ask|readonly>",
+	ApprovalCommand:    "🔐 Codex wants to run a command:\n%s",
+	ApprovalCwd:        "Directory: %s",
+	ApprovalFileChange: "🔐 Codex wants to change files:\n%s",
+	ApprovalFooter:     "React ✅ to approve or ❌ to decline (declined on timeout)",
+	ApprovalAccepted:   "✅ Approved",
+	ApprovalDeclined:   "❌ Declined",
+	ApprovalTimedOut:   "⌛ No answer in time, declined",
+
+	ErrorsHeader: "Recent errors (%d, newest first):",
+	ErrorsNone:   "No recent errors",
+
+	VersionInfo:    "Bridge version: %s\nCodex version: %s",
+	VersionUnknown: "unknown",
+
+	AskDisabled: "⚠️ Parallel questions are off (PARALLEL_ASK=true)",
+	AskUsage:    "Usage: %s <question>",
+	AskBusy:     "⏳ The previous %s is still running, please try again later",
+
+	CommitNotRepo:       "❌ The working directory is not a git repository",
+	CommitStatusFailed:  "❌ Failed to read git status: %v",
+	CommitNothing:       "Nothing to commit",
+	CommitConfirm:       "📝 Commit these %d changes?",
+	CommitConfirmFooter: "Commit message: %s\n\nReact ✅ to commit, ❌ to cancel (cancelled on timeout)",
+	CommitCancelled:     "Commit cancelled",
+	CommitTimeout:       "⌛ Not confirmed in time, commit cancelled",
+	CommitFailed:        "❌ Commit failed: %v",
+	CommitDone:          "✅ Committed: %s",
+	ListMore:            "…and %d more",
+	ChangesNone:         "No files changed in this session yet",
+	ChangesHeader:       "Files changed in this session (%d):",
+	ExportNone:          "No session to export",
+	ExportFailed:        "❌ Failed to read the session: %v",
+	ExportTruncated:     "(Transcript too long; only the first %d parts were exported)",
+	ExportTurnTitle:     "Turn %d",
+
+	Stats:           "Uptime: %s\nStarted: %d\nCompleted: %d\nFailed: %d\nInterrupted: %d\nQueued messages: %d\nRunning: %d",
+	StatsLimit:      " (limit %d, waiting %d)",
+	StatsNotStarted: "not started",
+	CleanupDone:     "🧹 Cleaned up %d expired sessions and freed %d idle chat states",
+	CleanupFailed:   "❌ Cleanup failed: %v",
+
+	Welcome: "👋 Hi! I pass messages on to Codex. Mention me with a task to get started.",
+
+	StartupSummary: "🚀 Started\nVersion: %s\nModel: %s\nWorking dir: %s\nSession DB: %s",
+	ShuttingDown:   "🛑 Shutting down",
+}
+
+// DefaultMessages returns the built-in zh-CN strings.
+func DefaultMessages() Messages {
+	return defaultMessages
+}
+
+// MessagesForLanguage returns the built-in strings for a normalized
+// language, falling back to the zh-CN set.
+func MessagesForLanguage(lang string) Messages {
+	if lang == LanguageEN {
+		return englishMessages
+	}
+	return defaultMessages
+}
+
+// LoadMessages reads a JSON file of message overrides on top of base.
+// Keys that are absent keep their base value; unknown keys are an error so
+// typos don't go unnoticed.
+func LoadMessages(path string, base Messages) (Messages, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return base, fmt.Errorf("failed to open messages file: %w", err)
+	}
+	defer f.Close()
+
+	msgs := base
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&msgs); err != nil {
+		return base, fmt.Errorf("failed to parse messages file %s: %w", path, err)
+	}
+	return msgs, nil
+}
+
+// messages returns the active strings; bridges built without New (e.g. in
+// tests) get the defaults.
+func (b *Bridge) messages() *Messages {
+	if b.msgs == nil {
+		return &defaultMessages
+	}
+	return b.msgs
+}
+
+// chatMessages returns the strings for chatID's language. MESSAGES_FILE
+// overrides only apply to Config.Language.
+func (b *Bridge) chatMessages(chatID string) *Messages {
+	lang := b.chatLanguage(chatID)
+	switch {
+	case lang == b.config.Language || (b.config.Language == "" && lang == LanguageZH):
+		return b.messages()
+	case lang == LanguageEN:
+		return &englishMessages
+	}
+	return &defaultMessages
+}
@@ -0,0 +1,94 @@
+package bridge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestLoadMessages_OverridesOnlyGivenKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.json")
+	if err := os.WriteFile(path, []byte(`{"no_text_response": "(no text)", "queued": "queued, %d ahead"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := LoadMessages(path, DefaultMessages())
+	if err != nil {
+		t.Fatalf("LoadMessages: %v", err)
+	}
+	if msgs.NoTextResponse != "(no text)" || msgs.Queued != "queued, %d ahead" {
+		t.Fatalf("overrides not applied: %+v", msgs)
+	}
+	if msgs.ClearDone != DefaultMessages().ClearDone {
+		t.Fatalf("expected unset key to keep default, got %q", msgs.ClearDone)
+	}
+}
+
+func TestLoadMessages_RejectsUnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.json")
+	if err := os.WriteFile(path, []byte(`{"no_text_reponse": "typo"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMessages(path, DefaultMessages()); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+}
+
+func TestTurnResponse_UsesCustomMessages(t *testing.T) {
+	msgs := DefaultMessages()
+	msgs.NoTextResponse = "(no text)"
+	msgs.TurnInterrupted = "interrupted"
+	b := &Bridge{msgs: &msgs}
+
+	got, _ := b.turnResponse("c1", "", codex.TurnCompletedParams{Status: "completed"})
+	if got != "(no text)" {
+		t.Fatalf("expected custom placeholder, got %q", got)
+	}
+	got, _ = b.turnResponse("c1", "partial", codex.TurnCompletedParams{Status: "interrupted"})
+	if got != "interrupted\n\npartial" {
+		t.Fatalf("expected custom interrupted notice, got %q", got)
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	for in, want := range map[string]string{"": LanguageZH, "zh-CN": LanguageZH, "EN": LanguageEN, "en_US": LanguageEN} {
+		got, err := normalizeLanguage(in)
+		if err != nil || got != want {
+			t.Fatalf("normalizeLanguage(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := normalizeLanguage("fr"); err == nil {
+		t.Fatal("expected error for unsupported language")
+	}
+}
+
+func TestEnglishMessages_UsedInReplies(t *testing.T) {
+	msgs := MessagesForLanguage(LanguageEN)
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{WorkingDir: "/tmp", Language: LanguageEN},
+		feishuClient: m,
+		msgs:         &msgs,
+		chatQueues:   make(map[string]*chatQueue),
+		chatStates:   make(map[string]*ChatState),
+	}
+
+	for _, content := range []string{"/pwd", "/status", "/help text"} {
+		b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: content})
+	}
+	if len(m.SentMessages) != 3 {
+		t.Fatalf("expected 3 replies, got %d", len(m.SentMessages))
+	}
+	if got := m.SentMessages[0].Text; got != "Working directory: /tmp" {
+		t.Fatalf("unexpected /pwd reply: %q", got)
+	}
+	if got := m.SentMessages[1].Text; got != "Status: idle\nPending: 0" {
+		t.Fatalf("unexpected /status reply: %q", got)
+	}
+	if got := m.SentMessages[2].Text; !strings.HasPrefix(got, "Available commands:\n/help or /h: ") {
+		t.Fatalf("unexpected /help text reply: %q", got)
+	}
+}
@@ -2,21 +2,51 @@ package bridge
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
 
 	"github.com/anthropics/feishu-codex-bridge/codex"
 	"github.com/anthropics/feishu-codex-bridge/feishu"
+	"github.com/anthropics/feishu-codex-bridge/session"
 )
 
 // MockFeishuClient is a mock implementation of FeishuClient for testing
 type MockFeishuClient struct {
-	OnMessageHandler  feishu.MessageHandler
-	OnRecalledHandler feishu.MessageRecalledHandler
-	DebugEnabled      bool
-	SentMessages      []MockSentMessage
-	Reactions         []MockReaction
-	DownloadedImages  []string
-	DownloadDir       string
-	StartError        error
+	OnMessageHandler     feishu.MessageHandler
+	OnRecalledHandler    feishu.MessageRecalledHandler
+	OnRejectedHandler    feishu.MessageRejectedHandler
+	OnReactionHandler    feishu.ReactionHandler
+	OnBotJoinedHandler   feishu.BotJoinedHandler
+	OnChatChangedHandler feishu.ChatChangedHandler
+	OnCardActionHandler  feishu.CardActionHandler
+	DebugEnabled         bool
+	AcceptedMsgTypes     []string
+	SentMessages         []MockSentMessage
+	Reactions            []MockReaction
+	DownloadedImages     []string
+	UpdatedMessages      []MockSentMessage
+	DeletedMessages      []string
+	DownloadDir          string
+	StartError           error
+	// FailEmojis makes AddReaction fail for the listed emoji types.
+	FailEmojis map[string]bool
+	// FailRichTitles makes ReplyRichText fail for posts with the listed
+	// titles.
+	FailRichTitles map[string]bool
+	// ReplyImageError is returned by ReplyImage when set.
+	ReplyImageError error
+	// ChatInfos and ChatMembers are returned by GetChatInfo and
+	// GetChatMembers.
+	ChatInfos   map[string]*feishu.ChatInfo
+	ChatMembers map[string][]*feishu.ChatMember
+	// ChatInfoCalls and ChatMembersCalls count GetChatInfo and
+	// GetChatMembers calls.
+	ChatInfoCalls    int
+	ChatMembersCalls int
 }
 
 type MockSentMessage struct {
@@ -27,6 +57,8 @@ type MockSentMessage struct {
 	Title   string
 	Content [][]map[string]interface{}
 	IsReply bool
+	Image   string
+	Card    map[string]interface{}
 }
 
 type MockReaction struct {
@@ -44,10 +76,34 @@ func (m *MockFeishuClient) OnMessageRecalled(handler feishu.MessageRecalledHandl
 	m.OnRecalledHandler = handler
 }
 
+func (m *MockFeishuClient) OnMessageRejected(handler feishu.MessageRejectedHandler) {
+	m.OnRejectedHandler = handler
+}
+
+func (m *MockFeishuClient) OnReaction(handler feishu.ReactionHandler) {
+	m.OnReactionHandler = handler
+}
+
+func (m *MockFeishuClient) OnBotJoined(handler feishu.BotJoinedHandler) {
+	m.OnBotJoinedHandler = handler
+}
+
+func (m *MockFeishuClient) OnChatChanged(handler feishu.ChatChangedHandler) {
+	m.OnChatChangedHandler = handler
+}
+
+func (m *MockFeishuClient) OnCardAction(handler feishu.CardActionHandler) {
+	m.OnCardActionHandler = handler
+}
+
 func (m *MockFeishuClient) SetDebug(enabled bool) {
 	m.DebugEnabled = enabled
 }
 
+func (m *MockFeishuClient) SetAcceptedMsgTypes(types []string) {
+	m.AcceptedMsgTypes = types
+}
+
 func (m *MockFeishuClient) Start() error {
 	return m.StartError
 }
@@ -55,11 +111,16 @@ func (m *MockFeishuClient) Start() error {
 func (m *MockFeishuClient) Stop() {}
 
 func (m *MockFeishuClient) SendText(chatID, text string) error {
+	_, err := m.SendTextWithID(chatID, text)
+	return err
+}
+
+func (m *MockFeishuClient) SendTextWithID(chatID, text string) (string, error) {
 	m.SentMessages = append(m.SentMessages, MockSentMessage{
 		ChatID: chatID,
 		Text:   text,
 	})
-	return nil
+	return fmt.Sprintf("mock-msg-%d", len(m.SentMessages)), nil
 }
 
 func (m *MockFeishuClient) SendRichText(chatID, title string, content [][]map[string]interface{}) error {
@@ -73,15 +134,23 @@ func (m *MockFeishuClient) SendRichText(chatID, title string, content [][]map[st
 }
 
 func (m *MockFeishuClient) ReplyText(messageID, text string, replyInThread bool) error {
+	_, err := m.ReplyTextWithID(messageID, text, replyInThread)
+	return err
+}
+
+func (m *MockFeishuClient) ReplyTextWithID(messageID, text string, replyInThread bool) (string, error) {
 	m.SentMessages = append(m.SentMessages, MockSentMessage{
 		MsgID:   messageID,
 		Text:    text,
 		IsReply: true,
 	})
-	return nil
+	return fmt.Sprintf("mock-msg-%d", len(m.SentMessages)), nil
 }
 
 func (m *MockFeishuClient) ReplyRichText(messageID, title string, content [][]map[string]interface{}, replyInThread bool) error {
+	if m.FailRichTitles[title] {
+		return errors.New("mock: post rejected")
+	}
 	m.SentMessages = append(m.SentMessages, MockSentMessage{
 		MsgID:   messageID,
 		IsRich:  true,
@@ -92,7 +161,52 @@ func (m *MockFeishuClient) ReplyRichText(messageID, title string, content [][]ma
 	return nil
 }
 
+func (m *MockFeishuClient) ReplyCard(messageID string, card map[string]interface{}, replyInThread bool) error {
+	m.SentMessages = append(m.SentMessages, MockSentMessage{
+		MsgID:   messageID,
+		Card:    card,
+		IsReply: true,
+	})
+	return nil
+}
+
+func (m *MockFeishuClient) SendImage(chatID, path string) error {
+	m.SentMessages = append(m.SentMessages, MockSentMessage{
+		ChatID: chatID,
+		Image:  path,
+	})
+	return nil
+}
+
+func (m *MockFeishuClient) ReplyImage(messageID, path string, replyInThread bool) error {
+	if m.ReplyImageError != nil {
+		return m.ReplyImageError
+	}
+	m.SentMessages = append(m.SentMessages, MockSentMessage{
+		MsgID:   messageID,
+		Image:   path,
+		IsReply: true,
+	})
+	return nil
+}
+
+func (m *MockFeishuClient) UpdateText(messageID, text string) error {
+	m.UpdatedMessages = append(m.UpdatedMessages, MockSentMessage{
+		MsgID: messageID,
+		Text:  text,
+	})
+	return nil
+}
+
+func (m *MockFeishuClient) DeleteMessage(messageID string) error {
+	m.DeletedMessages = append(m.DeletedMessages, messageID)
+	return nil
+}
+
 func (m *MockFeishuClient) AddReaction(messageID, emojiType string) (string, error) {
+	if m.FailEmojis[emojiType] {
+		return "", errors.New("mock: emoji not allowed: " + emojiType)
+	}
 	reactionID := "mock-reaction-" + emojiType + "-" + messageID
 	m.Reactions = append(m.Reactions, MockReaction{
 		MessageID:  messageID,
@@ -113,6 +227,13 @@ func (m *MockFeishuClient) RemoveReaction(messageID, reactionID string) error {
 
 func (m *MockFeishuClient) DownloadImage(messageID, imageKey string) (string, error) {
 	path := "/tmp/images/" + imageKey + ".png"
+	if m.DownloadDir != "" {
+		// Write a real file so tests can check cleanup.
+		path = filepath.Join(m.DownloadDir, imageKey+".png")
+		if err := os.WriteFile(path, []byte("png"), 0o600); err != nil {
+			return "", err
+		}
+	}
 	m.DownloadedImages = append(m.DownloadedImages, path)
 	return path, nil
 }
@@ -126,11 +247,13 @@ func (m *MockFeishuClient) GetChatHistory(chatID string, pageSize int) ([]*feish
 }
 
 func (m *MockFeishuClient) GetChatMembers(chatID string) ([]*feishu.ChatMember, error) {
-	return nil, nil
+	m.ChatMembersCalls++
+	return m.ChatMembers[chatID], nil
 }
 
 func (m *MockFeishuClient) GetChatInfo(chatID string) (*feishu.ChatInfo, error) {
-	return nil, nil
+	m.ChatInfoCalls++
+	return m.ChatInfos[chatID], nil
 }
 
 // MockCodexClient is a mock implementation of CodexClient for testing
@@ -145,12 +268,23 @@ type MockCodexClient struct {
 	StartedTurns     []MockTurn
 	NextThreadID     string
 	NextTurnID       string
+	// TurnStartErrors are returned by successive TurnStart calls before
+	// TurnStartError applies; a nil entry lets that call succeed.
+	TurnStartErrors []error
+	// ThreadResumeError is returned by ThreadResume, as codex does for an
+	// unknown thread.
+	ThreadResumeError error
+	// ThreadCwd is the working directory ThreadResume reports.
+	ThreadCwd string
+	// ResumedThreads records ThreadResume calls.
+	ResumedThreads []string
 }
 
 type MockTurn struct {
 	ThreadID string
 	Prompt   string
 	Images   []string
+	Options  *codex.TurnOptions
 }
 
 func NewMockCodexClient() *MockCodexClient {
@@ -194,17 +328,28 @@ func (m *MockCodexClient) ThreadStart(ctx context.Context, params *codex.ThreadS
 }
 
 func (m *MockCodexClient) ThreadResume(ctx context.Context, threadID string) (*codex.Thread, error) {
-	return &codex.Thread{ID: threadID}, nil
+	m.ResumedThreads = append(m.ResumedThreads, threadID)
+	if m.ThreadResumeError != nil {
+		return nil, m.ThreadResumeError
+	}
+	return &codex.Thread{ID: threadID, Cwd: m.ThreadCwd}, nil
 }
 
-func (m *MockCodexClient) TurnStart(ctx context.Context, threadID, prompt string, images []string) (string, error) {
-	if m.TurnStartError != nil {
+func (m *MockCodexClient) TurnStart(ctx context.Context, threadID, prompt string, images []string, opts *codex.TurnOptions) (string, error) {
+	if len(m.TurnStartErrors) > 0 {
+		err := m.TurnStartErrors[0]
+		m.TurnStartErrors = m.TurnStartErrors[1:]
+		if err != nil {
+			return "", err
+		}
+	} else if m.TurnStartError != nil {
 		return "", m.TurnStartError
 	}
 	m.StartedTurns = append(m.StartedTurns, MockTurn{
 		ThreadID: threadID,
 		Prompt:   prompt,
 		Images:   images,
+		Options:  opts,
 	})
 	return m.NextTurnID, nil
 }
@@ -217,7 +362,101 @@ func (m *MockCodexClient) RespondToApproval(requestID int64, decision string) er
 	return nil
 }
 
+func (m *MockCodexClient) SetApprovalHandler(handler codex.ApprovalHandler) {}
+
+func (m *MockCodexClient) SetDebug(enabled bool) {}
+
+func (m *MockCodexClient) SetInitTimeout(d time.Duration) {}
+
+func (m *MockCodexClient) SetDisableNetwork(disable bool) {}
+
+func (m *MockCodexClient) Exited() <-chan struct{} { return nil }
+
+func (m *MockCodexClient) Crashed() bool { return false }
+
+func (m *MockCodexClient) Unhealthy() <-chan struct{} { return nil }
+
+func (m *MockCodexClient) SetKeepAlive(interval time.Duration) {}
+
 // SendEvent sends an event through the mock client's event channel
 func (m *MockCodexClient) SendEvent(event codex.Event) {
 	m.EventsChan <- event
 }
+
+// newTestBridge returns a bridge for tests with an in-memory session store,
+// a MockFeishuClient and a codex client that was never started, in a temp
+// working directory. opts adjust it before it is returned.
+func newTestBridge(t *testing.T, opts ...func(*Bridge)) (*Bridge, *MockFeishuClient) {
+	t.Helper()
+	store, err := session.NewMemoryStore(60, -1)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	workDir := t.TempDir()
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:        Config{WorkingDir: workDir},
+		feishuClient:  m,
+		sessionStore:  store,
+		codexClient:   codex.NewClient(workDir, ""),
+		chatStates:    make(map[string]*ChatState),
+		chatQueues:    make(map[string]*chatQueue),
+		activeThreads: make(map[string]struct{}),
+		recalled:      make(map[string]map[string]time.Time),
+		recalledAll:   make(map[string]time.Time),
+		approvals:     make(map[string]*pendingApproval),
+		ctx:           context.Background(),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b, m
+}
+
+// withAdmin is a newTestBridge option making ou_admin an admin.
+func withAdmin(b *Bridge) {
+	b.config.AdminOpenIDs = []string{"ou_admin"}
+}
+
+// stubChatQueue pre-creates chatID's queue without a worker, so a test can
+// inspect what enqueueMessage puts on it.
+func stubChatQueue(b *Bridge, chatID string) *chatQueue {
+	q := &chatQueue{ch: make(chan *feishu.Message, 10)}
+	b.chatQueues[chatID] = q
+	return q
+}
+
+// restartOf is a newTestBridge option for b after a restart: same config and
+// session store, fresh in-memory state.
+func restartOf(b *Bridge) func(*Bridge) {
+	return func(r *Bridge) {
+		r.config = b.config
+		r.sessionStore = b.sessionStore
+	}
+}
+
+// fakeCodexServer is an app-server stand-in that answers every request
+// with a fixed thread or turn ID; it never sends notifications.
+const fakeCodexServer = `#!/bin/sh
+while IFS= read -r line; do
+	req=$(printf '%s\n' "$line" | sed -n 's/^{"id":\([0-9]*\),"method":"\([^"]*\)".*/\1 \2/p')
+	[ -n "$req" ] || continue
+	case ${req#* } in
+	thread/start) result='{"thread":{"id":"thread-1"}}' ;;
+	turn/start) result='{"turnId":"turn-1"}' ;;
+	*) result='{}' ;;
+	esac
+	printf '{"id":%s,"result":%s}\n' "${req%% *}" "$result"
+done
+`
+
+// useFakeCodex puts fakeCodexServer first on PATH as "codex" for the rest
+// of the test, so tests that start an app-server don't need a real codex.
+func useFakeCodex(t *testing.T) {
+	t.Helper()
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "codex"), []byte(fakeCodexServer), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
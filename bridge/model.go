@@ -0,0 +1,83 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModelArgDefault makes /model drop the chat's model and use
+// Config.CodexModel again.
+const ModelArgDefault = "default"
+
+// loadChatModels restores the models chats chose with /model before a
+// restart.
+func (b *Bridge) loadChatModels() {
+	models, err := b.sessionStore.ChatModels()
+	if err != nil {
+		fmt.Printf("[Bridge] Failed to load chat models: %v\n", err)
+		return
+	}
+	for chatID, model := range models {
+		state := b.getChatState(chatID)
+		state.mu.Lock()
+		state.Model = model
+		state.mu.Unlock()
+	}
+}
+
+// chatModel returns the model set with /model for chatID, or
+// Config.CodexModel.
+func (b *Bridge) chatModel(chatID string) string {
+	b.chatStatesMu.RLock()
+	state := b.chatStates[chatID]
+	b.chatStatesMu.RUnlock()
+	var model string
+	if state != nil {
+		state.mu.Lock()
+		model = state.Model
+		state.mu.Unlock()
+	}
+	if model == "" {
+		return b.config.CodexModel
+	}
+	return model
+}
+
+// setModel handles /model: an empty arg reports the chat's model, otherwise
+// it is switched (or reset with "default") and persisted. A thread keeps the
+// model it was started with, so switching clears the chat's context.
+func (b *Bridge) setModel(chatID, arg string) string {
+	msgs := b.chatMessages(chatID)
+	if arg == "" {
+		model := b.chatModel(chatID)
+		if model == "" {
+			model = msgs.ModelDefault
+		}
+		return fmt.Sprintf(msgs.ModelCurrent, model, b.withPrefix("/model"))
+	}
+	if strings.ContainsAny(arg, " \t\n\"") {
+		return fmt.Sprintf(msgs.ModelUsage, b.withPrefix("/model"))
+	}
+	model := arg
+	if strings.EqualFold(arg, ModelArgDefault) {
+		model = ""
+	}
+	if err := b.sessionStore.SetModel(chatID, model); err != nil {
+		fmt.Printf("[Bridge] Failed to persist model for %s: %v\n", chatID, err)
+	}
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	changed := state.Model != model
+	state.Model = model
+	state.mu.Unlock()
+	if changed {
+		b.clearChatContext(chatID)
+	}
+	if model == "" {
+		model = b.config.CodexModel
+		if model == "" {
+			model = msgs.ModelDefault
+		}
+	}
+	return fmt.Sprintf(msgs.ModelSet, model)
+}
@@ -0,0 +1,48 @@
+package bridge
+
+import "testing"
+
+func TestModelCommand_PersistsPerChat(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.CodexModel = "gpt-5.2-codex"
+	if _, err := b.sessionStore.Create("c1", "thread-1"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	if got := b.setModel("c1", "o3"); got != "✅ 本会话模型已设为 o3，已清空上下文，下一条消息将开启新会话" {
+		t.Fatalf("unexpected /model reply: %q", got)
+	}
+	if entry, _ := b.sessionStore.GetByChatID("c1"); entry != nil {
+		t.Fatalf("expected switching model to clear the session, got %+v", entry)
+	}
+	if p := b.threadStartParams("c1"); p == nil || p.Model != "o3" {
+		t.Fatalf("expected chat model in thread params, got %+v", p)
+	}
+	// Other chats fall back to Config.CodexModel.
+	if p := b.threadStartParams("c2"); p != nil {
+		t.Fatalf("expected nil params for a chat without a model, got %+v", p)
+	}
+	if got := b.setModel("c1", "bad model"); got != "⚠️ 用法：/model <模型名称|default>" {
+		t.Fatalf("unexpected reply to invalid model: %q", got)
+	}
+	b.config.CommandPrefix = "!"
+	if got := b.setModel("c1", ""); got != "当前模型：o3（可用 !model <名称> 切换，!model default 恢复默认）" {
+		t.Fatalf("expected the configured prefix in the current model reply, got %q", got)
+	}
+	b.config.CommandPrefix = ""
+
+	// The choice survives a restart.
+	b2, _ := newTestBridge(t, restartOf(b))
+	b2.loadChatModels()
+	if got := b2.chatModel("c1"); got != "o3" {
+		t.Fatalf("expected persisted model o3, got %q", got)
+	}
+
+	b.setModel("c1", "default")
+	if got := b.chatModel("c1"); got != "gpt-5.2-codex" {
+		t.Fatalf("expected fallback to Config.CodexModel, got %q", got)
+	}
+	if models, _ := b.sessionStore.ChatModels(); len(models) != 0 {
+		t.Fatalf("expected default to clear the persisted model, got %v", models)
+	}
+}
@@ -0,0 +1,40 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// truncatedNotice returns Messages.ResponseTruncated for chatID, naming /more
+// with the configured command prefix.
+func (b *Bridge) truncatedNotice(chatID string) string {
+	return fmt.Sprintf(b.chatMessages(chatID).ResponseTruncated, b.withPrefix("/more"))
+}
+
+// responseRemainder returns what truncateResponse cuts off,
+// without the whitespace at the cut, or "" when nothing is cut.
+func responseRemainder(response string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(response) <= max {
+		return ""
+	}
+	return strings.TrimLeft(string([]rune(response)[max:]), " \n")
+}
+
+// nextMoreOutput handles /more: it returns the next Config.MaxResponseChars
+// of the chat's truncated reply, with the truncation notice again if more
+// is left, or Messages.MoreNone when nothing is pending.
+func (b *Bridge) nextMoreOutput(chatID string) string {
+	msgs := b.chatMessages(chatID)
+	notice := b.truncatedNotice(chatID)
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.pendingOutput == "" {
+		return msgs.MoreNone
+	}
+	max := b.config.MaxResponseChars
+	chunk := truncateResponse(state.pendingOutput, max, notice)
+	state.pendingOutput = responseRemainder(state.pendingOutput, max)
+	return chunk
+}
@@ -0,0 +1,69 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestHandleTurnCompleted_KeepsTruncatedRemainder(t *testing.T) {
+	b, m := newTestBridge(t)
+	b.config.MaxResponseChars = 10
+	state := b.getChatState("c1")
+	state.ThreadID = "t1"
+	state.MsgID = "m1"
+	state.Buffer.WriteString(strings.Repeat("一", 10) + "\n" + strings.Repeat("二", 10) + strings.Repeat("三", 5))
+
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "completed"})
+
+	if got, want := findReplyText(m, "m1"), strings.Repeat("一", 10)+"\n\n"+b.truncatedNotice("c1"); got != want {
+		t.Fatalf("expected truncated reply %q, got %q", want, got)
+	}
+	if got, want := state.pendingOutput, strings.Repeat("二", 10)+strings.Repeat("三", 5); got != want {
+		t.Fatalf("expected remainder %q, got %q", want, got)
+	}
+}
+
+func TestMoreCommand(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{MaxResponseChars: 10},
+		feishuClient: m,
+		chatStates:   make(map[string]*ChatState),
+	}
+	b.getChatState("c1").pendingOutput = strings.Repeat("二", 10) + strings.Repeat("三", 5)
+	more := func(msgID string) string {
+		b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: msgID, MsgType: "text", Content: "/more"})
+		return findReplyText(m, msgID)
+	}
+
+	if got, want := more("m1"), strings.Repeat("二", 10)+"\n\n"+b.truncatedNotice("c1"); got != want {
+		t.Errorf("first /more: got %q, want %q", got, want)
+	}
+	if got, want := more("m2"), strings.Repeat("三", 5); got != want {
+		t.Errorf("second /more: got %q, want %q", got, want)
+	}
+	if got := more("m3"); got != "没有更多内容了" {
+		t.Errorf("expected nothing left, got %q", got)
+	}
+}
+
+func TestTruncatedNotice_UsesCommandPrefix(t *testing.T) {
+	b := &Bridge{config: Config{CommandPrefix: "!"}}
+	if got := b.truncatedNotice("c1"); got != "（回复过长，已截断，发送 !more 查看后续）" {
+		t.Errorf("unexpected notice %q", got)
+	}
+}
+
+func TestClearAndNew_DropPendingOutput(t *testing.T) {
+	b, _ := newTestBridge(t)
+	for _, reset := range []func(string){b.clearChatContext, func(chatID string) { b.startNewThread(chatID) }} {
+		b.getChatState("c1").pendingOutput = "rest of the last reply"
+		reset("c1")
+		if got := b.nextMoreOutput("c1"); got != defaultMessages.MoreNone {
+			t.Errorf("expected /more to have nothing left after a reset, got %q", got)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package bridge
+
+import "fmt"
+
+// startNewThread handles /new: the chat's next message starts a brand-new
+// codex thread. Unlike /clear it neither interrupts a turn nor drops queued
+// messages, and per-chat settings (model, language, verbosity, effort,
+// approvals, pause) are kept; only the session mapping and the thread/turn
+// state are reset. A chat with a turn in progress is refused.
+func (b *Bridge) startNewThread(chatID string) string {
+	msgs := b.chatMessages(chatID)
+	state := b.getChatState(chatID)
+
+	b.queuesMu.Lock()
+	q := b.chatQueues[chatID]
+	b.queuesMu.Unlock()
+
+	// Hold q.mu so the worker can't start a queued message halfway through;
+	// see chatWorker.
+	if q != nil {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+	}
+	state.mu.Lock()
+	if state.Processing {
+		state.mu.Unlock()
+		return fmt.Sprintf(msgs.NewBusy, b.withPrefix("/clear"))
+	}
+	state.ThreadID = ""
+	state.TurnID = ""
+	state.LastItem = ""
+	state.ChangedFiles = nil
+	state.Buffer.Reset()
+	state.FinalText = ""
+	state.FinalTurnID = ""
+	state.lastDeltaItem = ""
+	state.lastDelta = ""
+	state.pendingOutput = ""
+	state.mu.Unlock()
+
+	if err := b.sessionStore.Delete(chatID); err != nil {
+		fmt.Printf("[Bridge] Failed to delete session for %s: %v\n", chatID, err)
+	}
+	return msgs.NewDone
+}
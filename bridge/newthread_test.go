@@ -0,0 +1,66 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestNewCommand_KeepsPreferences(t *testing.T) {
+	b, m := newTestBridge(t)
+	b.config.WorkingDir = "/srv/repo"
+	store := b.sessionStore
+	if _, err := store.Create("c1", "thread-1"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	state := b.getChatState("c1")
+	state.ThreadID = "thread-1"
+	state.ChangedFiles = []string{"a.go"}
+	state.Model = "o3"
+	state.Verbose = true
+	state.verboseSet = true
+	state.Effort = "high"
+	state.Language = LanguageEN
+	state.ApprovalPolicy = "readonly"
+
+	b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", Content: "/new"})
+
+	if got := findReplyText(m, "m1"); got != englishMessages.NewDone {
+		t.Fatalf("unexpected /new reply %q", got)
+	}
+	if entry, _ := store.GetByChatID("c1"); entry != nil {
+		t.Fatalf("expected the session mapping to be deleted, got %+v", entry)
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.ThreadID != "" || state.ChangedFiles != nil {
+		t.Errorf("expected thread state reset, got thread=%q changed=%v", state.ThreadID, state.ChangedFiles)
+	}
+	if state.Model != "o3" || !state.Verbose || state.Effort != "high" || state.Language != LanguageEN || state.ApprovalPolicy != "readonly" {
+		t.Errorf("preferences lost: %+v", state)
+	}
+	if b.config.WorkingDir != "/srv/repo" {
+		t.Errorf("working dir changed to %q", b.config.WorkingDir)
+	}
+}
+
+func TestNewCommand_RefusedWhileProcessing(t *testing.T) {
+	b, _ := newTestBridge(t)
+	store := b.sessionStore
+	if _, err := store.Create("c1", "thread-1"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	state := b.getChatState("c1")
+	state.ThreadID = "thread-1"
+	state.Processing = true
+
+	if got := b.startNewThread("c1"); got != "⚠️ 当前任务还在处理中，请等它结束后再开启新会话（或用 /clear 中断）" {
+		t.Fatalf("unexpected reply %q", got)
+	}
+	if state.ThreadID != "thread-1" {
+		t.Errorf("expected the running thread to be kept")
+	}
+	if entry, _ := store.GetByChatID("c1"); entry == nil {
+		t.Errorf("expected the session to be kept")
+	}
+}
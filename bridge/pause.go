@@ -0,0 +1,147 @@
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// loadPausedChats restores the paused flag for chats paused before a restart.
+func (b *Bridge) loadPausedChats() {
+	chatIDs, err := b.sessionStore.PausedChats()
+	if err != nil {
+		fmt.Printf("[Bridge] Failed to load paused chats: %v\n", err)
+		return
+	}
+	for _, chatID := range chatIDs {
+		state := b.getChatState(chatID)
+		state.mu.Lock()
+		state.Paused = true
+		state.mu.Unlock()
+	}
+	if len(chatIDs) > 0 {
+		fmt.Printf("[Bridge] %d chat(s) paused\n", len(chatIDs))
+	}
+}
+
+// pauseChat stops dispatching messages for chatID. Messages already waiting
+// in the queue, and any that arrive while paused, are held until resumeChat.
+// A turn that is already running finishes normally.
+func (b *Bridge) pauseChat(chatID string) error {
+	if err := b.sessionStore.SetPaused(chatID, true); err != nil {
+		return err
+	}
+
+	b.queuesMu.Lock()
+	q := b.chatQueues[chatID]
+	b.queuesMu.Unlock()
+
+	if q != nil {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+	}
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	state.Paused = true
+	state.mu.Unlock()
+
+	if q == nil {
+		return nil
+	}
+	for {
+		select {
+		case msg, ok := <-q.ch:
+			if !ok {
+				return nil
+			}
+			if msg != nil {
+				q.held = append(q.held, msg)
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// resumeChat clears the paused flag and dispatches the held messages in
+// order. It returns how many were dispatched.
+func (b *Bridge) resumeChat(chatID string) (int, error) {
+	if err := b.sessionStore.SetPaused(chatID, false); err != nil {
+		return 0, err
+	}
+
+	b.queuesMu.Lock()
+	q := b.chatQueues[chatID]
+	b.queuesMu.Unlock()
+
+	var held []*feishu.Message
+	if q != nil {
+		q.mu.Lock()
+	}
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	state.Paused = false
+	state.mu.Unlock()
+	if q != nil {
+		held = q.held
+		q.held = nil
+		q.mu.Unlock()
+	}
+
+	dispatched := 0
+	for _, msg := range held {
+		if b.trySendQueue(q.ch, msg) {
+			dispatched++
+			continue
+		}
+		q.mu.Lock()
+		q.pending = removePendingByMsgID(q.pending, msg.MsgID)
+		q.mu.Unlock()
+		b.replyText(chatID, msg.MsgID, b.chatMessages(chatID).QueueFull, msg.ChatType == "group")
+	}
+	return dispatched, nil
+}
+
+// togglePause handles /pause and /resume and returns the reply text.
+func (b *Bridge) togglePause(chatID string, pause bool) string {
+	if pause {
+		if b.isPaused(chatID) {
+			return b.chatMessages(chatID).PauseAlready
+		}
+		if err := b.pauseChat(chatID); err != nil {
+			return fmt.Sprintf(b.chatMessages(chatID).PauseFailed, err)
+		}
+		return fmt.Sprintf(b.chatMessages(chatID).PauseDone, b.withPrefix("/resume"))
+	}
+	if !b.isPaused(chatID) {
+		return b.chatMessages(chatID).ResumeNotPaused
+	}
+	n, err := b.resumeChat(chatID)
+	if err != nil {
+		return fmt.Sprintf(b.chatMessages(chatID).ResumeFailed, err)
+	}
+	if n == 0 {
+		return b.chatMessages(chatID).ResumeDone
+	}
+	return fmt.Sprintf(b.chatMessages(chatID).ResumedWithHeld, n)
+}
+
+// holdIfPausedLocked parks msg on q.held when the chat is paused. Callers
+// must hold q.mu, which also serializes against pauseChat/resumeChat.
+func (b *Bridge) holdIfPausedLocked(q *chatQueue, msg *feishu.Message) bool {
+	state := b.getChatState(msg.ChatID)
+	state.mu.Lock()
+	paused := state.Paused
+	state.mu.Unlock()
+	if paused {
+		q.held = append(q.held, msg)
+	}
+	return paused
+}
+
+func (b *Bridge) isPaused(chatID string) bool {
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.Paused
+}
@@ -0,0 +1,87 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func sendCommand(b *Bridge, m *MockFeishuClient, sender, content string) string {
+	b.handleFeishuMessageV2(&feishu.Message{
+		ChatID:  "c1",
+		MsgID:   "cmd_" + content,
+		MsgType: "text",
+		Content: content,
+		Sender:  &feishu.Sender{SenderID: sender},
+	})
+	return m.SentMessages[len(m.SentMessages)-1].Text
+}
+
+func TestPauseCommand_HoldsMessagesUntilResume(t *testing.T) {
+	b, m := newTestBridge(t, withAdmin)
+	q := stubChatQueue(b, "c1")
+
+	if got := sendCommand(b, m, "ou_user", "/pause"); got != "⚠️ 该命令仅管理员可用" {
+		t.Fatalf("expected non-admin to be rejected, got %q", got)
+	}
+	if got := sendCommand(b, m, "ou_admin", "/pause"); got != "⏸ 已暂停本会话的消息处理，发送 /resume 恢复" {
+		t.Fatalf("unexpected pause reply: %q", got)
+	}
+
+	b.enqueueMessage(&feishu.Message{ChatID: "c1", MsgID: "m1", Content: "hello"})
+	b.enqueueMessage(&feishu.Message{ChatID: "c1", MsgID: "m2", Content: "world"})
+	if len(q.ch) != 0 || len(q.held) != 2 {
+		t.Fatalf("expected messages to be held, chan=%d held=%d", len(q.ch), len(q.held))
+	}
+	if got := m.SentMessages[len(m.SentMessages)-1].Text; got != "⏸ 本会话已暂停，消息会在发送 /resume 恢复后处理" {
+		t.Fatalf("expected paused notice, got %q", got)
+	}
+	if got := b.formatQueueStatus("c1"); got != "待处理：2" {
+		t.Fatalf("held messages should count as pending, got %q", got)
+	}
+
+	if got := sendCommand(b, m, "ou_admin", "/resume"); got != "▶️ 已恢复消息处理，继续处理暂停期间的 2 条消息" {
+		t.Fatalf("unexpected resume reply: %q", got)
+	}
+	if len(q.ch) != 2 || len(q.held) != 0 {
+		t.Fatalf("expected held messages to be dispatched, chan=%d held=%d", len(q.ch), len(q.held))
+	}
+	if first := <-q.ch; first.MsgID != "m1" {
+		t.Fatalf("expected held messages in order, got %s first", first.MsgID)
+	}
+	if paused, _ := b.sessionStore.PausedChats(); len(paused) != 0 {
+		t.Fatalf("expected paused state cleared, got %v", paused)
+	}
+}
+
+func TestPauseChat_MovesQueuedMessagesAside(t *testing.T) {
+	b, _ := newTestBridge(t)
+	q := stubChatQueue(b, "c1")
+
+	b.enqueueMessage(&feishu.Message{ChatID: "c1", MsgID: "m1"})
+	if err := b.pauseChat("c1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(q.ch) != 0 || len(q.held) != 1 || q.held[0].MsgID != "m1" {
+		t.Fatalf("expected queued message to be held, chan=%d held=%+v", len(q.ch), q.held)
+	}
+}
+
+func TestPausedState_SurvivesRestart(t *testing.T) {
+	b, _ := newTestBridge(t)
+	if err := b.pauseChat("c1"); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, _ := newTestBridge(t, restartOf(b))
+	restarted.loadPausedChats()
+	if !restarted.isPaused("c1") {
+		t.Fatal("expected c1 to stay paused after restart")
+	}
+	if restarted.chatStateIdle(restarted.getChatState("c1")) {
+		t.Fatal("paused chat state must not be evicted")
+	}
+	if got := restarted.formatStatus("c1"); got != "状态：已暂停\n待处理：0" {
+		t.Fatalf("unexpected status: %q", got)
+	}
+}
@@ -0,0 +1,158 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// stepLabel names the item types shown in /status and progress updates;
+// unknown types are shown as is.
+func stepLabel(msgs *Messages, itemType string) string {
+	switch itemType {
+	case "agentMessage":
+		return msgs.StatusDefaultStep
+	case "reasoning":
+		return msgs.StepReasoning
+	case "commandExecution":
+		return msgs.StepCommand
+	case "fileChange":
+		return msgs.StepFileChange
+	case "mcpToolCall":
+		return msgs.StepToolCall
+	case "webSearch":
+		return msgs.StepWebSearch
+	case "imageView":
+		return msgs.StepImageView
+	}
+	return itemType
+}
+
+// maxStepDetailChars caps the command/query shown after a step label.
+const maxStepDetailChars = 60
+
+// describeItem returns a short human-readable description of what item is
+// doing, e.g. "执行命令: npm test".
+func describeItem(item *codex.ThreadItem, msgs *Messages) string {
+	if item == nil {
+		return ""
+	}
+	label := stepLabel(msgs, item.Type)
+
+	var detail string
+	switch item.Type {
+	case "commandExecution":
+		detail = item.Command
+	case "webSearch":
+		detail = item.Query
+	case "mcpToolCall":
+		if item.Server != "" && item.Tool != "" {
+			detail = item.Server + "/" + item.Tool
+		} else {
+			detail = item.Tool
+		}
+	}
+	detail = strings.Join(strings.Fields(detail), " ")
+	if detail == "" {
+		return label
+	}
+	if runes := []rune(detail); len(runes) > maxStepDetailChars {
+		detail = string(runes[:maxStepDetailChars]) + "…"
+	}
+	return label + ": " + detail
+}
+
+func formatElapsed(d time.Duration) string {
+	secs := int(d / time.Second)
+	if secs < 60 {
+		return fmt.Sprintf("%ds", secs)
+	}
+	return fmt.Sprintf("%dm%02ds", secs/60, secs%60)
+}
+
+// progressText renders a progress update; step is ChatState.LastItem.
+func progressText(msgs *Messages, elapsed time.Duration, step string) string {
+	if step == "" {
+		step = msgs.StatusDefaultStep
+	}
+	return fmt.Sprintf(msgs.Progress, formatElapsed(elapsed), step)
+}
+
+// startProgressUpdates posts a status message after the first
+// Config.ProgressInterval of a turn and edits it on every tick with the
+// elapsed time and current step. The returned stop function ends the updates,
+// deletes the status message and waits for the goroutine to exit; it must be
+// called on every exit path. A zero interval disables updates.
+func (b *Bridge) startProgressUpdates(msg *feishu.Message, gen uint64) (stop func()) {
+	interval := b.config.ProgressInterval
+	if interval <= 0 {
+		return func() {}
+	}
+
+	state := b.getChatState(msg.ChatID)
+	replyInThread := msg.ChatType == "group"
+	started := time.Now() // until ChatState.TurnStartedAt is read
+	quit := make(chan struct{})
+	exited := make(chan struct{})
+
+	var ctxDone <-chan struct{}
+	if b.ctx != nil {
+		ctxDone = b.ctx.Done()
+	}
+
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var statusID string
+		defer func() {
+			if statusID != "" {
+				_ = b.feishuClient.DeleteMessage(statusID)
+			}
+		}()
+
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ctxDone:
+				return
+			case <-ticker.C:
+			}
+
+			state.mu.Lock()
+			active := state.Gen == gen && state.Processing
+			step := state.LastItem
+			if !state.TurnStartedAt.IsZero() {
+				started = state.TurnStartedAt
+			}
+			state.mu.Unlock()
+			if !active {
+				return
+			}
+
+			text := progressText(b.chatMessages(msg.ChatID), time.Since(started), step)
+			if statusID == "" {
+				id, err := b.replyTextWithID(msg.ChatID, msg.MsgID, text, replyInThread)
+				if err != nil {
+					b.debugf("Failed to post progress for %s: %v", msg.MsgID, err)
+					continue
+				}
+				statusID = id
+				continue
+			}
+			if err := b.feishuClient.UpdateText(statusID, text); err != nil {
+				b.debugf("Failed to update progress %s: %v", statusID, err)
+			}
+		}
+	}()
+
+	return func() {
+		close(quit)
+		<-exited
+	}
+}
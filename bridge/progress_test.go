@@ -0,0 +1,128 @@
+package bridge
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestProgressText(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		step    string
+		want    string
+	}{
+		{45 * time.Second, "执行命令: npm test", "⏳ 处理中 (45s) — 执行命令: npm test"},
+		{125 * time.Second, "", "⏳ 处理中 (2m05s) — 生成回复"},
+	}
+	for _, tt := range tests {
+		if got := progressText(&defaultMessages, tt.elapsed, tt.step); got != tt.want {
+			t.Errorf("progressText(%v, %q) = %q, want %q", tt.elapsed, tt.step, got, tt.want)
+		}
+	}
+	if got := progressText(&englishMessages, 45*time.Second, ""); got != "⏳ Working (45s) — generating reply" {
+		t.Errorf("unexpected English progress text %q", got)
+	}
+}
+
+func TestDescribeItem(t *testing.T) {
+	tests := []struct {
+		item codex.ThreadItem
+		want string
+	}{
+		{codex.ThreadItem{Type: "commandExecution", Command: "npm   test\n"}, "执行命令: npm test"},
+		{codex.ThreadItem{Type: "fileChange"}, "修改文件"},
+		{codex.ThreadItem{Type: "webSearch", Query: "go generics"}, "搜索网络: go generics"},
+		{codex.ThreadItem{Type: "mcpToolCall", Server: "docs", Tool: "lookup"}, "调用工具: docs/lookup"},
+		{codex.ThreadItem{Type: "somethingNew"}, "somethingNew"},
+		{codex.ThreadItem{Type: "commandExecution", Command: strings.Repeat("长", 70)}, "执行命令: " + strings.Repeat("长", 60) + "…"},
+	}
+	for _, tt := range tests {
+		if got := describeItem(&tt.item, &defaultMessages); got != tt.want {
+			t.Errorf("describeItem(%+v) = %q, want %q", tt.item, got, tt.want)
+		}
+	}
+	if got := describeItem(&codex.ThreadItem{Type: "commandExecution", Command: "npm test"}, &englishMessages); got != "running command: npm test" {
+		t.Errorf("unexpected English step %q", got)
+	}
+}
+
+func TestHandleEvent_TracksCurrentStep(t *testing.T) {
+	b, _ := newTestBridge(t)
+	state := b.getChatState("c1")
+	state.ThreadID = "t1"
+	state.Processing = true
+
+	event := func(method string, params any) codex.Event {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return codex.Event{Method: method, Params: raw}
+	}
+
+	b.handleEvent(event(codex.MethodItemStarted, codex.ItemStartedParams{
+		ThreadID: "t1",
+		Item:     &codex.ThreadItem{Type: "commandExecution", ID: "i1", Command: "npm test"},
+	}))
+	if !strings.Contains(b.formatStatus("c1"), "当前步骤：执行命令: npm test") {
+		t.Fatalf("expected current step in status, got %q", b.formatStatus("c1"))
+	}
+
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "completed"})
+	if state.LastItem != "" {
+		t.Fatalf("expected LastItem cleared on turn completion, got %q", state.LastItem)
+	}
+}
+
+func TestStartProgressUpdates_PostsUpdatesAndCleansUp(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{ProgressInterval: 10 * time.Millisecond},
+		feishuClient: m,
+		chatStates:   make(map[string]*ChatState),
+	}
+	state := b.getChatState("c1")
+	state.Processing = true
+	state.LastItem = "commandExecution"
+
+	stop := b.startProgressUpdates(&feishu.Message{ChatID: "c1", MsgID: "m1"}, 0)
+	time.Sleep(60 * time.Millisecond)
+	stop()
+
+	if len(m.SentMessages) != 1 || m.SentMessages[0].MsgID != "m1" {
+		t.Fatalf("expected one status reply, got %+v", m.SentMessages)
+	}
+	if len(m.UpdatedMessages) == 0 || m.UpdatedMessages[0].MsgID != "mock-msg-1" {
+		t.Fatalf("expected status message to be updated, got %+v", m.UpdatedMessages)
+	}
+	if len(m.DeletedMessages) != 1 || m.DeletedMessages[0] != "mock-msg-1" {
+		t.Fatalf("expected status message to be deleted on stop, got %v", m.DeletedMessages)
+	}
+}
+
+func TestStartProgressUpdates_StopsWhenTurnEnds(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{ProgressInterval: 10 * time.Millisecond},
+		feishuClient: m,
+		chatStates:   make(map[string]*ChatState),
+	}
+	// Not processing: the first tick ends the updates without posting.
+	stop := b.startProgressUpdates(&feishu.Message{ChatID: "c1", MsgID: "m1"}, 0)
+	time.Sleep(30 * time.Millisecond)
+	stop()
+
+	if len(m.SentMessages) != 0 || len(m.DeletedMessages) != 0 {
+		t.Fatalf("expected no status message, got sent=%+v deleted=%v", m.SentMessages, m.DeletedMessages)
+	}
+}
+
+func TestStartProgressUpdates_Disabled(t *testing.T) {
+	b := &Bridge{feishuClient: &MockFeishuClient{}}
+	b.startProgressUpdates(&feishu.Message{ChatID: "c1", MsgID: "m1"}, 0)()
+}
@@ -0,0 +1,56 @@
+package bridge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectPromptFile is the per-project standing instructions file, relative to
+// the working directory. Its contents are sent ahead of the first message of
+// every new thread.
+const projectPromptFile = ".feishu-codex-bridge/prompt.md"
+
+// projectPrompt returns the standing instructions for dir, reading the file on
+// first use and caching the result (including "no file").
+func (b *Bridge) projectPrompt(dir string) string {
+	b.projectPromptsMu.Lock()
+	defer b.projectPromptsMu.Unlock()
+
+	if prompt, ok := b.projectPrompts[dir]; ok {
+		return prompt
+	}
+
+	var prompt string
+	data, err := os.ReadFile(filepath.Join(dir, projectPromptFile))
+	if err == nil {
+		prompt = strings.TrimSpace(string(data))
+	} else if !os.IsNotExist(err) {
+		fmt.Printf("[Bridge] Failed to read project prompt in %s: %v\n", dir, err)
+	}
+
+	if b.projectPrompts == nil {
+		b.projectPrompts = make(map[string]string)
+	}
+	b.projectPrompts[dir] = prompt
+	return prompt
+}
+
+// forgetProjectPrompt drops the cached prompt for dir so it is re-read on the
+// next new thread.
+func (b *Bridge) forgetProjectPrompt(dir string) {
+	b.projectPromptsMu.Lock()
+	delete(b.projectPrompts, dir)
+	b.projectPromptsMu.Unlock()
+}
+
+// withProjectPrompt prepends the working directory's standing instructions to
+// the first message of a new thread.
+func (b *Bridge) withProjectPrompt(content string) string {
+	prompt := b.projectPrompt(b.config.WorkingDir)
+	if prompt == "" {
+		return content
+	}
+	return prompt + "\n\n---\n\n" + content
+}
@@ -0,0 +1,40 @@
+package bridge
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeProjectPrompt(t *testing.T, dir, text string) {
+	t.Helper()
+	path := filepath.Join(dir, projectPromptFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithProjectPrompt(t *testing.T) {
+	dir := t.TempDir()
+	b := &Bridge{config: Config{WorkingDir: dir}}
+
+	if got := b.withProjectPrompt("hi"); got != "hi" {
+		t.Fatalf("expected message unchanged without prompt.md, got %q", got)
+	}
+
+	writeProjectPrompt(t, dir, "  This is a Rust project.\n")
+
+	// The missing file was cached; a new thread in the same dir keeps it.
+	if got := b.withProjectPrompt("hi"); got != "hi" {
+		t.Fatalf("expected cached result, got %q", got)
+	}
+
+	b.forgetProjectPrompt(dir)
+	want := "This is a Rust project.\n\n---\n\nhi"
+	if got := b.withProjectPrompt("hi"); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
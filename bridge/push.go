@@ -0,0 +1,36 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PushMessage sends an unsolicited text message to a chat, outside of the
+// request/response flow. It does not touch per-chat state or the queue.
+func (b *Bridge) PushMessage(chatID, text string) error {
+	if strings.TrimSpace(chatID) == "" {
+		return fmt.Errorf("chat id is required")
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("text is required")
+	}
+	return b.feishuClient.SendText(chatID, text)
+}
+
+// PushMarkdown sends an unsolicited markdown message to a chat as a post with
+// a single "md" element, or as plain text when Config.PlainTextOnly is set.
+func (b *Bridge) PushMarkdown(chatID, markdown string) error {
+	if strings.TrimSpace(chatID) == "" {
+		return fmt.Errorf("chat id is required")
+	}
+	if strings.TrimSpace(markdown) == "" {
+		return fmt.Errorf("markdown is required")
+	}
+	if b.config.PlainTextOnly {
+		return b.feishuClient.SendText(chatID, markdown)
+	}
+	content := [][]map[string]interface{}{
+		{{"tag": "md", "text": markdown}},
+	}
+	return b.feishuClient.SendRichText(chatID, "", content)
+}
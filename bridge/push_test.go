@@ -0,0 +1,64 @@
+package bridge
+
+import "testing"
+
+func TestPushMessage_SendsTextWithoutTouchingState(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		feishuClient: m,
+		chatQueues:   make(map[string]*chatQueue),
+		chatStates:   make(map[string]*ChatState),
+	}
+
+	if err := b.PushMessage("c1", "build failed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.SentMessages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(m.SentMessages))
+	}
+	sent := m.SentMessages[0]
+	if sent.ChatID != "c1" || sent.Text != "build failed" || sent.IsReply {
+		t.Fatalf("unexpected message: %+v", sent)
+	}
+	if len(b.chatStates) != 0 || len(b.chatQueues) != 0 {
+		t.Fatalf("push should not create chat state or queue")
+	}
+}
+
+func TestPushMarkdown_SendsMdPost(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{feishuClient: m}
+
+	if err := b.PushMarkdown("c1", "**done**"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.SentMessages) != 1 || !m.SentMessages[0].IsRich {
+		t.Fatalf("expected one rich message, got %+v", m.SentMessages)
+	}
+	elem := m.SentMessages[0].Content[0][0]
+	if elem["tag"] != "md" || elem["text"] != "**done**" {
+		t.Fatalf("unexpected content: %+v", elem)
+	}
+}
+
+func TestPushMarkdown_PlainTextOnly(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{config: Config{PlainTextOnly: true}, feishuClient: m}
+
+	if err := b.PushMarkdown("c1", "**done**"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.SentMessages) != 1 || m.SentMessages[0].IsRich || m.SentMessages[0].Text != "**done**" {
+		t.Fatalf("expected one plain text message, got %+v", m.SentMessages)
+	}
+}
+
+func TestPushMessage_RequiresChatAndText(t *testing.T) {
+	b := &Bridge{feishuClient: &MockFeishuClient{}}
+	if err := b.PushMessage("", "x"); err == nil {
+		t.Fatalf("expected error for empty chat id")
+	}
+	if err := b.PushMessage("c1", "  "); err == nil {
+		t.Fatalf("expected error for empty text")
+	}
+}
@@ -34,3 +34,66 @@ func TestFormatQueueStatus_OnlyShowsPendingCount(t *testing.T) {
 		t.Fatalf("did not expect item list, got %q", out)
 	}
 }
+
+func TestEnqueueMessage_AcksWhenBusy(t *testing.T) {
+	b, m := newTestBridge(t)
+	b.config.AckQueued = true
+	stubChatQueue(b, "c1")
+
+	// Idle chat: no acknowledgement.
+	b.enqueueMessage(&feishu.Message{ChatID: "c1", MsgID: "m1", Content: "a"})
+	if len(m.SentMessages) != 0 {
+		t.Fatalf("expected no ack while idle, got %+v", m.SentMessages)
+	}
+
+	b.getChatState("c1").Processing = true
+	b.enqueueMessage(&feishu.Message{ChatID: "c1", MsgID: "m2", Content: "b"})
+	if len(m.SentMessages) != 1 {
+		t.Fatalf("expected one ack while busy, got %d", len(m.SentMessages))
+	}
+	ack := m.SentMessages[0]
+	if !ack.IsReply || ack.MsgID != "m2" || !strings.Contains(ack.Text, "已加入队列") {
+		t.Fatalf("unexpected ack: %+v", ack)
+	}
+}
+
+func TestClear_DropsOnlyMessagesQueuedBeforeIt(t *testing.T) {
+	b, _ := newTestBridge(t)
+	q := stubChatQueue(b, "c1")
+
+	b.enqueueMessage(&feishu.Message{ChatID: "c1", MsgID: "before", Content: "a"})
+	b.clearChatContext("c1")
+	b.enqueueMessage(&feishu.Message{ChatID: "c1", MsgID: "after", Content: "b"})
+
+	// Both are still in the channel; the worker must skip the first.
+	var run []string
+	for len(q.ch) > 0 {
+		msg := <-q.ch
+		if _, ok := b.takeQueued("c1", q, msg); ok {
+			run = append(run, msg.MsgID)
+		}
+	}
+	if len(run) != 1 || run[0] != "after" {
+		t.Fatalf("expected only the message sent after /clear to run, got %v", run)
+	}
+}
+
+func TestClear_DropsMessageDequeuedBeforeIt(t *testing.T) {
+	b, _ := newTestBridge(t)
+	q := stubChatQueue(b, "c1")
+
+	msg := &feishu.Message{ChatID: "c1", MsgID: "m1", Content: "a"}
+	b.enqueueMessage(msg)
+	gen, ok := b.takeQueued("c1", q, <-q.ch)
+	if !ok {
+		t.Fatal("expected message to be taken")
+	}
+
+	// /clear lands between the dequeue and the turn starting.
+	b.clearChatContext("c1")
+	b.processQueuedMessage("c1", msg, gen)
+
+	if st := b.getChatState("c1"); st.Processing || st.MsgID != "" {
+		t.Fatalf("expected cleared message not to start, state=%+v", st)
+	}
+}
@@ -0,0 +1,21 @@
+package bridge
+
+import "strings"
+
+// maxQuotedPromptChars caps the prompt excerpt quoted above a reply.
+const maxQuotedPromptChars = 60
+
+// quotePrompt prefixes response with a one-line excerpt of the prompt it
+// answers, when Config.QuotePromptInReply is set. A threaded reply already
+// points at the prompt; the quote matters most when the reply falls back to
+// a plain send.
+func (b *Bridge) quotePrompt(prompt, response string) string {
+	if !b.config.QuotePromptInReply {
+		return response
+	}
+	excerpt := strings.Join(strings.Fields(stripSenderContext(prompt)), " ")
+	if excerpt == "" {
+		return response
+	}
+	return "> " + truncateRunes(excerpt, maxQuotedPromptChars) + "\n\n" + response
+}
@@ -0,0 +1,57 @@
+package bridge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// failingReplyClient makes ReplyText fail so replies fall back to SendText.
+type failingReplyClient struct {
+	*MockFeishuClient
+}
+
+func (f failingReplyClient) ReplyText(messageID, text string, replyInThread bool) error {
+	return errors.New("reply failed")
+}
+
+func TestHandleTurnCompleted_QuotesPrompt(t *testing.T) {
+	const want = "> 帮我看看 build 为什么挂了\n\nanswer"
+	for _, fallback := range []bool{false, true} {
+		b, m := newTestBridge(t)
+		b.config.QuotePromptInReply = true
+		if fallback {
+			b.feishuClient = failingReplyClient{m}
+		}
+		state := b.getChatState("c1")
+		state.ThreadID = "t1"
+		state.MsgID = "m1"
+		state.Prompt = "帮我看看 build\n为什么挂了"
+		state.Buffer.WriteString("answer")
+
+		b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "completed"})
+
+		if len(m.SentMessages) != 1 {
+			t.Fatalf("fallback=%v: expected one message, got %+v", fallback, m.SentMessages)
+		}
+		sent := m.SentMessages[0]
+		if sent.IsReply == fallback || sent.Text != want {
+			t.Errorf("fallback=%v: got reply=%v text %q, want %q", fallback, sent.IsReply, sent.Text, want)
+		}
+	}
+}
+
+func TestQuotePrompt(t *testing.T) {
+	b := &Bridge{}
+	if got := b.quotePrompt("hi", "answer"); got != "answer" {
+		t.Errorf("expected no quote when disabled, got %q", got)
+	}
+	b.config.QuotePromptInReply = true
+	if got := b.quotePrompt("[来自 群 'dev' 的 张三]\nhi", "answer"); got != "> hi\n\nanswer" {
+		t.Errorf("expected the sender line to be left out, got %q", got)
+	}
+	if got := b.quotePrompt("", "answer"); got != "answer" {
+		t.Errorf("expected no quote for an empty prompt, got %q", got)
+	}
+}
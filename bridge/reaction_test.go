@@ -0,0 +1,35 @@
+package bridge
+
+import "testing"
+
+func TestAddProcessingReaction_FallsBackWhenPrimaryFails(t *testing.T) {
+	m := &MockFeishuClient{FailEmojis: map[string]bool{"Typing": true}}
+	b := &Bridge{feishuClient: m}
+
+	reactionID := b.addProcessingReaction("om1")
+	if reactionID != "mock-reaction-OnIt-om1" {
+		t.Fatalf("expected OnIt fallback reaction id, got %q", reactionID)
+	}
+}
+
+func TestAddProcessingReaction_UsesConfiguredEmoji(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{config: Config{ProcessingEmoji: "FINGERHEART"}, feishuClient: m}
+
+	reactionID := b.addProcessingReaction("om1")
+	if reactionID != "mock-reaction-FINGERHEART-om1" {
+		t.Fatalf("unexpected reaction id: %q", reactionID)
+	}
+	if len(m.Reactions) != 1 {
+		t.Fatalf("expected a single AddReaction call, got %d", len(m.Reactions))
+	}
+}
+
+func TestAddProcessingReaction_AllFail(t *testing.T) {
+	m := &MockFeishuClient{FailEmojis: map[string]bool{"Typing": true, "OnIt": true, "FINGERHEART": true}}
+	b := &Bridge{feishuClient: m}
+
+	if reactionID := b.addProcessingReaction("om1"); reactionID != "" {
+		t.Fatalf("expected empty reaction id, got %q", reactionID)
+	}
+}
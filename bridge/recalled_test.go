@@ -1,11 +1,16 @@
 package bridge
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
 
 func TestRecalled_MarkAndClear(t *testing.T) {
 	b := &Bridge{
-		recalled:    make(map[string]map[string]struct{}),
-		recalledAll: make(map[string]struct{}),
+		recalled:    make(map[string]map[string]time.Time),
+		recalledAll: make(map[string]time.Time),
 	}
 
 	if b.isRecalled("c1", "m1") {
@@ -25,8 +30,8 @@ func TestRecalled_MarkAndClear(t *testing.T) {
 
 func TestRecalled_GlobalMark(t *testing.T) {
 	b := &Bridge{
-		recalled:    make(map[string]map[string]struct{}),
-		recalledAll: make(map[string]struct{}),
+		recalled:    make(map[string]map[string]time.Time),
+		recalledAll: make(map[string]time.Time),
 	}
 
 	b.markRecalled("", "m1")
@@ -38,3 +43,54 @@ func TestRecalled_GlobalMark(t *testing.T) {
 		t.Fatalf("expected global recall to be cleared")
 	}
 }
+
+func TestRecalled_EvictsExpiredEntries(t *testing.T) {
+	b := &Bridge{
+		config:      Config{RecalledTTL: time.Minute},
+		recalled:    make(map[string]map[string]time.Time),
+		recalledAll: make(map[string]time.Time),
+	}
+
+	old := time.Now().Add(-2 * time.Minute)
+	b.recalledAll["m_old"] = old
+	b.recalled["c1"] = map[string]time.Time{"m_old": old}
+
+	if b.isRecalled("c1", "m_old") {
+		t.Fatalf("expected expired recall to be ignored")
+	}
+
+	b.markRecalled("c2", "m_new")
+	if _, ok := b.recalledAll["m_old"]; ok {
+		t.Fatalf("expected expired global entry to be evicted")
+	}
+	if _, ok := b.recalled["c1"]; ok {
+		t.Fatalf("expected empty chat entry to be evicted")
+	}
+	if !b.isRecalled("c2", "m_new") {
+		t.Fatalf("expected fresh recall to be kept")
+	}
+}
+
+func TestHandleTurnCompleted_SkipsRecalledMessage(t *testing.T) {
+	b, m := newTestBridge(t)
+	done := make(chan struct{})
+	state := b.getChatState("c1")
+	state.ThreadID = "t1"
+	state.MsgID = "m1"
+	state.Processing = true
+	state.done = done
+	state.Buffer.WriteString("answer")
+
+	// Recalled after the turn started, before it completed.
+	b.markRecalled("c1", "m1")
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "completed"})
+
+	if len(m.SentMessages) != 0 || len(m.Reactions) != 0 {
+		t.Fatalf("expected no reply to a recalled message, got sent=%+v reactions=%+v", m.SentMessages, m.Reactions)
+	}
+	select {
+	case <-done:
+	default:
+		t.Fatal("expected done to be closed so the worker moves on")
+	}
+}
@@ -0,0 +1,211 @@
+package bridge
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// CommandSpec describes one command. ParseCommand and /help both work from
+// commandSpecs, so every command in /help parses and vice versa.
+type CommandSpec struct {
+	Kind string
+	// Aliases are the names the command is invoked by; the first is the
+	// main one.
+	Aliases []string
+	// TakesArg allows "<alias> <arg>".
+	TakesArg bool
+	// ArgHint and Description are per-language /help text.
+	ArgHint     map[string]string
+	Description map[string]string
+	// parseArg normalizes the trimmed argument; false rejects the input.
+	// nil keeps the argument as is.
+	parseArg func(arg string) (string, bool)
+}
+
+// commandSpecs lists the commands, in /help order.
+var commandSpecs = []CommandSpec{
+	{
+		Kind:        CommandHelp,
+		TakesArg:    true,
+		parseArg:    parseHelpArg,
+		Aliases:     []string{"/help", "/h"},
+		Description: map[string]string{LanguageZH: "查看帮助（/help text 输出纯文本）", LanguageEN: "show this help (/help text for plain text)"},
+	},
+	{
+		Kind:        CommandShowDir,
+		Aliases:     []string{"/pwd"},
+		Description: map[string]string{LanguageZH: "查看当前工作目录", LanguageEN: "show the working directory"},
+	},
+	{
+		Kind:        CommandSwitchDir,
+		parseArg:    func(arg string) (string, bool) { return filepath.Clean(arg), true },
+		Aliases:     []string{"/cd"},
+		TakesArg:    true,
+		ArgHint:     map[string]string{LanguageZH: "<绝对路径>", LanguageEN: "<absolute path>"},
+		Description: map[string]string{LanguageZH: "切换工作目录（不带参数时从 WORKSPACES 中选择）", LanguageEN: "switch the working directory (without an argument, pick from WORKSPACES)"},
+	},
+	{
+		Kind:        CommandStatus,
+		Aliases:     []string{"/status", "/s"},
+		Description: map[string]string{LanguageZH: "查看当前状态", LanguageEN: "show the current status"},
+	},
+	{
+		Kind:        CommandElapsed,
+		Aliases:     []string{"/elapsed"},
+		Description: map[string]string{LanguageZH: "查看当前任务已运行多久", LanguageEN: "show how long the current task has been running"},
+	},
+	{
+		Kind:        CommandQueue,
+		Aliases:     []string{"/queue", "/q"},
+		Description: map[string]string{LanguageZH: "查看队列", LanguageEN: "show the queue"},
+	},
+	{
+		Kind:        CommandStats,
+		Aliases:     []string{"/stats"},
+		Description: map[string]string{LanguageZH: "查看运行统计", LanguageEN: "show runtime statistics"},
+	},
+	{
+		Kind:        CommandVersion,
+		Aliases:     []string{"/version"},
+		Description: map[string]string{LanguageZH: "查看 bridge 和 Codex 的版本", LanguageEN: "show the bridge and Codex versions"},
+	},
+	{
+		Kind:        CommandMore,
+		Aliases:     []string{"/more"},
+		Description: map[string]string{LanguageZH: "查看上一条被截断回复的后续内容", LanguageEN: "show more of the last truncated reply"},
+	},
+	{
+		Kind:        CommandChanges,
+		Aliases:     []string{"/changes"},
+		Description: map[string]string{LanguageZH: "查看本会话修改过的文件", LanguageEN: "list files changed in this session"},
+	},
+	{
+		Kind:        CommandCommit,
+		TakesArg:    true,
+		Aliases:     []string{"/commit"},
+		ArgHint:     map[string]string{LanguageZH: "[说明]", LanguageEN: "[message]"},
+		Description: map[string]string{LanguageZH: "确认后提交工作目录的改动（git）", LanguageEN: "commit working directory changes after confirmation (git)"},
+	},
+	{
+		Kind:        CommandAsk,
+		TakesArg:    true,
+		Aliases:     []string{"/ask"},
+		ArgHint:     map[string]string{LanguageZH: "<问题>", LanguageEN: "<question>"},
+		Description: map[string]string{LanguageZH: "在临时会话中并行提问，不影响当前上下文", LanguageEN: "ask in a throwaway session, in parallel, without touching the current context"},
+	},
+	{
+		Kind:        CommandExport,
+		Aliases:     []string{"/export"},
+		Description: map[string]string{LanguageZH: "导出当前会话记录", LanguageEN: "export this session's transcript"},
+	},
+	{
+		Kind:        CommandVerbose,
+		TakesArg:    true,
+		parseArg:    parseVerboseArg,
+		Aliases:     []string{"/verbose"},
+		ArgHint:     map[string]string{LanguageZH: "[on|off]", LanguageEN: "[on|off]"},
+		Description: map[string]string{LanguageZH: "开关详细模式（转发思考摘要和执行的命令）", LanguageEN: "toggle verbose mode (forward reasoning summaries and commands)"},
+	},
+	{
+		Kind:        CommandEffort,
+		TakesArg:    true,
+		parseArg:    lowerArg,
+		Aliases:     []string{"/effort"},
+		ArgHint:     map[string]string{LanguageZH: "[low|medium|high]", LanguageEN: "[low|medium|high]"},
+		Description: map[string]string{LanguageZH: "查看或设置本会话的推理强度", LanguageEN: "show or set this chat's reasoning effort"},
+	},
+	{
+		Kind:        CommandStream,
+		TakesArg:    true,
+		parseArg:    lowerArg,
+		Aliases:     []string{"/stream"},
+		ArgHint:     map[string]string{LanguageZH: "[final|stream|chunks]", LanguageEN: "[final|stream|chunks]"},
+		Description: map[string]string{LanguageZH: "查看或设置回复的输出方式（完成后一次性/实时更新/分段发送）", LanguageEN: "show or set how replies are delivered (at the end / live-updated / per paragraph)"},
+	},
+	{
+		Kind:        CommandLang,
+		TakesArg:    true,
+		parseArg:    lowerArg,
+		Aliases:     []string{"/lang"},
+		ArgHint:     map[string]string{LanguageZH: "[zh|en]", LanguageEN: "[zh|en]"},
+		Description: map[string]string{LanguageZH: "查看或切换本会话的语言", LanguageEN: "show or switch this chat's language"},
+	},
+	{
+		Kind:        CommandModel,
+		TakesArg:    true,
+		Aliases:     []string{"/model"},
+		ArgHint:     map[string]string{LanguageZH: "[名称|default]", LanguageEN: "[name|default]"},
+		Description: map[string]string{LanguageZH: "查看或切换本会话使用的模型（切换会清空上下文）", LanguageEN: "show or switch this chat's model (clears context)"},
+	},
+	{
+		Kind:        CommandNew,
+		Aliases:     []string{"/new"},
+		Description: map[string]string{LanguageZH: "开启新会话（保留模型等本会话设置）", LanguageEN: "start a new thread (keeps this chat's settings)"},
+	},
+	{
+		Kind:        CommandClear,
+		Aliases:     []string{"/clear", "/c"},
+		Description: map[string]string{LanguageZH: "清空当前会话上下文", LanguageEN: "clear this session's context"},
+	},
+	{
+		Kind:        CommandReset,
+		Aliases:     []string{"/reset", "/r"},
+		Description: map[string]string{LanguageZH: "重启 Codex", LanguageEN: "restart Codex"},
+	},
+	{
+		Kind:        CommandCleanup,
+		Aliases:     []string{"/cleanup"},
+		Description: map[string]string{LanguageZH: "立即清理过期会话（仅管理员）", LanguageEN: "clean up expired sessions now (admins only)"},
+	},
+	{
+		Kind:        CommandErrors,
+		Aliases:     []string{"/errors"},
+		Description: map[string]string{LanguageZH: "查看最近的错误（仅管理员）", LanguageEN: "show recent errors (admins only)"},
+	},
+	{
+		Kind:        CommandBind,
+		TakesArg:    true,
+		Aliases:     []string{"/bind"},
+		ArgHint:     map[string]string{LanguageZH: "<线程 ID>", LanguageEN: "<thread id>"},
+		Description: map[string]string{LanguageZH: "将本会话绑定到已有的 Codex 线程（仅管理员）", LanguageEN: "bind this chat to an existing Codex thread (admins only)"},
+	},
+	{
+		Kind:        CommandApprovals,
+		TakesArg:    true,
+		parseArg:    lowerArg,
+		Aliases:     []string{"/approvals"},
+		ArgHint:     map[string]string{LanguageZH: "[global] [auto|ask|readonly]", LanguageEN: "[global] [auto|ask|readonly]"},
+		Description: map[string]string{LanguageZH: "查看或修改审批策略（修改仅管理员）", LanguageEN: "show or change the approval policy (changes are admins only)"},
+	},
+	{
+		Kind:        CommandPause,
+		Aliases:     []string{"/pause"},
+		Description: map[string]string{LanguageZH: "暂停本会话的消息处理（仅管理员）", LanguageEN: "pause message processing in this chat (admins only)"},
+	},
+	{
+		Kind:        CommandResume,
+		Aliases:     []string{"/resume"},
+		Description: map[string]string{LanguageZH: "恢复本会话的消息处理（仅管理员）", LanguageEN: "resume message processing in this chat (admins only)"},
+	},
+}
+
+func lowerArg(arg string) (string, bool) {
+	return strings.ToLower(arg), true
+}
+
+// parseHelpArg accepts "text" (or "raw") for the plain-text help.
+func parseHelpArg(arg string) (string, bool) {
+	switch arg {
+	case "text", "raw":
+		return HelpArgText, true
+	}
+	return "", false
+}
+
+func parseVerboseArg(arg string) (string, bool) {
+	switch arg = strings.ToLower(arg); arg {
+	case "on", "off":
+		return arg, true
+	}
+	return "", false
+}
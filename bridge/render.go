@@ -0,0 +1,101 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+const (
+	// renderOutputTailLines is how many trailing lines of a command's output
+	// are shown in a rendered post.
+	renderOutputTailLines = 20
+	// maxRenderedOutputChars caps a command's rendered output (in runes).
+	maxRenderedOutputChars = 2000
+)
+
+// renderItemsPost renders thread items as post content, one section per
+// item: messages as text, commands with their output as code blocks, and
+// file changes and tool calls as short lists. Reasoning and other internal
+// items are left out. It is pure so consolidated replies and transcripts can
+// share it.
+func renderItemsPost(items []codex.ThreadItem) [][]map[string]interface{} {
+	post := feishu.NewPostBuilder()
+	sections := 0
+	section := func() {
+		if sections > 0 {
+			post.NewLine()
+		}
+		sections++
+	}
+	for _, item := range items {
+		switch item.Type {
+		case "userMessage":
+			text := stripSenderContext(item.UserText())
+			if text == "" {
+				continue
+			}
+			section()
+			post.BoldRun("用户：").NewLine().TextRun(text).NewLine()
+		case "agentMessage":
+			if strings.TrimSpace(item.Text) == "" {
+				continue
+			}
+			section()
+			post.TextRun(item.Text).NewLine()
+		case "commandExecution":
+			if item.Command == "" {
+				continue
+			}
+			section()
+			post.BoldRun("$ " + item.Command)
+			if item.ExitCode != nil && *item.ExitCode != 0 {
+				post.TextRun(fmt.Sprintf(" (exit %d)", *item.ExitCode))
+			}
+			post.NewLine()
+			output := item.AggregatedOutput
+			if output == "" {
+				output = item.Output
+			}
+			if output = strings.TrimRight(output, "\n"); output != "" {
+				post.CodeRun("", tailRunes(tailLines(output, renderOutputTailLines), maxRenderedOutputChars))
+			}
+		case "fileChange":
+			if len(item.Changes) == 0 {
+				continue
+			}
+			section()
+			post.BoldRun("修改文件：").NewLine()
+			for _, ch := range item.Changes {
+				post.TextRun("• " + ch.Path).NewLine()
+			}
+		case "mcpToolCall":
+			if item.Tool == "" {
+				continue
+			}
+			section()
+			name := item.Tool
+			if item.Server != "" {
+				name = item.Server + "." + item.Tool
+			}
+			post.TextRun("🔌 调用工具：").BoldRun(name).NewLine()
+		case "webSearch":
+			if item.Query == "" {
+				continue
+			}
+			section()
+			post.TextRun("🔍 搜索：").BoldRun(item.Query).NewLine()
+		}
+	}
+	return post.Content()
+}
+
+// tailRunes keeps the last max runes of s, prefixed with "…" when cut.
+func tailRunes(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return "…" + string(runes[len(runes)-max:])
+	}
+	return s
+}
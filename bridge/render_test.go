@@ -0,0 +1,84 @@
+package bridge
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+func TestRenderItemsPost(t *testing.T) {
+	exit := 2
+	userContent, _ := json.Marshal([]codex.UserInput{{Type: "text", Text: "[来自 群 'dev' 的 张三]\nfix it"}})
+	items := []codex.ThreadItem{
+		{Type: "userMessage", Content: userContent},
+		{Type: "reasoning", Summary: []string{"thinking"}},
+		{Type: "agentMessage", Text: "On it."},
+		{Type: "commandExecution", Command: "go test ./...", AggregatedOutput: "FAIL\n", ExitCode: &exit},
+		{Type: "fileChange", Changes: []codex.FileChange{{Path: "a.go"}, {Path: "b.go"}}},
+		{Type: "mcpToolCall", Server: "docs", Tool: "search"},
+		{Type: "webSearch", Query: "feishu post"},
+	}
+
+	want := [][]map[string]interface{}{
+		{{"tag": "text", "text": "用户：", "style": []string{"bold"}}},
+		{{"tag": "text", "text": "fix it"}},
+		{},
+		{{"tag": "text", "text": "On it."}},
+		{},
+		{
+			{"tag": "text", "text": "$ go test ./...", "style": []string{"bold"}},
+			{"tag": "text", "text": " (exit 2)"},
+		},
+		{{"tag": "code_block", "text": "FAIL"}},
+		{},
+		{{"tag": "text", "text": "修改文件：", "style": []string{"bold"}}},
+		{{"tag": "text", "text": "• a.go"}},
+		{{"tag": "text", "text": "• b.go"}},
+		{},
+		{
+			{"tag": "text", "text": "🔌 调用工具："},
+			{"tag": "text", "text": "docs.search", "style": []string{"bold"}},
+		},
+		{},
+		{
+			{"tag": "text", "text": "🔍 搜索："},
+			{"tag": "text", "text": "feishu post", "style": []string{"bold"}},
+		},
+	}
+	if got := renderItemsPost(items); !reflect.DeepEqual(got, want) {
+		t.Fatalf("renderItemsPost =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestRenderItemsPost_Empty(t *testing.T) {
+	items := []codex.ThreadItem{
+		{Type: "reasoning", Summary: []string{"x"}},
+		{Type: "agentMessage", Text: "  "},
+		{Type: "commandExecution"},
+	}
+	if got := renderItemsPost(items); len(got) != 0 {
+		t.Fatalf("expected no content, got %#v", got)
+	}
+}
+
+func TestRenderItemsPost_TrimsLongOutput(t *testing.T) {
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, strings.Repeat("x", 50))
+	}
+	items := []codex.ThreadItem{{Type: "commandExecution", Command: "make", Output: strings.Join(lines, "\n")}}
+	got := renderItemsPost(items)
+	if len(got) != 2 {
+		t.Fatalf("expected command and output paragraphs, got %d", len(got))
+	}
+	out := got[1][0]["text"].(string)
+	if !strings.HasPrefix(out, "…") {
+		t.Errorf("expected trimmed output to start with …, got %q", out[:10])
+	}
+	if n := len([]rune(out)); n > maxRenderedOutputChars+1 {
+		t.Errorf("output has %d runes, want at most %d", n, maxRenderedOutputChars+1)
+	}
+}
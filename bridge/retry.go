@@ -0,0 +1,103 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// messageRetryDelay is the pause before retrying a failed codex request; it
+// grows linearly with the attempt number.
+var messageRetryDelay = 2 * time.Second
+
+// maxMessageAttempts returns how many times a message's codex requests are
+// tried before it is dropped.
+func (b *Bridge) maxMessageAttempts() int {
+	if b.config.MaxMessageAttempts < 1 {
+		return 1
+	}
+	return b.config.MaxMessageAttempts
+}
+
+// retryCodexRequest runs op until it succeeds, Config.MaxMessageAttempts is
+// reached, the chat is cleared or ctx is done. Only errors that
+// retryableCodexError considers transient are retried; "thread not found"
+// in particular is returned at once since the caller recovers from it by
+// starting a new thread. It returns the number of attempts made and the
+// last error.
+func (b *Bridge) retryCodexRequest(ctx context.Context, state *ChatState, gen uint64, op func() error) (int, error) {
+	max := b.maxMessageAttempts()
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= max || !retryableCodexError(err) {
+			return attempt, err
+		}
+		fmt.Printf("[Bridge] Codex request failed (attempt %d/%d): %v\n", attempt, max, err)
+		select {
+		case <-time.After(time.Duration(attempt) * messageRetryDelay):
+		case <-ctx.Done():
+			return attempt, err
+		}
+		state.mu.Lock()
+		stale := state.Gen != gen
+		state.mu.Unlock()
+		if stale {
+			return attempt, err
+		}
+	}
+}
+
+// isThreadNotFound reports whether codex no longer knows the thread, e.g.
+// after an app-server restart.
+func isThreadNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "thread not found")
+}
+
+// transientCodexSignatures are lower-case substrings of codex error messages
+// for rate limiting or a busy or overloaded app-server or model. They are
+// matched apart from codexErrorHints, whose hints are only for display.
+var transientCodexSignatures = []string{
+	"rate limit", "rate_limit", "too many requests",
+	"busy", "overloaded", "temporarily unavailable",
+}
+
+// retryableCodexError reports whether a failed codex request may succeed
+// when tried again. Only known-transient errors are: rate limiting, a busy
+// or overloaded server, and a request that never reached the app-server
+// because it had exited. Anything else, including a timeout or an exit after
+// the request was written, may have been acted on already and is returned
+// to the user; so is a client that isn't running, which codexReady handles.
+func retryableCodexError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, codex.ErrNotSent) {
+		return true
+	}
+	message := err.Error()
+	var rpcErr *codex.RPCError
+	if errors.As(err, &rpcErr) {
+		message = rpcErr.Message
+	}
+	lower := strings.ToLower(message)
+	for _, sig := range transientCodexSignatures {
+		if strings.Contains(lower, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// failureAfterAttempts renders a codex failure for the user, noting that the
+// message was dropped when it took more than one attempt.
+func (b *Bridge) failureAfterAttempts(chatID, prefix string, err error, attempts int) string {
+	text := b.codexFailureText(chatID, prefix, err)
+	if attempts > 1 {
+		text += fmt.Sprintf(b.chatMessages(chatID).GaveUpAfter, attempts)
+	}
+	return text
+}
@@ -0,0 +1,126 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestRetryCodexRequest(t *testing.T) {
+	defer func(d time.Duration) { messageRetryDelay = d }(messageRetryDelay)
+	messageRetryDelay = time.Millisecond
+
+	b := &Bridge{config: Config{MaxMessageAttempts: 3}, chatStates: make(map[string]*ChatState)}
+	state := b.getChatState("c1")
+
+	calls := 0
+	attempts, err := b.retryCodexRequest(context.Background(), state, 0, func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("server busy")
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected success on attempt 2, got attempts=%d err=%v", attempts, err)
+	}
+
+	calls = 0
+	attempts, _ = b.retryCodexRequest(context.Background(), state, 0, func() error {
+		calls++
+		return errors.New("thread not found")
+	})
+	if attempts != 1 || calls != 1 {
+		t.Fatalf("expected thread not found not to be retried, got attempts=%d", attempts)
+	}
+}
+
+func TestProcessQueuedMessage_NotRunningIsNotRetried(t *testing.T) {
+	defer func(d time.Duration) { messageRetryDelay = d }(messageRetryDelay)
+	messageRetryDelay = time.Millisecond
+
+	// codex is never started, so every codex request fails.
+	b, m := newTestBridge(t)
+	b.config.MaxMessageAttempts = 3
+
+	b.processQueuedMessage("c1", &feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: "hi"}, 0)
+
+	reply := findReplyText(m, "m1")
+	if !strings.HasPrefix(reply, "❌ 创建会话失败") || strings.Contains(reply, "已尝试") {
+		t.Fatalf("unexpected failure notice %q", reply)
+	}
+	if st := b.getChatState("c1"); st.Processing {
+		t.Fatal("expected the chat to be free for the next message")
+	}
+	if errs := b.formatRecentErrors("c1", false); !strings.Contains(errs, "thread/start (1 attempts)") {
+		t.Fatalf("expected the failed message in recent errors, got %q", errs)
+	}
+}
+
+func TestRetryCodexRequest_TransientThenSuccess(t *testing.T) {
+	defer func(d time.Duration) { messageRetryDelay = d }(messageRetryDelay)
+	messageRetryDelay = time.Millisecond
+
+	b := &Bridge{config: Config{MaxMessageAttempts: 3}, chatStates: make(map[string]*ChatState)}
+	state := b.getChatState("c1")
+	mock := NewMockCodexClient()
+	mock.TurnStartErrors = []error{&codex.RPCError{Code: -32000, Message: "server busy"}}
+
+	var turnID string
+	attempts, err := b.retryCodexRequest(context.Background(), state, 0, func() (err error) {
+		turnID, err = mock.TurnStart(context.Background(), "t1", "hi", nil, nil)
+		return err
+	})
+	if err != nil || attempts != 2 || turnID != mock.NextTurnID {
+		t.Fatalf("expected the turn to start on attempt 2, got attempts=%d turn=%q err=%v", attempts, turnID, err)
+	}
+	if len(mock.StartedTurns) != 1 {
+		t.Fatalf("expected one started turn, got %d", len(mock.StartedTurns))
+	}
+}
+
+func TestRetryableCodexError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("server busy"), true},
+		{&codex.RPCError{Code: -32000, Message: "rate limit exceeded"}, true},
+		{&codex.RPCError{Code: -32000, Message: "rate_limit_exceeded"}, true},
+		{errors.New("429 Too Many Requests"), true},
+		{&codex.RPCError{Code: -32000, Message: "service temporarily unavailable"}, true},
+		{&codex.RPCError{Code: -32000, Message: "model is overloaded"}, true},
+		{fmt.Errorf("request turn/start: %w: broken pipe", codex.ErrNotSent), true},
+		{errors.New("request turn/start timed out after 30s"), false},
+		{context.DeadlineExceeded, false},
+		{fmt.Errorf("request turn/start failed: %w", codex.ErrServerExited), false},
+		{errors.New("client not running"), false},
+		{errors.New("RPC error -32000: thread not found: t1"), false},
+		{&codex.RPCError{Code: -32602, Message: "invalid params"}, false},
+		{&codex.RPCError{Code: -32000, Message: "Not logged in"}, false},
+		{&codex.RPCError{Code: -32000, Message: "insufficient_quota"}, false},
+		{&codex.RPCError{Code: -32000, Message: "You've hit your usage limit."}, false},
+		{&codex.RPCError{Code: -32000, Message: "context_length_exceeded"}, false},
+		{context.Canceled, false},
+	}
+	for _, tt := range tests {
+		if got := retryableCodexError(tt.err); got != tt.want {
+			t.Errorf("retryableCodexError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+
+	// Terminal errors are surfaced after one attempt.
+	b := &Bridge{config: Config{MaxMessageAttempts: 3}, chatStates: make(map[string]*ChatState)}
+	attempts, _ := b.retryCodexRequest(context.Background(), b.getChatState("c1"), 0, func() error {
+		return &codex.RPCError{Code: -32000, Message: "Not logged in"}
+	})
+	if attempts != 1 {
+		t.Fatalf("expected a terminal error not to be retried, got %d attempts", attempts)
+	}
+}
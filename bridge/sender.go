@@ -0,0 +1,65 @@
+package bridge
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// senderContextRe matches the line withSenderContext prepends.
+var senderContextRe = regexp.MustCompile(`(?m)^\[来自 [^\n]*\]\n`)
+
+// withSenderContext prepends who sent a group message, e.g.
+// "[来自 群 '研发组' 的 张三]", when Config.IncludeSenderContext is set, so
+// codex knows who is asking in multi-user chats. Names that can't be
+// resolved are left out; if neither can, content is returned unchanged.
+func (b *Bridge) withSenderContext(msg *feishu.Message) string {
+	if !b.config.IncludeSenderContext || msg.ChatType != "group" || msg.Sender == nil {
+		return msg.Content
+	}
+	chatName, senderName := b.chatNames(msg.ChatID, msg.Sender.SenderID)
+	var line string
+	switch {
+	case chatName != "" && senderName != "":
+		line = fmt.Sprintf("[来自 群 '%s' 的 %s]", chatName, senderName)
+	case senderName != "":
+		line = fmt.Sprintf("[来自 %s]", senderName)
+	case chatName != "":
+		line = fmt.Sprintf("[来自 群 '%s']", chatName)
+	default:
+		return msg.Content
+	}
+	return line + "\n" + msg.Content
+}
+
+// stripSenderContext removes the line withSenderContext prepended, for
+// transcripts echoed back to the chat.
+func stripSenderContext(text string) string {
+	loc := senderContextRe.FindStringIndex(text)
+	if loc == nil {
+		return text
+	}
+	return text[:loc[0]] + text[loc[1]:]
+}
+
+// chatNames returns the chat's name and the name of member openID. Lookups
+// go through the chat info cache when it is enabled.
+func (b *Bridge) chatNames(chatID, openID string) (chatName, memberName string) {
+	if info, err := b.feishuClient.GetChatInfo(chatID); err != nil {
+		b.debugf("Failed to get chat info for %s: %v", chatID, err)
+	} else if info != nil {
+		chatName = info.Name
+	}
+	members, err := b.feishuClient.GetChatMembers(chatID)
+	if err != nil {
+		b.debugf("Failed to get chat members for %s: %v", chatID, err)
+	}
+	for _, m := range members {
+		if m.MemberID == openID {
+			memberName = m.Name
+			break
+		}
+	}
+	return chatName, memberName
+}
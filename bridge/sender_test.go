@@ -0,0 +1,47 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestWithSenderContext(t *testing.T) {
+	m := &MockFeishuClient{
+		ChatInfos: map[string]*feishu.ChatInfo{"oc_1": {ChatID: "oc_1", Name: "研发组"}},
+		ChatMembers: map[string][]*feishu.ChatMember{
+			"oc_1": {{MemberID: "ou_1", Name: "张三"}},
+		},
+	}
+	b := &Bridge{config: Config{IncludeSenderContext: true}, feishuClient: m}
+	msg := func(chatType, sender string) *feishu.Message {
+		return &feishu.Message{ChatID: "oc_1", ChatType: chatType, Content: "帮我看下日志", Sender: &feishu.Sender{SenderID: sender}}
+	}
+
+	if got, want := b.withSenderContext(msg("group", "ou_1")), "[来自 群 '研发组' 的 张三]\n帮我看下日志"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := b.withSenderContext(msg("group", "ou_unknown")), "[来自 群 '研发组']\n帮我看下日志"; got != want {
+		t.Errorf("unknown sender: got %q, want %q", got, want)
+	}
+	if got := b.withSenderContext(msg("p2p", "ou_1")); got != "帮我看下日志" {
+		t.Errorf("p2p message should be unchanged, got %q", got)
+	}
+
+	b.config.IncludeSenderContext = false
+	if got := b.withSenderContext(msg("group", "ou_1")); got != "帮我看下日志" {
+		t.Errorf("expected no context when disabled, got %q", got)
+	}
+}
+
+func TestStripSenderContext(t *testing.T) {
+	for in, want := range map[string]string{
+		"[来自 群 '研发组' 的 张三]\n帮我看下日志":      "帮我看下日志",
+		"项目说明\n\n---\n\n[来自 张三]\n帮我看下日志": "项目说明\n\n---\n\n帮我看下日志",
+		"普通消息": "普通消息",
+	} {
+		if got := stripSenderContext(in); got != want {
+			t.Errorf("stripSenderContext(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
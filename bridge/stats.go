@@ -0,0 +1,114 @@
+package bridge
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// bridgeStats holds process-wide counters. All fields are updated atomically
+// so the hot path doesn't contend on a mutex.
+type bridgeStats struct {
+	startedAt        atomic.Int64 // unix nanos, 0 until Start
+	turnsStarted     atomic.Int64
+	turnsCompleted   atomic.Int64
+	turnsFailed      atomic.Int64
+	turnsInterrupted atomic.Int64
+	turnsWaiting     atomic.Int64 // blocked on Config.MaxConcurrentTurns
+}
+
+// StatsSnapshot is a point-in-time copy of the bridge counters.
+type StatsSnapshot struct {
+	Uptime           time.Duration
+	TurnsStarted     int64
+	TurnsCompleted   int64
+	TurnsFailed      int64
+	TurnsInterrupted int64
+
+	// Load at snapshot time.
+	PendingMessages    int   // queued across all chats
+	ActiveTurns        int   // codex turns in flight
+	WaitingTurns       int64 // waiting for a concurrency slot
+	MaxConcurrentTurns int   // 0 = unlimited
+}
+
+func (s *bridgeStats) markStarted(now time.Time) {
+	s.startedAt.Store(now.UnixNano())
+}
+
+// recordTurnCompleted classifies a finished turn by its status.
+func (s *bridgeStats) recordTurnCompleted(status string) {
+	switch status {
+	case "failed":
+		s.turnsFailed.Add(1)
+	case "interrupted":
+		s.turnsInterrupted.Add(1)
+	default:
+		s.turnsCompleted.Add(1)
+	}
+}
+
+// Stats returns a snapshot of the bridge counters.
+func (b *Bridge) Stats() StatsSnapshot {
+	snap := StatsSnapshot{
+		TurnsStarted:     b.stats.turnsStarted.Load(),
+		TurnsCompleted:   b.stats.turnsCompleted.Load(),
+		TurnsFailed:      b.stats.turnsFailed.Load(),
+		TurnsInterrupted: b.stats.turnsInterrupted.Load(),
+
+		PendingMessages:    b.pendingMessageCount(),
+		ActiveTurns:        b.activeTurnCount(),
+		WaitingTurns:       b.stats.turnsWaiting.Load(),
+		MaxConcurrentTurns: b.config.MaxConcurrentTurns,
+	}
+	if started := b.stats.startedAt.Load(); started > 0 {
+		snap.Uptime = time.Since(time.Unix(0, started))
+	}
+	return snap
+}
+
+func (b *Bridge) formatStats(chatID string) string {
+	msgs := b.chatMessages(chatID)
+	snap := b.Stats()
+	uptime := msgs.StatsNotStarted
+	if snap.Uptime > 0 {
+		uptime = snap.Uptime.Round(time.Second).String()
+	}
+	out := fmt.Sprintf(msgs.Stats,
+		uptime, snap.TurnsStarted, snap.TurnsCompleted, snap.TurnsFailed, snap.TurnsInterrupted,
+		snap.PendingMessages, snap.ActiveTurns)
+	if snap.MaxConcurrentTurns > 0 {
+		out += fmt.Sprintf(msgs.StatsLimit, snap.MaxConcurrentTurns, snap.WaitingTurns)
+	}
+	return out
+}
+
+// metricsHandler serves the stats snapshot in the Prometheus text format.
+func (b *Bridge) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write([]byte(formatMetrics(b.Stats())))
+}
+
+func formatMetrics(snap StatsSnapshot) string {
+	var sb strings.Builder
+	metric := func(name, kind, help string, value any) {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, kind, name, value)
+	}
+	metric("bridge_uptime_seconds", "gauge", "Seconds since the bridge started.", int64(snap.Uptime.Seconds()))
+	metric("bridge_turns_started_total", "counter", "Codex turns started.", snap.TurnsStarted)
+	metric("bridge_turns_completed_total", "counter", "Codex turns completed.", snap.TurnsCompleted)
+	metric("bridge_turns_failed_total", "counter", "Codex turns that failed.", snap.TurnsFailed)
+	metric("bridge_turns_interrupted_total", "counter", "Codex turns that were interrupted.", snap.TurnsInterrupted)
+	metric("bridge_pending_messages", "gauge", "Messages queued across all chats.", snap.PendingMessages)
+	metric("bridge_active_turns", "gauge", "Codex turns in flight.", snap.ActiveTurns)
+	metric("bridge_waiting_turns", "gauge", "Turns waiting for a concurrency slot.", snap.WaitingTurns)
+	metric("bridge_max_concurrent_turns", "gauge", "Configured turn concurrency limit (0 = unlimited).", snap.MaxConcurrentTurns)
+	return sb.String()
+}
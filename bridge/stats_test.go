@@ -0,0 +1,31 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+func TestHandleTurnCompleted_IncrementsCounters(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.getChatState("c1").ThreadID = "t1"
+
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "completed"})
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn2", Status: "failed"})
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn3", Status: "interrupted"})
+
+	snap := b.Stats()
+	if snap.TurnsCompleted != 1 || snap.TurnsFailed != 1 || snap.TurnsInterrupted != 1 {
+		t.Fatalf("unexpected counters: %+v", snap)
+	}
+}
+
+func TestFormatStats_NotStarted(t *testing.T) {
+	b := &Bridge{}
+	b.stats.turnsStarted.Add(3)
+	out := b.formatStats("c1")
+	if !strings.Contains(out, "运行时长：未启动") || !strings.Contains(out, "已开始：3") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
@@ -1,12 +1,16 @@
 package bridge
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 func (b *Bridge) formatStatus(chatID string) string {
 	state := b.getChatState(chatID)
 	state.mu.Lock()
 	processing := state.Processing
 	lastItem := state.LastItem
+	paused := state.Paused
 	state.mu.Unlock()
 
 	pendingCount := 0
@@ -19,13 +23,46 @@ func (b *Bridge) formatStatus(chatID string) string {
 		q.mu.Unlock()
 	}
 
-	if !processing {
-		return fmt.Sprintf("状态：空闲\n待处理：%d", pendingCount)
+	msgs := b.chatMessages(chatID)
+	var out string
+	if paused && !processing {
+		out = msgs.StatusPaused + "\n" + fmt.Sprintf(msgs.Pending, pendingCount)
+	} else if !processing {
+		out = msgs.StatusIdle + "\n" + fmt.Sprintf(msgs.Pending, pendingCount)
+	} else {
+		step := lastItem
+		if step == "" {
+			step = msgs.StatusDefaultStep
+		}
+		out = msgs.StatusProcessing + "\n" + fmt.Sprintf(msgs.StatusStep, step) + "\n" + fmt.Sprintf(msgs.Pending, pendingCount)
+	}
+
+	if gitLine := formatGitStatus(b.config.WorkingDir, msgs); gitLine != "" {
+		out += "\n" + gitLine
 	}
+	return out
+}
+
+// formatElapsedStatus answers /elapsed: how long the running turn has taken
+// so far and its current step.
+func (b *Bridge) formatElapsedStatus(chatID string) string {
+	msgs := b.chatMessages(chatID)
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	processing := state.Processing
+	startedAt := state.TurnStartedAt
+	step := state.LastItem
+	state.mu.Unlock()
 
-	step := lastItem
+	if !processing {
+		return msgs.ElapsedIdle
+	}
+	if startedAt.IsZero() {
+		// Still waiting for warmup, images or turn/start.
+		return msgs.ElapsedStarting
+	}
 	if step == "" {
-		step = "生成回复"
+		step = msgs.StatusDefaultStep
 	}
-	return fmt.Sprintf("状态：处理中\n当前步骤：%s\n待处理：%d", step, pendingCount)
+	return fmt.Sprintf(msgs.Elapsed, formatElapsed(time.Since(startedAt)), step)
 }
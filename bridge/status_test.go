@@ -1,8 +1,16 @@
 package bridge
 
 import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
 )
 
 func TestFormatStatus_Idle(t *testing.T) {
@@ -18,3 +26,127 @@ func TestFormatStatus_Idle(t *testing.T) {
 		t.Fatalf("unexpected output: %q", out)
 	}
 }
+
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.name", "t"},
+		{"config", "user.email", "t@example.com"},
+		{"commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestFormatStatus_GitRepo(t *testing.T) {
+	dir := initGitRepo(t)
+	b := &Bridge{
+		config:     Config{WorkingDir: dir},
+		chatQueues: make(map[string]*chatQueue),
+		chatStates: make(map[string]*ChatState),
+	}
+
+	if out := b.formatStatus("c1"); !strings.Contains(out, "分支：main（工作区干净）") {
+		t.Fatalf("expected clean branch line, got %q", out)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out := b.formatStatus("c1"); !strings.Contains(out, "分支：main（有未提交改动）") {
+		t.Fatalf("expected dirty branch line, got %q", out)
+	}
+}
+
+func TestFormatStatus_NotGitRepo(t *testing.T) {
+	b := &Bridge{
+		config:     Config{WorkingDir: t.TempDir()},
+		chatQueues: make(map[string]*chatQueue),
+		chatStates: make(map[string]*ChatState),
+	}
+	if out := b.formatStatus("c1"); strings.Contains(out, "分支") {
+		t.Fatalf("expected no git line outside a repo, got %q", out)
+	}
+}
+
+func TestFormatElapsedStatus(t *testing.T) {
+	b := &Bridge{chatStates: make(map[string]*ChatState)}
+	if got := b.formatElapsedStatus("c1"); got != "当前无进行中的任务" {
+		t.Fatalf("idle: got %q", got)
+	}
+
+	state := b.getChatState("c1")
+	state.Processing = true
+	// Processing but turn/start has not returned yet: no elapsed time to show.
+	if got := b.formatElapsedStatus("c1"); got != "⏳ 任务正在启动，尚未开始计时" {
+		t.Fatalf("starting: got %q", got)
+	}
+
+	state.TurnStartedAt = time.Now().Add(-95 * time.Second)
+	state.LastItem = "执行命令: go test ./..."
+	got := b.formatElapsedStatus("c1")
+	if !strings.HasPrefix(got, "已运行 1m3") || !strings.HasSuffix(got, "当前步骤：执行命令: go test ./...") {
+		t.Fatalf("running: got %q", got)
+	}
+
+	state.LastItem = ""
+	if got := b.formatElapsedStatus("c1"); !strings.HasSuffix(got, "当前步骤：生成回复") {
+		t.Fatalf("default step: got %q", got)
+	}
+}
+
+func TestTurnStartedAt_SetOnStartClearedOnCompletion(t *testing.T) {
+	useFakeCodex(t)
+
+	b, _ := newTestBridge(t)
+	if err := b.codexClient.Start(context.Background()); err != nil {
+		t.Skipf("fake codex did not start: %v", err)
+	}
+	t.Cleanup(func() { b.codexClient.Stop() })
+
+	state := b.getChatState("c1")
+
+	before := time.Now()
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		b.processQueuedMessage("c1", &feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: "hi"}, 0)
+	}()
+
+	var startedAt time.Time
+	for deadline := time.Now().Add(5 * time.Second); startedAt.IsZero(); {
+		if time.Now().After(deadline) {
+			t.Fatal("TurnStartedAt not set after turn/start")
+		}
+		time.Sleep(5 * time.Millisecond)
+		state.mu.Lock()
+		startedAt = state.TurnStartedAt
+		state.mu.Unlock()
+	}
+	if startedAt.Before(before) || startedAt.After(time.Now()) {
+		t.Errorf("TurnStartedAt = %v, want the time turn/start returned", startedAt)
+	}
+
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "thread-1", TurnID: "turn-1", Status: "completed"})
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("processQueuedMessage did not return after turn/completed")
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if !state.TurnStartedAt.IsZero() {
+		t.Errorf("TurnStartedAt = %v after completion, want zero", state.TurnStartedAt)
+	}
+}
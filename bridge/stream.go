@@ -0,0 +1,283 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Streaming modes for /stream. They decide when a turn's agent message
+// reaches the chat.
+const (
+	StreamModeFinal  = "final"  // one reply when the turn completes (default)
+	StreamModeStream = "stream" // one reply, edited as deltas arrive
+	StreamModeChunks = "chunks" // a reply per paragraph as it finishes
+)
+
+// streamModes are the values /stream accepts.
+var streamModes = map[string]bool{StreamModeFinal: true, StreamModeStream: true, StreamModeChunks: true}
+
+// streamingMarker ends the live reply of StreamModeStream until the turn
+// completes.
+const streamingMarker = "\n\n⏳"
+
+// streamState is what a turn has already posted in its chat's streaming
+// mode. It is reset when a turn starts and taken when it completes.
+type streamState struct {
+	mode     string    // ChatState.StreamMode when the turn started
+	msgID    string    // StreamModeStream: the live reply; empty until posted
+	edits    int       // StreamModeStream: edits of msgID so far
+	lastEdit time.Time // StreamModeStream: when msgID was last posted or edited
+	sent     int       // StreamModeChunks: bytes of Buffer already posted
+	chunks   int       // StreamModeChunks: replies posted
+	worker   *streamWorker
+}
+
+// streamWorker posts a turn's streamed output in StreamModeStream and
+// StreamModeChunks, so Feishu requests don't hold up the codex event
+// goroutine; handleAgentDelta only wakes it.
+type streamWorker struct {
+	wake     chan struct{} // buffered, so deltas arriving mid-post coalesce
+	quit     chan struct{}
+	exited   chan struct{}
+	stopOnce sync.Once
+	queued   atomic.Uint64 // deltas that woke the worker
+	handled  atomic.Uint64 // deltas whose output was posted
+}
+
+// stop tells the worker to exit without waiting for it. It is nil-safe.
+func (w *streamWorker) stop() {
+	if w != nil {
+		w.stopOnce.Do(func() { close(w.quit) })
+	}
+}
+
+// finish stops the worker and waits for a post in flight, so the caller
+// sees everything it posted. It is nil-safe; state.mu must not be held.
+func (w *streamWorker) finish() {
+	if w != nil {
+		w.stop()
+		<-w.exited
+	}
+}
+
+// beginStreamLocked resets the chat's stream state for a new turn, fixing
+// its mode so /stream mid-turn takes effect on the next one, and starts the
+// turn's stream worker. state.mu must be held.
+func (b *Bridge) beginStreamLocked(chatID string, state *ChatState) {
+	// A turn cleared before it completed leaves its worker behind.
+	state.stream.worker.stop()
+	state.stream = streamState{mode: state.StreamMode}
+	if state.stream.mode != StreamModeStream && state.stream.mode != StreamModeChunks {
+		return
+	}
+	w := &streamWorker{
+		wake:   make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	state.stream.worker = w
+	mode := state.stream.mode
+
+	var ctxDone <-chan struct{}
+	if b.ctx != nil {
+		ctxDone = b.ctx.Done()
+	}
+	go func() {
+		defer close(w.exited)
+		for {
+			select {
+			case <-w.quit:
+				return
+			case <-ctxDone:
+				return
+			case <-w.wake:
+			}
+			queued := w.queued.Load()
+			if mode == StreamModeStream {
+				b.streamLiveReply(chatID, state)
+			} else {
+				b.streamChunks(chatID, state)
+			}
+			w.handled.Store(queued)
+		}
+	}()
+}
+
+// loadChatStreamModes restores the modes chats chose with /stream before a
+// restart.
+func (b *Bridge) loadChatStreamModes() {
+	modes, err := b.sessionStore.ChatStreamModes()
+	if err != nil {
+		fmt.Printf("[Bridge] Failed to load chat stream modes: %v\n", err)
+		return
+	}
+	for chatID, mode := range modes {
+		if !streamModes[mode] {
+			continue
+		}
+		state := b.getChatState(chatID)
+		state.mu.Lock()
+		state.StreamMode = mode
+		state.mu.Unlock()
+	}
+}
+
+// setStreamMode handles /stream: an empty arg reports the chat's current
+// mode, otherwise arg is validated and stored for later turns.
+func (b *Bridge) setStreamMode(chatID, arg string) string {
+	state := b.getChatState(chatID)
+	if arg == "" {
+		state.mu.Lock()
+		mode := state.StreamMode
+		state.mu.Unlock()
+		if mode == "" {
+			mode = StreamModeFinal
+		}
+		return fmt.Sprintf(b.chatMessages(chatID).StreamCurrent, mode, b.withPrefix("/stream"))
+	}
+	if !streamModes[arg] {
+		return fmt.Sprintf(b.chatMessages(chatID).StreamUsage, b.withPrefix("/stream"))
+	}
+	// The default is stored as "", so the chat's state can be evicted.
+	mode := arg
+	if mode == StreamModeFinal {
+		mode = ""
+	}
+	if err := b.sessionStore.SetStreamMode(chatID, mode); err != nil {
+		fmt.Printf("[Bridge] Failed to persist stream mode for %s: %v\n", chatID, err)
+	}
+	state.mu.Lock()
+	state.StreamMode = mode
+	state.mu.Unlock()
+	return fmt.Sprintf(b.chatMessages(chatID).StreamSet, arg)
+}
+
+// streamDelta wakes the turn's stream worker after an agent delta was
+// buffered for chatID, so it posts what the streaming mode releases.
+func (b *Bridge) streamDelta(chatID string) {
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	w := state.stream.worker
+	state.mu.Unlock()
+	if w == nil {
+		return
+	}
+	w.queued.Add(1)
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// streamLiveReply posts the buffered response as a reply, then keeps it
+// up to date with edits at most once per liveOutputInterval. The last of
+// Feishu's liveOutputMaxEdits edits is kept for the final response.
+func (b *Bridge) streamLiveReply(chatID string, state *ChatState) {
+	notice := b.truncatedNotice(chatID)
+	state.mu.Lock()
+	msgID := state.MsgID
+	st := state.stream
+	if msgID == "" || st.edits >= liveOutputMaxEdits-1 || time.Since(st.lastEdit) < liveOutputInterval {
+		state.mu.Unlock()
+		return
+	}
+	text := truncateResponse(state.Buffer.String(), b.config.MaxResponseChars, notice)
+	text = b.quotePrompt(state.Prompt, text) + streamingMarker
+	replyInThread := state.ChatType == "group"
+	state.mu.Unlock()
+
+	if st.msgID == "" {
+		id, err := b.replyTextWithID(chatID, msgID, text, replyInThread)
+		if err != nil {
+			b.debugf("Failed to post streamed reply: %v", err)
+			return
+		}
+		st.msgID = id
+	} else {
+		if err := b.feishuClient.UpdateText(st.msgID, text); err != nil {
+			b.debugf("Failed to update streamed reply %s: %v", st.msgID, err)
+			return
+		}
+		st.edits++
+	}
+
+	state.mu.Lock()
+	// The turn may have been cleared while the request was in flight.
+	if state.MsgID == msgID {
+		state.stream.msgID = st.msgID
+		state.stream.edits = st.edits
+		state.stream.lastEdit = time.Now()
+	}
+	state.mu.Unlock()
+}
+
+// finishLiveReply edits the final response into the turn's live reply. It
+// reports false when there is no live reply or the edit failed, so the
+// caller sends the response as usual.
+func (b *Bridge) finishLiveReply(st streamState, response string) bool {
+	if st.msgID == "" || st.edits >= liveOutputMaxEdits {
+		return false
+	}
+	if err := b.feishuClient.UpdateText(st.msgID, response); err != nil {
+		fmt.Printf("[Bridge] Failed to finish streamed reply %s: %v\n", st.msgID, err)
+		return false
+	}
+	return true
+}
+
+// streamChunks replies with each paragraph of the buffer that has
+// finished, i.e. is followed by a blank line outside a code block. The
+// rest is sent when the turn completes.
+func (b *Bridge) streamChunks(chatID string, state *ChatState) {
+	state.mu.Lock()
+	msgID := state.MsgID
+	if msgID == "" {
+		state.mu.Unlock()
+		return
+	}
+	first := state.stream.chunks == 0
+	var chunks []string
+	for {
+		pending := state.Buffer.String()[state.stream.sent:]
+		end := paragraphEnd(pending)
+		if end == 0 {
+			break
+		}
+		state.stream.sent += end
+		if chunk := strings.TrimSpace(pending[:end]); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+	}
+	state.stream.chunks += len(chunks)
+	prompt := state.Prompt
+	replyInThread := state.ChatType == "group"
+	state.mu.Unlock()
+
+	for i, chunk := range chunks {
+		if first && i == 0 {
+			chunk = b.quotePrompt(prompt, chunk)
+		}
+		b.sendResponse(chatID, msgID, chunk, replyInThread)
+	}
+}
+
+// paragraphEnd returns the offset just past the first blank line in s that
+// is outside a ``` code block, or 0 if there is none.
+func paragraphEnd(s string) int {
+	inFence := false
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "```"):
+			inFence = !inFence
+			i += 3
+		case !inFence && strings.HasPrefix(s[i:], "\n\n"):
+			return i + 2
+		default:
+			i++
+		}
+	}
+	return 0
+}
@@ -0,0 +1,135 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// runStreamedTurn feeds deltas to a turn of a chat in mode and completes
+// it, returning the mock that recorded what was sent.
+func runStreamedTurn(t *testing.T, mode string, deltas ...string) *MockFeishuClient {
+	t.Helper()
+	b, m := newTestBridge(t)
+	if got := b.setStreamMode("c1", mode); got != "✅ 输出方式已设为 "+mode+"，从下一轮开始生效" {
+		t.Fatalf("unexpected /stream reply %q", got)
+	}
+	state := b.getChatState("c1")
+	state.ThreadID = "t1"
+	state.MsgID = "m1"
+	b.beginStreamLocked("c1", state)
+
+	for _, delta := range deltas {
+		b.handleAgentDelta(codex.AgentMessageDeltaParams{ThreadID: "t1", ItemID: "item1", Delta: delta})
+		waitStreamed(t, state)
+	}
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "completed"})
+	return m
+}
+
+// waitStreamed waits until the chat's stream worker posted the output of
+// every delta so far.
+func waitStreamed(t *testing.T, state *ChatState) {
+	t.Helper()
+	state.mu.Lock()
+	w := state.stream.worker
+	state.mu.Unlock()
+	if w == nil {
+		return
+	}
+	for deadline := time.Now().Add(5 * time.Second); w.handled.Load() != w.queued.Load(); {
+		if time.Now().After(deadline) {
+			t.Fatal("stream worker did not post the buffered deltas")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func sentTexts(m *MockFeishuClient) []string {
+	var texts []string
+	for _, sm := range m.SentMessages {
+		texts = append(texts, sm.Text)
+	}
+	return texts
+}
+
+func TestStreamMode_Final(t *testing.T) {
+	m := runStreamedTurn(t, StreamModeFinal, "first paragraph\n\n", "second")
+
+	if got := sentTexts(m); len(got) != 1 || got[0] != "first paragraph\n\nsecond" {
+		t.Errorf("expected one reply with the whole response, got %q", got)
+	}
+	if len(m.UpdatedMessages) != 0 {
+		t.Errorf("expected no edits, got %+v", m.UpdatedMessages)
+	}
+}
+
+func TestStreamMode_Stream(t *testing.T) {
+	m := runStreamedTurn(t, StreamModeStream, "Hello", " world")
+
+	// The first delta is posted right away; the second falls within
+	// liveOutputInterval and only shows up in the final edit.
+	if got := sentTexts(m); len(got) != 1 || got[0] != "Hello"+streamingMarker {
+		t.Fatalf("expected one live reply, got %q", got)
+	}
+	if len(m.UpdatedMessages) != 1 {
+		t.Fatalf("expected one final edit, got %+v", m.UpdatedMessages)
+	}
+	if edit := m.UpdatedMessages[0]; edit.MsgID != "mock-msg-1" || edit.Text != "Hello world" {
+		t.Errorf("expected the live reply to be edited to the full response, got %+v", edit)
+	}
+}
+
+func TestStreamMode_Chunks(t *testing.T) {
+	m := runStreamedTurn(t, StreamModeChunks,
+		"first paragraph\n",
+		"\n```go\nfunc f() {\n\n}\n```\n\n",
+		"last",
+	)
+
+	want := []string{"first paragraph", "```go\nfunc f() {\n\n}\n```", "last"}
+	got := sentTexts(m)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d chunks, got %q", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chunk %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	// With every paragraph already sent, completion adds no reply.
+	m = runStreamedTurn(t, StreamModeChunks, "only paragraph\n\n")
+	if got := sentTexts(m); len(got) != 1 || got[0] != "only paragraph" {
+		t.Errorf("expected just the chunk, got %q", got)
+	}
+	if len(m.Reactions) == 0 {
+		t.Error("expected the completion reaction to be added")
+	}
+}
+
+func TestSetStreamMode(t *testing.T) {
+	b, _ := newTestBridge(t)
+	if got := b.setStreamMode("c1", ""); got != "当前输出方式：final（可用 /stream final|stream|chunks 修改：final 完成后一次性回复，stream 实时更新同一条回复，chunks 每写完一段就发送）" {
+		t.Errorf("unexpected current mode reply %q", got)
+	}
+	if got := b.setStreamMode("c1", "live"); got != "⚠️ 用法：/stream <final|stream|chunks>" {
+		t.Errorf("expected usage for an unknown mode, got %q", got)
+	}
+	if b.getChatState("c1").StreamMode != "" {
+		t.Error("unknown mode should not be stored")
+	}
+
+	b.setStreamMode("c1", StreamModeChunks)
+	restarted, _ := newTestBridge(t, restartOf(b))
+	restarted.loadChatStreamModes()
+	if got := restarted.getChatState("c1").StreamMode; got != StreamModeChunks {
+		t.Errorf("stream mode after a restart = %q, want chunks", got)
+	}
+	// Going back to the default clears the stored mode.
+	b.setStreamMode("c1", StreamModeFinal)
+	if modes, _ := b.sessionStore.ChatStreamModes(); len(modes) != 0 {
+		t.Errorf("stored modes after /stream final = %v, want none", modes)
+	}
+}
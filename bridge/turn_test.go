@@ -0,0 +1,90 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+func TestHandleTurnCompleted_TruncatesLongResponse(t *testing.T) {
+	b, m := newTestBridge(t)
+	b.config.MaxResponseChars = 10
+	state := b.getChatState("c1")
+	state.ThreadID = "t1"
+	state.MsgID = "m1"
+	state.Buffer.WriteString(strings.Repeat("长", 50))
+
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "failed"})
+
+	want := strings.Repeat("长", 10) + "\n\n" + b.truncatedNotice("c1") + "\n\n❌ 本轮任务失败"
+	if len(m.SentMessages) != 1 || m.SentMessages[0].Text != want {
+		t.Fatalf("expected truncated reply %q, got %+v", want, m.SentMessages)
+	}
+}
+
+func TestHandleTurnCompleted_ReportsStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		buffer   string
+		err      *codex.TurnError
+		reaction string
+		want     string
+	}{
+		{"completed", "hello", nil, "DONE", "hello"},
+		{"completed", "", nil, "DONE", "✅（无文字回应）"},
+		{"failed", "", nil, turnFailedEmoji, "❌ 本轮任务失败"},
+		{"failed", "partial", nil, turnFailedEmoji, "partial\n\n❌ 本轮任务失败"},
+		{"failed", "", &codex.TurnError{Type: "overloaded", Message: "model is overloaded"}, turnFailedEmoji, "❌ 本轮任务失败：model is overloaded"},
+		{"failed", "", &codex.TurnError{Message: "401 Unauthorized"}, turnFailedEmoji, "❌ 本轮任务失败：Codex 未登录或登录已过期，请在服务器上运行 codex login 后重试"},
+		{"interrupted", "", nil, turnFailedEmoji, "⏹ 本轮任务已中断"},
+		{"interrupted", "partial", nil, turnFailedEmoji, "⏹ 本轮任务已中断\n\npartial"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			b, m := newTestBridge(t)
+			state := b.getChatState("c1")
+			state.ThreadID = "t1"
+			state.MsgID = "m1"
+			state.Buffer.WriteString(tt.buffer)
+
+			b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: tt.status, Error: tt.err})
+
+			if len(m.Reactions) != 1 || m.Reactions[0].EmojiType != tt.reaction {
+				t.Fatalf("expected %s reaction, got %+v", tt.reaction, m.Reactions)
+			}
+			if len(m.SentMessages) != 1 || m.SentMessages[0].Text != tt.want {
+				t.Fatalf("expected reply %q, got %+v", tt.want, m.SentMessages)
+			}
+		})
+	}
+}
+
+func TestHandleTurnCompleted_Interrupted(t *testing.T) {
+	for _, policy := range []string{InterruptedOutputKeep, InterruptedOutputDiscard} {
+		b, m := newTestBridge(t)
+		b.config.InterruptedOutput = policy
+		state := b.getChatState("c1")
+		state.ThreadID = "t1"
+		state.MsgID = "m1"
+		state.ProcessingReactionID = "r1"
+		state.Buffer.WriteString("half an answer")
+
+		b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "t1", TurnID: "turn1", Status: "interrupted"})
+
+		if len(m.Reactions) != 2 || !m.Reactions[0].IsRemove || m.Reactions[0].ReactionID != "r1" {
+			t.Fatalf("%s: expected the processing reaction to be removed, got %+v", policy, m.Reactions)
+		}
+		if got := m.Reactions[1].EmojiType; got != turnFailedEmoji {
+			t.Errorf("%s: expected %s instead of DONE, got %s", policy, turnFailedEmoji, got)
+		}
+		want := "⏹ 本轮任务已中断\n\nhalf an answer"
+		if policy == InterruptedOutputDiscard {
+			want = "⏹ 本轮任务已中断"
+		}
+		if len(m.SentMessages) != 1 || m.SentMessages[0].Text != want {
+			t.Errorf("%s: expected reply %q, got %+v", policy, want, m.SentMessages)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// maxVerboseChars caps a single forwarded reasoning summary or command.
+const maxVerboseChars = 500
+
+// defaultVerbose reports whether a chat of chatType starts in verbose mode.
+func (b *Bridge) defaultVerbose(chatType string) bool {
+	if chatType == "group" {
+		return b.config.VerboseGroup
+	}
+	return b.config.VerboseP2P
+}
+
+// applyVerboseDefaultLocked sets the chat's verbose mode from its chat type
+// the first time it is seen, unless /verbose already chose one. Callers
+// must hold state.mu.
+func (b *Bridge) applyVerboseDefaultLocked(state *ChatState, chatType string) {
+	if state.verboseSet {
+		return
+	}
+	if state.ChatType == "" {
+		state.ChatType = chatType
+	}
+	state.Verbose = b.defaultVerbose(chatType)
+	state.verboseSet = true
+}
+
+// setVerbose handles /verbose and returns the reply text. arg is "on",
+// "off", or "" to toggle.
+func (b *Bridge) setVerbose(chatID, chatType, arg string) string {
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	b.applyVerboseDefaultLocked(state, chatType)
+	switch arg {
+	case "on":
+		state.Verbose = true
+	case "off":
+		state.Verbose = false
+	default:
+		state.Verbose = !state.Verbose
+	}
+	on := state.Verbose
+	state.mu.Unlock()
+
+	if on {
+		return b.chatMessages(chatID).VerboseOn
+	}
+	return b.chatMessages(chatID).VerboseOff
+}
+
+// forwardVerboseItem posts a completed reasoning or command item to the
+// chat when it is in verbose mode.
+func (b *Bridge) forwardVerboseItem(chatID string, item *codex.ThreadItem) {
+	text := formatVerboseItem(item)
+	if text == "" {
+		return
+	}
+	state := b.getChatState(chatID)
+	state.mu.Lock()
+	verbose := state.Verbose
+	msgID := state.MsgID
+	replyInThread := state.ChatType == "group"
+	state.mu.Unlock()
+	if !verbose || msgID == "" {
+		return
+	}
+	b.replyText(chatID, msgID, text, replyInThread)
+}
+
+// formatVerboseItem renders the reasoning summary or command of a completed
+// item, or "" for items that aren't forwarded.
+func formatVerboseItem(item *codex.ThreadItem) string {
+	if item == nil {
+		return ""
+	}
+	switch item.Type {
+	case "reasoning":
+		summary := strings.TrimSpace(strings.Join(item.Summary, "\n"))
+		if summary == "" {
+			return ""
+		}
+		return "💭 " + truncateRunes(summary, maxVerboseChars)
+	case "commandExecution":
+		if item.Command == "" {
+			return ""
+		}
+		text := "🔧 $ " + truncateRunes(item.Command, maxVerboseChars)
+		if item.ExitCode != nil && *item.ExitCode != 0 {
+			text += fmt.Sprintf(" (exit %d)", *item.ExitCode)
+		}
+		return text
+	}
+	return ""
+}
+
+func truncateRunes(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max]) + "…"
+	}
+	return s
+}
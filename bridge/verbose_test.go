@@ -0,0 +1,80 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+func TestApplyVerboseDefault_ByChatType(t *testing.T) {
+	b := &Bridge{config: Config{VerboseP2P: true}}
+
+	p2p := &ChatState{}
+	b.applyVerboseDefaultLocked(p2p, "p2p")
+	group := &ChatState{}
+	b.applyVerboseDefaultLocked(group, "group")
+	if !p2p.Verbose || group.Verbose {
+		t.Fatalf("expected p2p verbose and group quiet, got p2p=%v group=%v", p2p.Verbose, group.Verbose)
+	}
+}
+
+func TestSetVerbose_OverridesDefault(t *testing.T) {
+	b := &Bridge{
+		config:     Config{VerboseP2P: true},
+		chatStates: make(map[string]*ChatState),
+	}
+
+	if got := b.setVerbose("c1", "p2p", ""); got != DefaultMessages().VerboseOff {
+		t.Fatalf("expected toggle to turn off the p2p default, got %q", got)
+	}
+	// The default must not come back once the chat chose a mode.
+	state := b.getChatState("c1")
+	b.applyVerboseDefaultLocked(state, "p2p")
+	if state.Verbose {
+		t.Fatal("expected /verbose choice to stick")
+	}
+	if got := b.setVerbose("c1", "p2p", "on"); got != DefaultMessages().VerboseOn || !state.Verbose {
+		t.Fatalf("expected verbose on, got %q", got)
+	}
+}
+
+func TestForwardVerboseItem(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		feishuClient: m,
+		chatStates:   make(map[string]*ChatState),
+	}
+	state := b.getChatState("c1")
+	state.MsgID = "m1"
+	item := &codex.ThreadItem{Type: "commandExecution", Command: "go test ./..."}
+
+	b.forwardVerboseItem("c1", item)
+	if len(m.SentMessages) != 0 {
+		t.Fatalf("expected nothing forwarded when quiet, got %+v", m.SentMessages)
+	}
+
+	state.Verbose = true
+	b.forwardVerboseItem("c1", item)
+	if len(m.SentMessages) != 1 || m.SentMessages[0].MsgID != "m1" || m.SentMessages[0].Text != "🔧 $ go test ./..." {
+		t.Fatalf("expected command forwarded as a reply, got %+v", m.SentMessages)
+	}
+}
+
+func TestFormatVerboseItem(t *testing.T) {
+	exit := 2
+	tests := []struct {
+		item *codex.ThreadItem
+		want string
+	}{
+		{&codex.ThreadItem{Type: "reasoning", Summary: []string{"Check the tests", "Then fix"}}, "💭 Check the tests\nThen fix"},
+		{&codex.ThreadItem{Type: "reasoning"}, ""},
+		{&codex.ThreadItem{Type: "commandExecution", Command: "make", ExitCode: &exit}, "🔧 $ make (exit 2)"},
+		{&codex.ThreadItem{Type: "agentMessage", Text: "hi"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := formatVerboseItem(tt.item); got != tt.want {
+			t.Errorf("formatVerboseItem(%+v) = %q, want %q", tt.item, got, tt.want)
+		}
+	}
+}
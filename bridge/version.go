@@ -0,0 +1,58 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// checkCodexVersion runs `codex --version` before the app-server starts,
+// keeps the result for /version and warns when the CLI is older than
+// codex.MinSupportedVersion, whose protocol errors are otherwise cryptic.
+func (b *Bridge) checkCodexVersion(ctx context.Context) {
+	raw, err := codex.DetectVersion(ctx)
+	if err != nil {
+		fmt.Printf("[Bridge] Could not detect codex version: %v\n", err)
+		return
+	}
+	b.codexVersion = raw
+	fmt.Printf("[Bridge] Codex: %s\n", raw)
+	v, err := codex.ParseVersion(raw)
+	if err != nil {
+		fmt.Printf("[Bridge] Could not parse codex version: %v\n", err)
+		return
+	}
+	if v.Less(codex.MinSupportedVersion) {
+		fmt.Printf("[Bridge] ⚠️ codex %s is older than %s, the oldest version known to work with this bridge; please upgrade the codex CLI\n", v, codex.MinSupportedVersion)
+	}
+}
+
+// formatVersion answers /version.
+func (b *Bridge) formatVersion(chatID string) string {
+	msgs := b.chatMessages(chatID)
+	codexVersion := b.codexVersion
+	if codexVersion == "" {
+		codexVersion = msgs.VersionUnknown
+	}
+	return fmt.Sprintf(msgs.VersionInfo, buildVersion(), codexVersion)
+}
+
+// buildVersion reports the module version, or the VCS revision for a local
+// build.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" && len(s.Value) >= 12 {
+			return s.Value[:12]
+		}
+	}
+	return "(devel)"
+}
@@ -0,0 +1,30 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestVersionCommand(t *testing.T) {
+	b, m := newTestBridge(t)
+
+	b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", Content: "/version"})
+	if got := findReplyText(m, "m1"); !strings.Contains(got, "Codex 版本：未知") {
+		t.Fatalf("unexpected /version reply %q", got)
+	}
+
+	b.codexVersion = "codex-cli 0.98.0"
+	b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m2", Content: "/version"})
+	if got := findReplyText(m, "m2"); !strings.Contains(got, "Codex 版本：codex-cli 0.98.0") || !strings.HasPrefix(got, "Bridge 版本：") {
+		t.Fatalf("unexpected /version reply %q", got)
+	}
+
+	b.setLanguage("c1", "en")
+	b.codexVersion = ""
+	b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m3", Content: "/version"})
+	if got := findReplyText(m, "m3"); !strings.Contains(got, "Codex version: unknown") {
+		t.Fatalf("unexpected English /version reply %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// Supported values for Config.WarmupPolicy.
+const (
+	// WarmupWait holds messages until the starting app-server is up.
+	WarmupWait = "wait"
+	// WarmupReply answers Messages.CodexWarmingUp and drops the message.
+	WarmupReply = "reply"
+)
+
+// warmupPollInterval is how often a held message rechecks whether codex
+// finished starting.
+var warmupPollInterval = 200 * time.Millisecond
+
+// startCodex starts client, marking codex as warming up until Start
+// returns; see codexWarmingUp.
+func (b *Bridge) startCodex(ctx context.Context, client *codex.Client) error {
+	b.codexStarting.Add(1)
+	defer b.codexStarting.Add(-1)
+	return client.Start(ctx)
+}
+
+// codexWarmingUp reports whether an app-server is being started (at
+// startup, /reset, /cd or a crash restart) and the current client isn't
+// running yet.
+func (b *Bridge) codexWarmingUp() bool {
+	return b.codexStarting.Load() > 0 && !b.codexClient.IsRunning()
+}
+
+// awaitWarmup applies Config.WarmupPolicy to a message that arrives while
+// codex is warming up, so it doesn't fail with "codex unavailable" or a
+// failed thread/start. It reports whether the message should go ahead;
+// under WarmupWait it blocks until the start finished, the chat is cleared
+// (gen changes) or the bridge stops. A failed start is left to codexReady.
+func (b *Bridge) awaitWarmup(chatID string, state *ChatState, gen uint64, sendReply func(string) bool) bool {
+	if !b.codexWarmingUp() {
+		return true
+	}
+	if b.config.WarmupPolicy == WarmupReply {
+		fmt.Printf("[Bridge] Codex is starting, asking %s to wait\n", chatID)
+		sendReply(b.chatMessages(chatID).CodexWarmingUp)
+		return false
+	}
+
+	fmt.Printf("[Bridge] Codex is starting, holding message for %s\n", chatID)
+	var ctxDone <-chan struct{}
+	if b.ctx != nil {
+		ctxDone = b.ctx.Done()
+	}
+	ticker := time.NewTicker(warmupPollInterval)
+	defer ticker.Stop()
+	for b.codexWarmingUp() {
+		select {
+		case <-ticker.C:
+		case <-ctxDone:
+			return false
+		}
+		state.mu.Lock()
+		stale := state.Gen != gen
+		state.mu.Unlock()
+		if stale {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// startingCodex is a newTestBridge option for a codex client that is being
+// started but not running yet.
+func startingCodex(policy string) func(*Bridge) {
+	return func(b *Bridge) {
+		b.config.WarmupPolicy = policy
+		b.config.CodexDownPolicy = CodexDownReject
+		b.codexStarting.Add(1)
+	}
+}
+
+// drainQueues waits until the chat workers processed everything enqueued.
+func drainQueues(b *Bridge) {
+	b.closeAllChatQueues()
+	b.wg.Wait()
+}
+
+func TestEnqueueWhileWarmingUp_Reply(t *testing.T) {
+	b, m := newTestBridge(t, startingCodex(WarmupReply))
+
+	b.enqueueMessage(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: "hi"})
+	drainQueues(b)
+
+	if reply := findReplyText(m, "m1"); reply != "启动中，请稍候" {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	if entry, _ := b.sessionStore.GetByChatID("c1"); entry != nil {
+		t.Fatalf("expected no thread to be created, got %+v", entry)
+	}
+}
+
+func TestEnqueueWhileWarmingUp_WaitsForStart(t *testing.T) {
+	useFakeCodex(t)
+	old := warmupPollInterval
+	warmupPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { warmupPollInterval = old })
+
+	b, m := newTestBridge(t, startingCodex(WarmupWait))
+	b.enqueueMessage(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: "m1", MsgType: "text", Content: "hi"})
+
+	// Held, not rejected as "codex unavailable", while codex is starting.
+	time.Sleep(50 * time.Millisecond)
+	if entry, _ := b.sessionStore.GetByChatID("c1"); entry != nil {
+		t.Fatalf("thread created before codex was running: %+v", entry)
+	}
+
+	if err := b.codexClient.Start(context.Background()); err != nil {
+		t.Skipf("fake codex did not start: %v", err)
+	}
+	t.Cleanup(func() { b.codexClient.Stop() })
+	b.codexStarting.Add(-1)
+
+	state := b.getChatState("c1")
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		state.mu.Lock()
+		started := !state.TurnStartedAt.IsZero()
+		state.mu.Unlock()
+		if started {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("held message did not start a turn once codex was running")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	b.handleTurnCompleted(codex.TurnCompletedParams{ThreadID: "thread-1", TurnID: "turn-1", Status: "completed"})
+	drainQueues(b)
+
+	if reply := findReplyText(m, "m1"); reply == defaultMessages.CodexUnavailable || reply == "启动中，请稍候" {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	if entry, _ := b.sessionStore.GetByChatID("c1"); entry == nil || entry.ThreadID != "thread-1" {
+		t.Fatalf("expected the held message to create thread-1, got %+v", entry)
+	}
+}
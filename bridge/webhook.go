@@ -0,0 +1,214 @@
+package bridge
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// WebhookSecretHeader carries the shared secret for webhook requests.
+const WebhookSecretHeader = "X-Webhook-Secret"
+
+const webhookMaxBodyBytes = 1 << 20
+
+// webhookMsgIDPrefix marks the made-up message IDs of webhook prompts, which
+// have no Feishu message to reply or react to.
+const webhookMsgIDPrefix = "webhook_"
+
+// webhookRateLimit caps the requests one source address may make per
+// webhookRateWindow; webhookMaxSources bounds the tracking map.
+const (
+	webhookRateLimit  = 30
+	webhookRateWindow = time.Minute
+	webhookMaxSources = 1024
+)
+
+var webhookSeq int64
+
+// webhookWindow counts one source's requests in the current rate window.
+type webhookWindow struct {
+	start time.Time
+	count int
+}
+
+type webhookRequest struct {
+	ChatID string `json:"chat_id"`
+	Prompt string `json:"prompt"`
+}
+
+type webhookResponse struct {
+	MsgID string `json:"msg_id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// webhookHandler accepts {chat_id, prompt} payloads and enqueues them as if
+// they were Feishu text messages, so queueing and recall logic still apply.
+func (b *Bridge) webhookHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeWebhookJSON(w, http.StatusMethodNotAllowed, webhookResponse{Error: "method not allowed"})
+			return
+		}
+		if !b.webhookAllowed(webhookSource(r), time.Now()) {
+			w.Header().Set("Retry-After", fmt.Sprint(int(webhookRateWindow/time.Second)))
+			writeWebhookJSON(w, http.StatusTooManyRequests, webhookResponse{Error: "too many requests"})
+			return
+		}
+
+		secret := b.config.WebhookSecret
+		got := r.Header.Get(WebhookSecretHeader)
+		if secret == "" || subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+			writeWebhookJSON(w, http.StatusUnauthorized, webhookResponse{Error: "unauthorized"})
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, webhookMaxBodyBytes+1))
+		if err != nil {
+			writeWebhookJSON(w, http.StatusBadRequest, webhookResponse{Error: "failed to read body"})
+			return
+		}
+		if len(body) > webhookMaxBodyBytes {
+			writeWebhookJSON(w, http.StatusRequestEntityTooLarge, webhookResponse{Error: "body too large"})
+			return
+		}
+
+		var req webhookRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeWebhookJSON(w, http.StatusBadRequest, webhookResponse{Error: "invalid json"})
+			return
+		}
+		req.ChatID = strings.TrimSpace(req.ChatID)
+		if req.ChatID == "" || strings.TrimSpace(req.Prompt) == "" {
+			writeWebhookJSON(w, http.StatusBadRequest, webhookResponse{Error: "chat_id and prompt are required"})
+			return
+		}
+
+		msg := &feishu.Message{
+			ChatID:  req.ChatID,
+			MsgID:   fmt.Sprintf("%s%d_%d", webhookMsgIDPrefix, time.Now().UnixNano(), atomic.AddInt64(&webhookSeq, 1)),
+			MsgType: "text",
+			Content: req.Prompt,
+		}
+		fmt.Printf("[Bridge] Webhook prompt for %s: %s\n", msg.ChatID, truncate(msg.Content, 50))
+		b.enqueueMessage(msg)
+
+		writeWebhookJSON(w, http.StatusAccepted, webhookResponse{MsgID: msg.MsgID})
+	})
+	b.registerAdminAPI(mux)
+	return mux
+}
+
+// webhookSource identifies the client of r for rate limiting.
+func webhookSource(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// webhookAllowed reports whether source may make another webhook request at
+// now, and if so counts it.
+func (b *Bridge) webhookAllowed(source string, now time.Time) bool {
+	b.webhookUsesMu.Lock()
+	defer b.webhookUsesMu.Unlock()
+	if b.webhookUses == nil {
+		b.webhookUses = make(map[string]*webhookWindow)
+	}
+	win := b.webhookUses[source]
+	if win == nil || now.Sub(win.start) >= webhookRateWindow {
+		if len(b.webhookUses) >= webhookMaxSources {
+			for k, old := range b.webhookUses {
+				if now.Sub(old.start) >= webhookRateWindow {
+					delete(b.webhookUses, k)
+				}
+			}
+		}
+		b.webhookUses[source] = &webhookWindow{start: now, count: 1}
+		return true
+	}
+	if win.count >= webhookRateLimit {
+		return false
+	}
+	win.count++
+	return true
+}
+
+// canReplyTo reports whether msgID is a Feishu message that can be replied
+// or reacted to; webhook prompts only have made-up IDs.
+func canReplyTo(msgID string) bool {
+	return msgID != "" && !strings.HasPrefix(msgID, webhookMsgIDPrefix)
+}
+
+// replyText replies to msgID, or posts text in chatID when msgID can't be
+// replied to or the reply fails.
+func (b *Bridge) replyText(chatID, msgID, text string, replyInThread bool) {
+	if canReplyTo(msgID) && b.feishuClient.ReplyText(msgID, text, replyInThread) == nil {
+		return
+	}
+	_ = b.feishuClient.SendText(chatID, text)
+}
+
+// replyTextWithID is replyText for messages that are edited later.
+func (b *Bridge) replyTextWithID(chatID, msgID, text string, replyInThread bool) (string, error) {
+	if canReplyTo(msgID) {
+		if id, err := b.feishuClient.ReplyTextWithID(msgID, text, replyInThread); err == nil {
+			return id, nil
+		}
+	}
+	return b.feishuClient.SendTextWithID(chatID, text)
+}
+
+func writeWebhookJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (b *Bridge) startWebhookServer() error {
+	if b.config.WebhookSecret == "" {
+		return fmt.Errorf("WEBHOOK_SECRET is required when WEBHOOK_ADDR is set")
+	}
+
+	ln, err := net.Listen("tcp", b.config.WebhookAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", b.config.WebhookAddr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           b.webhookHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	b.webhookServer = srv
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("[Bridge] Webhook server error: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("[Bridge] Webhook listening on %s\n", ln.Addr())
+	return nil
+}
+
+func (b *Bridge) stopWebhookServer() {
+	if b.webhookServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = b.webhookServer.Shutdown(ctx)
+}
@@ -0,0 +1,144 @@
+package bridge
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestWebhook_RejectsMissingSecret(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.WebhookSecret = "s3cret"
+	q := stubChatQueue(b, "c1")
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"chat_id":"c1","prompt":"hi"}`))
+	rec := httptest.NewRecorder()
+	b.webhookHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	if len(q.pending) != 0 {
+		t.Fatalf("unauthenticated request should not be enqueued")
+	}
+}
+
+func TestWebhook_RejectsInvalidPayload(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.WebhookSecret = "s3cret"
+
+	for _, body := range []string{`not json`, `{"chat_id":"c1"}`, `{"prompt":"hi"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set(WebhookSecretHeader, "s3cret")
+		rec := httptest.NewRecorder()
+		b.webhookHandler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %q, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestWebhook_EnqueuesPrompt(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.WebhookSecret = "s3cret"
+	q := stubChatQueue(b, "c1")
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"chat_id":"c1","prompt":"summarize the build"}`))
+	req.Header.Set(WebhookSecretHeader, "s3cret")
+	rec := httptest.NewRecorder()
+	b.webhookHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(q.pending) != 1 {
+		t.Fatalf("expected 1 pending message, got %d", len(q.pending))
+	}
+	msg := q.pending[0]
+	if msg.ChatID != "c1" || msg.Content != "summarize the build" || msg.MsgType != "text" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if !strings.Contains(rec.Body.String(), msg.MsgID) {
+		t.Fatalf("expected response to contain msg id, got %s", rec.Body.String())
+	}
+}
+
+func TestWebhook_RejectsGet(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.WebhookSecret = "s3cret"
+	req := httptest.NewRequest(http.MethodGet, "/webhook", nil)
+	rec := httptest.NewRecorder()
+	b.webhookHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
+
+func TestWebhook_RateLimitedPerSource(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.WebhookSecret = "s3cret"
+	stubChatQueue(b, "c1")
+
+	post := func(remote string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"chat_id":"c1","prompt":"hi"}`))
+		req.Header.Set(WebhookSecretHeader, "s3cret")
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		b.webhookHandler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+	for i := 0; i < webhookRateLimit; i++ {
+		if code := post("10.0.0.1:1234"); code != http.StatusAccepted {
+			t.Fatalf("request %d: expected 202, got %d", i+1, code)
+		}
+	}
+	if code := post("10.0.0.1:5678"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once the limit is reached, got %d", code)
+	}
+	if code := post("10.0.0.2:1234"); code != http.StatusAccepted {
+		t.Fatalf("other sources have their own limit, got %d", code)
+	}
+
+	now := time.Now()
+	if !b.webhookAllowed("10.0.0.1", now.Add(webhookRateWindow)) {
+		t.Fatal("expected the limit to reset after the window")
+	}
+}
+
+func TestWebhookPrompt_KeepsChatType(t *testing.T) {
+	// Codex being down ends the turn right after the chat state is set up.
+	b, _ := newTestBridge(t, codexDownPolicy(CodexDownReject))
+	b.processQueuedMessage("c1", &feishu.Message{ChatID: "c1", ChatType: "group", MsgID: "m1", MsgType: "text", Content: "hi"}, 0)
+	b.processQueuedMessage("c1", &feishu.Message{ChatID: "c1", MsgID: webhookMsgIDPrefix + "1_1", MsgType: "text", Content: "hi"}, 0)
+
+	state := b.getChatState("c1")
+	state.mu.Lock()
+	chatType := state.ChatType
+	state.mu.Unlock()
+	if chatType != "group" {
+		t.Fatalf("expected a webhook prompt to keep the group chat type, got %q", chatType)
+	}
+}
+
+func TestWebhookPrompt_SentWithoutReplyOrReaction(t *testing.T) {
+	b, m := newTestBridge(t)
+	msgID := webhookMsgIDPrefix + "1_1"
+
+	if id := b.addProcessingReaction(msgID); id != "" || len(m.Reactions) != 0 {
+		t.Fatalf("expected no reaction on a webhook prompt, got %q %+v", id, m.Reactions)
+	}
+	b.sendResponse("c1", msgID, "done", false)
+	b.replyText("c1", msgID, "queued", false)
+	if len(m.SentMessages) != 2 {
+		t.Fatalf("expected 2 messages, got %+v", m.SentMessages)
+	}
+	for _, sent := range m.SentMessages {
+		if sent.IsReply || sent.ChatID != "c1" {
+			t.Fatalf("expected a plain message in c1, got %+v", sent)
+		}
+	}
+}
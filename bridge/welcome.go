@@ -0,0 +1,47 @@
+package bridge
+
+import (
+	"fmt"
+	"time"
+)
+
+// welcomeDedupWindow suppresses repeat welcomes for the same chat, e.g. when
+// the bot-added event is redelivered after a reconnect.
+const welcomeDedupWindow = 10 * time.Minute
+
+// handleBotJoined posts a short intro plus the command list to a group the
+// bot was just added to, if Config.WelcomeOnJoin is set.
+func (b *Bridge) handleBotJoined(chatID string) {
+	if !b.config.WelcomeOnJoin || !b.markWelcomed(chatID) {
+		return
+	}
+
+	welcome := b.chatMessages(chatID).Welcome
+	if !b.config.PlainTextOnly {
+		title, content := b.buildHelpPost(b.chatLanguage(chatID))
+		post := append([][]map[string]interface{}{{{"tag": "text", "text": welcome}}}, content...)
+		if err := b.feishuClient.SendRichText(chatID, title, post); err == nil {
+			return
+		}
+	}
+	text := welcome + "\n\n" + b.buildHelpFallbackText(b.chatLanguage(chatID))
+	if err := b.feishuClient.SendText(chatID, text); err != nil {
+		fmt.Printf("[Bridge] Failed to send welcome to %s: %v\n", chatID, err)
+	}
+}
+
+// markWelcomed records a welcome for chatID and reports whether one should
+// be sent, i.e. the chat wasn't welcomed within welcomeDedupWindow.
+func (b *Bridge) markWelcomed(chatID string) bool {
+	b.welcomedMu.Lock()
+	defer b.welcomedMu.Unlock()
+	if b.welcomed == nil {
+		b.welcomed = make(map[string]time.Time)
+	}
+	now := time.Now()
+	if last, ok := b.welcomed[chatID]; ok && now.Sub(last) < welcomeDedupWindow {
+		return false
+	}
+	b.welcomed[chatID] = now
+	return true
+}
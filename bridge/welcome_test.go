@@ -0,0 +1,51 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHandleBotJoined_PostsWelcomeWithHelp(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{feishuClient: m}
+
+	b.handleBotJoined("oc_1")
+	if len(m.SentMessages) != 0 {
+		t.Fatalf("expected no welcome when disabled, got %+v", m.SentMessages)
+	}
+
+	b.config.WelcomeOnJoin = true
+	b.handleBotJoined("oc_1")
+	if len(m.SentMessages) != 1 {
+		t.Fatalf("expected one welcome, got %d", len(m.SentMessages))
+	}
+	sent := m.SentMessages[0]
+	if sent.ChatID != "oc_1" || !sent.IsRich {
+		t.Fatalf("expected rich welcome in oc_1, got %+v", sent)
+	}
+	if got := textFromPostLine(sent.Content[0]); got != DefaultMessages().Welcome {
+		t.Fatalf("expected welcome line first, got %q", got)
+	}
+	if got := textFromPostLine(sent.Content[1]); got != "可用命令：" {
+		t.Fatalf("expected help to follow the welcome, got %q", got)
+	}
+}
+
+func TestHandleBotJoined_SkipsRepeatWithinWindow(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{config: Config{WelcomeOnJoin: true}, feishuClient: m}
+
+	b.handleBotJoined("oc_1")
+	b.handleBotJoined("oc_1") // redelivered after reconnect
+	b.handleBotJoined("oc_2")
+	if len(m.SentMessages) != 2 {
+		t.Fatalf("expected one welcome per chat, got %d", len(m.SentMessages))
+	}
+
+	// Re-adding the bot later welcomes again.
+	b.welcomed["oc_1"] = time.Now().Add(-welcomeDedupWindow - time.Second)
+	b.handleBotJoined("oc_1")
+	if len(m.SentMessages) != 3 {
+		t.Fatalf("expected a fresh welcome after the window, got %d", len(m.SentMessages))
+	}
+}
@@ -0,0 +1,147 @@
+package bridge
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+// Workspace is a named working directory offered by /cd.
+type Workspace struct {
+	Name string
+	Dir  string
+}
+
+// cardActionSwitchDir is the "action" value of the /cd picker's buttons;
+// "workspace" names the Workspace to switch to.
+const cardActionSwitchDir = "cd"
+
+// ParseWorkspaces parses a comma-separated list of name=dir pairs. A bare
+// dir is named after its last element. Names must be unique.
+func ParseWorkspaces(s string) ([]Workspace, error) {
+	var workspaces []Workspace
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, dir, ok := strings.Cut(entry, "=")
+		if !ok {
+			dir = name
+			name = filepath.Base(filepath.Clean(dir))
+		}
+		name, dir = strings.TrimSpace(name), strings.TrimSpace(dir)
+		if name == "" || dir == "" {
+			return nil, fmt.Errorf("invalid workspace %q: want name=dir", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate workspace name %q", name)
+		}
+		seen[name] = true
+		workspaces = append(workspaces, Workspace{Name: name, Dir: dir})
+	}
+	return workspaces, nil
+}
+
+// workspace returns the configured workspace called name.
+func (b *Bridge) workspace(name string) (Workspace, bool) {
+	for _, ws := range b.config.Workspaces {
+		if ws.Name == name {
+			return ws, true
+		}
+	}
+	return Workspace{}, false
+}
+
+// replyWorkspacePicker answers a bare /cd with a card of the configured
+// workspaces, one button each. Without workspaces it replies with the
+// /cd <path> usage; if the card can't be sent, with a text list.
+func (b *Bridge) replyWorkspacePicker(msg *feishu.Message, replyInThread bool) {
+	msgs := b.chatMessages(msg.ChatID)
+	reply := func(text string) {
+		if err := b.feishuClient.ReplyText(msg.MsgID, text, replyInThread); err != nil {
+			_ = b.feishuClient.SendText(msg.ChatID, text)
+		}
+	}
+	usage := fmt.Sprintf(msgs.SwitchDirUsage, b.withPrefix("/cd"))
+	if len(b.config.Workspaces) == 0 {
+		reply(usage)
+		return
+	}
+
+	// In groups where commands need an @-mention, only the member who
+	// mentioned the bot may click; see handleCardAction.
+	requester := ""
+	if msg.ChatType == "group" && b.config.GroupCommandsRequireMention && msg.Sender != nil {
+		requester = msg.Sender.SenderID
+	}
+	var list strings.Builder
+	buttons := make([]feishu.CardButton, 0, len(b.config.Workspaces))
+	for _, ws := range b.config.Workspaces {
+		fmt.Fprintf(&list, "\n- **%s**：%s", ws.Name, ws.Dir)
+		value := map[string]string{"action": cardActionSwitchDir, "workspace": ws.Name}
+		if requester != "" {
+			value["requester"] = requester
+		}
+		buttons = append(buttons, feishu.CardButton{Text: ws.Name, Value: value})
+	}
+	text := fmt.Sprintf(msgs.WorkspacePicker, b.config.WorkingDir) + list.String()
+	card := feishu.ButtonCard(msgs.WorkspacePickerTitle, text, buttons)
+	if err := b.feishuClient.ReplyCard(msg.MsgID, card, replyInThread); err != nil {
+		b.debugf("Failed to send workspace picker: %v", err)
+		reply(text + "\n\n" + usage)
+	}
+}
+
+// handleCardAction dispatches a card button click and returns the toast to
+// show. A picker click is gated like /cd: it shares its cooldown, and a
+// picker posted for an @-mention only answers the member who sent it.
+func (b *Bridge) handleCardAction(action *feishu.CardAction) string {
+	if action.ChatID == "" {
+		return ""
+	}
+	switch action.Value["action"] {
+	case cardActionSwitchDir:
+		msgs := b.chatMessages(action.ChatID)
+		if requester := action.Value["requester"]; requester != "" && requester != action.OperatorID {
+			return msgs.WorkspaceNotRequester
+		}
+		ws, ok := b.workspace(action.Value["workspace"])
+		if !ok {
+			return msgs.WorkspaceUnknown
+		}
+		if !b.commandAllowed(action.ChatID, CommandSwitchDir, time.Now()) {
+			return msgs.CommandTooFrequent
+		}
+		// Clicks arriving while a switch runs are dropped rather than
+		// queued behind codexMu.
+		if !b.switchingDir.CompareAndSwap(false, true) {
+			return msgs.WorkspaceSwitchBusy
+		}
+		// Feishu waits only a few seconds for the callback; restarting
+		// codex takes longer, so the result is posted to the chat.
+		go func() {
+			defer b.switchingDir.Store(false)
+			b.switchWorkspace(action.ChatID, ws)
+		}()
+		return fmt.Sprintf(msgs.WorkspaceSwitching, ws.Name)
+	}
+	return ""
+}
+
+// switchWorkspace switches to ws for a picker click and tells the chat.
+func (b *Bridge) switchWorkspace(chatID string, ws Workspace) {
+	msgs := b.chatMessages(chatID)
+	var text string
+	if err := b.switchWorkingDir(chatID, ws.Dir); err != nil {
+		text = fmt.Sprintf(msgs.SwitchDirFailed, err)
+	} else {
+		text = fmt.Sprintf(msgs.SwitchDirDone, b.config.WorkingDir)
+	}
+	if err := b.feishuClient.SendText(chatID, text); err != nil {
+		fmt.Printf("[Bridge] Failed to send workspace switch result: %v\n", err)
+	}
+}
@@ -0,0 +1,126 @@
+package bridge
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anthropics/feishu-codex-bridge/feishu"
+)
+
+func TestParseWorkspaces(t *testing.T) {
+	got, err := ParseWorkspaces(" api=/srv/api , /srv/web/ ,")
+	if err != nil {
+		t.Fatalf("ParseWorkspaces: %v", err)
+	}
+	want := []Workspace{{Name: "api", Dir: "/srv/api"}, {Name: "web", Dir: "/srv/web/"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	for _, in := range []string{"api=", "=/srv/api", "api=/srv/a,api=/srv/b"} {
+		if _, err := ParseWorkspaces(in); err == nil {
+			t.Errorf("ParseWorkspaces(%q): expected an error", in)
+		}
+	}
+}
+
+func TestBareCd(t *testing.T) {
+	m := &MockFeishuClient{}
+	b := &Bridge{
+		config:       Config{WorkingDir: "/srv/api"},
+		feishuClient: m,
+		chatQueues:   make(map[string]*chatQueue),
+		chatStates:   make(map[string]*ChatState),
+	}
+	cd := func(msgID string) MockSentMessage {
+		b.handleFeishuMessageV2(&feishu.Message{ChatID: "c1", ChatType: "p2p", MsgID: msgID, MsgType: "text", Content: "/cd"})
+		for _, sm := range m.SentMessages {
+			if sm.IsReply && sm.MsgID == msgID {
+				return sm
+			}
+		}
+		t.Fatalf("no reply to %s", msgID)
+		return MockSentMessage{}
+	}
+
+	// Without workspaces /cd needs a path.
+	if got := cd("m1"); got.Card != nil || got.Text != "⚠️ 用法：/cd <绝对路径>" {
+		t.Errorf("expected the usage, got %+v", got)
+	}
+
+	b.config.Workspaces = []Workspace{{Name: "api", Dir: "/srv/api"}, {Name: "web", Dir: "/srv/web"}}
+	got := cd("m2")
+	if got.Card == nil {
+		t.Fatalf("expected a picker card, got %+v", got)
+	}
+	elements := got.Card["elements"].([]map[string]interface{})
+	buttons := elements[len(elements)-1]["actions"].([]map[string]interface{})
+	if len(buttons) != 2 {
+		t.Fatalf("expected a button per workspace, got %+v", buttons)
+	}
+	if value := buttons[1]["value"].(map[string]string); value["action"] != cardActionSwitchDir || value["workspace"] != "web" {
+		t.Errorf("unexpected button value %+v", value)
+	}
+}
+
+func TestHandleCardAction_UnknownWorkspace(t *testing.T) {
+	b := &Bridge{
+		config:     Config{Workspaces: []Workspace{{Name: "api", Dir: "/srv/api"}}},
+		chatStates: make(map[string]*ChatState),
+	}
+	action := &feishu.CardAction{ChatID: "c1", Value: map[string]string{"action": cardActionSwitchDir, "workspace": "gone"}}
+	if got := b.handleCardAction(action); got != "该工作区已不在配置中" {
+		t.Errorf("expected an unknown workspace to be refused, got %q", got)
+	}
+	if got := b.handleCardAction(&feishu.CardAction{ChatID: "c1"}); got != "" {
+		t.Errorf("expected other card actions to be ignored, got %q", got)
+	}
+}
+
+func TestSwitchWorkspace(t *testing.T) {
+	useFakeCodex(t)
+	b, m := newTestBridge(t)
+	newDir := filepath.Join(b.config.WorkingDir, "web")
+	if err := os.MkdirAll(newDir, 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	b.switchWorkspace("c1", Workspace{Name: "web", Dir: newDir})
+
+	if len(m.SentMessages) != 1 || m.SentMessages[0].Text != "✅ 已切换到新的工作目录："+newDir {
+		t.Fatalf("expected the switch to be reported, got %+v", m.SentMessages)
+	}
+	if b.config.WorkingDir != newDir {
+		t.Errorf("expected working dir %s, got %s", newDir, b.config.WorkingDir)
+	}
+}
+
+func TestHandleCardAction_GatedLikeCd(t *testing.T) {
+	b, _ := newTestBridge(t)
+	b.config.Workspaces = []Workspace{{Name: "api", Dir: b.config.WorkingDir}}
+	b.config.CommandCooldown = time.Minute
+	click := func(operator, requester string) string {
+		value := map[string]string{"action": cardActionSwitchDir, "workspace": "api"}
+		if requester != "" {
+			value["requester"] = requester
+		}
+		return b.handleCardAction(&feishu.CardAction{ChatID: "c1", OperatorID: operator, Value: value})
+	}
+
+	if got := click("ou_other", "ou_asker"); got != defaultMessages.WorkspaceNotRequester {
+		t.Errorf("expected a click by another member to be refused, got %q", got)
+	}
+
+	b.switchingDir.Store(true)
+	if got := click("ou_asker", "ou_asker"); got != defaultMessages.WorkspaceSwitchBusy {
+		t.Errorf("expected a click during a switch to be dropped, got %q", got)
+	}
+	b.switchingDir.Store(false)
+
+	// The busy click above already used the /cd cooldown.
+	if got := click("ou_asker", "ou_asker"); got != defaultMessages.CommandTooFrequent {
+		t.Errorf("expected a second click to hit the /cd cooldown, got %q", got)
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,29 +22,96 @@ type Event struct {
 	Params json.RawMessage
 }
 
+// ApprovalRequest is a server → client request asking the client to approve
+// a command execution or file change.
+type ApprovalRequest struct {
+	ID     int64
+	Method string
+	Params json.RawMessage
+}
+
+// ApprovalHandler is called from the read loop for each approval request. It
+// returns true if it will respond (later, via RespondToApproval); returning
+// false leaves the request to the default auto-accept. It must not block.
+type ApprovalHandler func(req ApprovalRequest) bool
+
 // Client is the ACP client for communicating with Codex app-server
 type Client struct {
 	cmd    *exec.Cmd
 	stdin  io.WriteCloser
-	stdout *bufio.Scanner
+	stdout *bufio.Reader
 	stderr io.ReadCloser
 
+	// writeMu serializes writes so concurrent requests/approvals don't interleave.
+	writeMu sync.Mutex
+
 	requestID int64
 	pending   map[int64]chan *Response
 	pendingMu sync.Mutex
 
-	events      chan Event
-	initialized bool
-	running     bool
+	events          chan Event
+	approvalHandler ApprovalHandler
+	readDone        chan struct{} // closed when readLoop exits
+	unhealthy       chan struct{} // closed when a keep-alive ping fails
+	unhealthyOnce   sync.Once
+	initialized     atomic.Bool
+	running         atomic.Bool // read by IsRunning while Start runs
+
+	workingDir     string
+	model          string
+	debug          bool
+	disableNetwork bool
 
-	workingDir string
-	model      string
+	// maxLineBytes bounds a single JSON line read from codex stdout.
+	maxLineBytes int
+
+	// initTimeout bounds the initialize handshake in Start.
+	initTimeout time.Duration
+
+	// keepAliveInterval is the gap between keep-alive pings; 0 disables them.
+	keepAliveInterval time.Duration
+
+	// configOverrides are extra "key=value" -c arguments; see
+	// SetConfigOverrides.
+	configOverrides []string
 
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 }
 
+// ErrServerExited is returned for requests still waiting when the app-server
+// output stream ends (process exit or crash).
+var ErrServerExited = errors.New("codex app-server exited")
+
+// ErrNotSent is returned when a request could not be written to the
+// app-server, e.g. because it already exited; codex never saw it.
+var ErrNotSent = errors.New("codex request not sent")
+
+// ErrResponseTooLarge is returned for a request whose response line exceeded
+// the reader's line limit and was dropped.
+var ErrResponseTooLarge = errors.New("codex response too large")
+
+// oversizedHeadBytes is how much of a dropped line is kept to tell which
+// request, if any, it answered.
+const oversizedHeadBytes = 256
+
+var (
+	oversizedMethodRe = regexp.MustCompile(`^\s*\{[^{]*?"method"\s*:`)
+	oversizedIDRe     = regexp.MustCompile(`^\s*\{[^{]*?"id"\s*:\s*(\d+)`)
+)
+
+// defaultMaxLineBytes is large enough for big file-change diffs delivered in a
+// single JSON line, while still bounding memory if codex misbehaves.
+const defaultMaxLineBytes = 64 * 1024 * 1024
+
+// DefaultInitTimeout bounds the initialize handshake, so a codex that hangs
+// on startup is reported quickly instead of after requestTimeout.
+const DefaultInitTimeout = 30 * time.Second
+
+// requestTimeout bounds every other request; turns can take a while.
+const requestTimeout = 5 * time.Minute
+
 // NewClient creates a new ACP client
 func NewClient(workingDir, model string) *Client {
 	return &Client{
@@ -48,21 +119,22 @@ func NewClient(workingDir, model string) *Client {
 		model:      model,
 		pending:    make(map[int64]chan *Response),
 		events:     make(chan Event, 100),
+		readDone:   make(chan struct{}),
+		unhealthy:  make(chan struct{}),
+
+		maxLineBytes: defaultMaxLineBytes,
+		initTimeout:  DefaultInitTimeout,
 	}
 }
 
 // Start spawns the Codex app-server process and initializes the connection
 func (c *Client) Start(ctx context.Context) error {
 	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.readDone = make(chan struct{})
+	c.unhealthy = make(chan struct{})
+	c.unhealthyOnce = sync.Once{}
 
-	// Build command arguments
-	args := []string{"app-server"}
-	if c.model != "" {
-		args = append(args, "-c", fmt.Sprintf("model=\"%s\"", c.model))
-	}
-	// Enable full-auto mode for sandbox permissions
-	args = append(args, "-c", `sandbox_permissions=["disk-full-read-access","disk-full-write-access","network-full-access"]`)
-
+	args := c.startArgs()
 	fmt.Printf("[Codex] Starting: codex %v\n", args)
 
 	c.cmd = exec.CommandContext(c.ctx, "codex", args...)
@@ -78,8 +150,7 @@ func (c *Client) Start(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
-	c.stdout = bufio.NewScanner(stdout)
-	c.stdout.Buffer(make([]byte, 1024*1024), 1024*1024) // 1MB buffer for large responses
+	c.stdout = bufio.NewReaderSize(stdout, 64*1024)
 
 	c.stderr, err = c.cmd.StderrPipe()
 	if err != nil {
@@ -90,7 +161,7 @@ func (c *Client) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to start codex: %w", err)
 	}
 
-	c.running = true
+	c.running.Store(true)
 
 	// Start read loops
 	c.wg.Add(2)
@@ -104,16 +175,18 @@ func (c *Client) Start(ctx context.Context) error {
 	}
 
 	fmt.Println("[Codex] Initialized successfully")
+	if c.keepAliveInterval > 0 {
+		c.wg.Add(1)
+		go c.keepAliveLoop()
+	}
 	return nil
 }
 
 // Stop gracefully shuts down the client
 func (c *Client) Stop() error {
-	if !c.running {
+	if !c.running.CompareAndSwap(true, false) {
 		return nil
 	}
-
-	c.running = false
 	c.cancel()
 
 	// Close stdin to signal EOF
@@ -133,21 +206,121 @@ func (c *Client) Stop() error {
 		c.cmd.Process.Kill()
 	}
 
-	close(c.events)
+	// Close events only after the read loops have exited, so a notification
+	// arriving during shutdown can't be sent on a closed channel.
 	c.wg.Wait()
+	close(c.events)
 
 	fmt.Println("[Codex] Stopped")
 	return nil
 }
 
+// SetDebug enables tracing of every JSON line sent to and received from the
+// app-server.
+func (c *Client) SetDebug(enabled bool) {
+	c.debug = enabled
+}
+
+// startArgs builds the codex app-server command line.
+func (c *Client) startArgs() []string {
+	args := []string{"app-server"}
+	if c.model != "" {
+		args = append(args, "-c", fmt.Sprintf("model=\"%s\"", c.model))
+	}
+	// Enable full-auto mode for sandbox permissions
+	perms := `"disk-full-read-access","disk-full-write-access"`
+	if !c.disableNetwork {
+		perms += `,"network-full-access"`
+	}
+	args = append(args, "-c", "sandbox_permissions=["+perms+"]")
+	for _, kv := range c.configOverrides {
+		args = append(args, "-c", kv)
+	}
+	return args
+}
+
+// SetDisableNetwork stops the sandbox from granting network access, which
+// it does by default. It takes effect on the next Start.
+func (c *Client) SetDisableNetwork(disable bool) {
+	c.disableNetwork = disable
+}
+
+// SetKeepAlive makes the client ping the app-server every interval, so a
+// server that died or hung is noticed before the next turn; see Unhealthy.
+// 0 disables it (the default). It takes effect on the next Start.
+func (c *Client) SetKeepAlive(interval time.Duration) {
+	c.keepAliveInterval = interval
+}
+
+// SetInitTimeout sets how long Start waits for the initialize handshake.
+// Zero or negative means DefaultInitTimeout.
+func (c *Client) SetInitTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultInitTimeout
+	}
+	c.initTimeout = d
+}
+
+func (c *Client) debugf(format string, args ...any) {
+	if !c.debug {
+		return
+	}
+	fmt.Printf("[Codex][Debug] "+format+"\n", args...)
+}
+
+// SetApprovalHandler installs a handler for approval requests. It must be
+// called before Start.
+func (c *Client) SetApprovalHandler(handler ApprovalHandler) {
+	c.approvalHandler = handler
+}
+
 // Events returns the channel for receiving server notifications
 func (c *Client) Events() <-chan Event {
 	return c.events
 }
 
-// IsRunning returns true if the client is running
+// IsRunning returns true if the client is running and the app-server
+// hasn't exited or stopped answering keep-alive pings.
 func (c *Client) IsRunning() bool {
-	return c.running && c.initialized
+	if !c.running.Load() || !c.initialized.Load() {
+		return false
+	}
+	select {
+	case <-c.readDone:
+		return false
+	case <-c.unhealthy:
+		return false
+	default:
+		return true
+	}
+}
+
+// Exited returns a channel that is closed when the app-server's output ends,
+// whether through Stop or a crash. It must be called after Start.
+func (c *Client) Exited() <-chan struct{} {
+	return c.readDone
+}
+
+// Unhealthy returns a channel that is closed when a keep-alive ping fails
+// (see SetKeepAlive). It must be called after Start.
+func (c *Client) Unhealthy() <-chan struct{} {
+	return c.unhealthy
+}
+
+// Crashed reports whether the app-server exited, or stopped answering
+// keep-alive pings, without Stop being called.
+func (c *Client) Crashed() bool {
+	if c.ctx == nil || c.ctx.Err() != nil {
+		return false
+	}
+	select {
+	case <-c.readDone:
+		return true
+	case <-c.unhealthy:
+		return true
+	default:
+		return false
+	}
 }
 
 // ============ High-level API ============
@@ -192,8 +365,8 @@ func (c *Client) ThreadResume(ctx context.Context, threadID string) (*Thread, er
 	return &result.Thread, nil
 }
 
-// TurnStart starts a new turn with a user prompt
-func (c *Client) TurnStart(ctx context.Context, threadID, prompt string, images []string) (string, error) {
+// TurnStart starts a new turn with a user prompt. opts may be nil.
+func (c *Client) TurnStart(ctx context.Context, threadID, prompt string, images []string, opts *TurnOptions) (string, error) {
 	// Build input array
 	input := []UserInput{
 		{Type: "text", Text: prompt},
@@ -207,6 +380,9 @@ func (c *Client) TurnStart(ctx context.Context, threadID, prompt string, images
 		ThreadID: threadID,
 		Input:    input,
 	}
+	if opts != nil {
+		params.Effort = opts.Effort
+	}
 
 	resp, err := c.sendRequest("turn/start", params)
 	if err != nil {
@@ -250,7 +426,7 @@ func (c *Client) initialize() error {
 		},
 	}
 
-	resp, err := c.sendRequest("initialize", params)
+	resp, err := c.sendRequestWithTimeout("initialize", params, c.initTimeout)
 	if err != nil {
 		return err
 	}
@@ -260,17 +436,21 @@ func (c *Client) initialize() error {
 		return fmt.Errorf("failed to parse initialize result: %w", err)
 	}
 
-	fmt.Printf("[Codex] Server: %s\n", result.UserAgent)
+	c.debugf("Server: %s", result.UserAgent)
 
 	// Send initialized notification
 	c.sendNotification("initialized", nil)
 
-	c.initialized = true
+	c.initialized.Store(true)
 	return nil
 }
 
 func (c *Client) sendRequest(method string, params interface{}) (*Response, error) {
-	if !c.running {
+	return c.sendRequestWithTimeout(method, params, requestTimeout)
+}
+
+func (c *Client) sendRequestWithTimeout(method string, params interface{}, timeout time.Duration) (*Response, error) {
+	if !c.running.Load() {
 		return nil, fmt.Errorf("client not running")
 	}
 
@@ -292,21 +472,41 @@ func (c *Client) sendRequest(method string, params interface{}) (*Response, erro
 		c.pendingMu.Lock()
 		delete(c.pending, id)
 		c.pendingMu.Unlock()
-		return nil, err
+		return nil, fmt.Errorf("request %s: %w: %v", method, ErrNotSent, err)
 	}
 
 	// Wait for response with timeout
 	select {
 	case resp := <-respChan:
+		if resp.dropped != nil {
+			return nil, fmt.Errorf("request %s: %w", method, resp.dropped)
+		}
 		if resp.Error != nil {
-			return nil, fmt.Errorf("RPC error %d: %s", resp.Error.Code, resp.Error.Message)
+			return nil, resp.Error
 		}
 		return resp, nil
-	case <-time.After(5 * time.Minute):
+	case <-c.readDone:
+		// Prefer a response that raced with the reader shutting down.
+		select {
+		case resp := <-respChan:
+			if resp.dropped != nil {
+				return nil, fmt.Errorf("request %s: %w", method, resp.dropped)
+			}
+			if resp.Error != nil {
+				return nil, resp.Error
+			}
+			return resp, nil
+		default:
+		}
 		c.pendingMu.Lock()
 		delete(c.pending, id)
 		c.pendingMu.Unlock()
-		return nil, fmt.Errorf("request %s timed out", method)
+		return nil, fmt.Errorf("request %s failed: %w", method, ErrServerExited)
+	case <-time.After(timeout):
+		c.pendingMu.Lock()
+		delete(c.pending, id)
+		c.pendingMu.Unlock()
+		return nil, fmt.Errorf("request %s timed out after %s", method, timeout)
 	case <-c.ctx.Done():
 		return nil, c.ctx.Err()
 	}
@@ -329,57 +529,140 @@ func (c *Client) sendRaw(v interface{}) error {
 		return fmt.Errorf("failed to marshal: %w", err)
 	}
 
+	if c.stdin == nil {
+		return fmt.Errorf("stdin not available")
+	}
+	c.debugf(">>> %s", data)
 	line := append(data, '\n')
+	c.writeMu.Lock()
 	_, err = c.stdin.Write(line)
+	c.writeMu.Unlock()
 	return err
 }
 
 func (c *Client) readLoop() {
 	defer c.wg.Done()
-
-	for c.stdout.Scan() {
-		line := c.stdout.Text()
-		if line == "" {
-			continue
+	defer close(c.readDone)
+
+	for {
+		line, tooLong, err := readLine(c.stdout, c.maxLineBytes)
+		if tooLong {
+			fmt.Printf("[Codex] Dropped line larger than %d bytes\n", c.maxLineBytes)
+			c.failDroppedResponse(line)
+		} else if err == nil || err == io.EOF {
+			// On EOF, line holds any trailing data without a newline; parse it
+			// so a final response isn't silently discarded.
+			if s := strings.TrimRight(string(line), "\r\n"); s != "" {
+				c.handleLine(s)
+			}
+		}
+		if err != nil {
+			if err != io.EOF && c.running.Load() {
+				fmt.Printf("[Codex] Read error: %v\n", err)
+			}
+			return
 		}
+	}
+}
 
-		c.handleLine(line)
+// failDroppedResponse fails the request answered by a line that was too
+// long to parse, going by the id at the start of the line (head). When head
+// doesn't tell whether the line was a response at all, every pending request
+// is failed rather than left waiting for its timeout. Notifications are
+// simply lost.
+func (c *Client) failDroppedResponse(head []byte) {
+	if oversizedMethodRe.Match(head) {
+		return
+	}
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	if m := oversizedIDRe.FindSubmatch(head); m != nil {
+		id, err := strconv.ParseInt(string(m[1]), 10, 64)
+		if err == nil {
+			if ch, ok := c.pending[id]; ok {
+				ch <- &Response{ID: id, dropped: ErrResponseTooLarge}
+				delete(c.pending, id)
+			}
+			return
+		}
+	}
+	for id, ch := range c.pending {
+		ch <- &Response{ID: id, dropped: ErrResponseTooLarge}
+		delete(c.pending, id)
 	}
+}
 
-	if err := c.stdout.Err(); err != nil && c.running {
-		fmt.Printf("[Codex] Read error: %v\n", err)
+// readLine reads one '\n'-terminated line of any length. If the line exceeds
+// max bytes, it is consumed and discarded and tooLong is reported instead,
+// with line holding the first oversizedHeadBytes of it.
+func readLine(r *bufio.Reader, max int) (line []byte, tooLong bool, err error) {
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !tooLong {
+			if max > 0 && len(line)+len(chunk) > max {
+				tooLong = true
+				head := append(line, chunk...)
+				if len(head) > oversizedHeadBytes {
+					head = head[:oversizedHeadBytes]
+				}
+				line = append([]byte(nil), head...)
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return line, tooLong, err
 	}
 }
 
 func (c *Client) handleLine(line string) {
-	// Try to parse as Response (has "id" and "result" or "error")
-	var resp Response
-	if err := json.Unmarshal([]byte(line), &resp); err == nil && resp.ID != 0 {
-		c.pendingMu.Lock()
-		if ch, ok := c.pending[resp.ID]; ok {
-			ch <- &resp
-			delete(c.pending, resp.ID)
-		}
-		c.pendingMu.Unlock()
+	c.debugf("<<< %s", line)
+	var msg struct {
+		ID     int64           `json:"id,omitempty"`
+		Method string          `json:"method,omitempty"`
+		Params json.RawMessage `json:"params,omitempty"`
+		Result json.RawMessage `json:"result,omitempty"`
+		Error  *RPCError       `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(line), &msg); err != nil {
 		return
 	}
 
-	// Otherwise it's a Notification (may or may not have "id" for approval requests)
-	var notif Notification
-	if err := json.Unmarshal([]byte(line), &notif); err == nil && notif.Method != "" {
-		// Check if it's an approval request (has ID)
-		if notif.ID != 0 {
+	// Messages with a method are server notifications, or server requests
+	// (approvals) when they also carry an ID.
+	if msg.Method != "" {
+		if msg.ID != 0 {
+			req := ApprovalRequest{ID: msg.ID, Method: msg.Method, Params: msg.Params}
+			if c.approvalHandler != nil && c.approvalHandler(req) {
+				return
+			}
 			// Auto-approve all requests
-			c.RespondToApproval(notif.ID, "accept")
+			if err := c.RespondToApproval(msg.ID, "accept"); err != nil {
+				fmt.Printf("[Codex] Failed to respond to %s: %v\n", msg.Method, err)
+			}
 			return
 		}
 
 		// Regular notification - send to events channel
 		select {
-		case c.events <- Event{Method: notif.Method, Params: notif.Params}:
+		case c.events <- Event{Method: msg.Method, Params: msg.Params}:
 		default:
-			fmt.Printf("[Codex] Event channel full, dropping: %s\n", notif.Method)
+			fmt.Printf("[Codex] Event channel full, dropping: %s\n", msg.Method)
 		}
+		return
+	}
+
+	// Otherwise it's a Response to one of our requests
+	if msg.ID != 0 {
+		resp := &Response{ID: msg.ID, Result: msg.Result, Error: msg.Error}
+		c.pendingMu.Lock()
+		if ch, ok := c.pending[resp.ID]; ok {
+			ch <- resp
+			delete(c.pending, resp.ID)
+		}
+		c.pendingMu.Unlock()
 	}
 }
 
@@ -1,9 +1,16 @@
 package codex
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -30,12 +37,12 @@ func TestIsRunning(t *testing.T) {
 		t.Error("new client should not be running")
 	}
 
-	client.running = true
+	client.running.Store(true)
 	if client.IsRunning() {
 		t.Error("should not be running without initialization")
 	}
 
-	client.initialized = true
+	client.initialized.Store(true)
 	if !client.IsRunning() {
 		t.Error("should be running after both flags set")
 	}
@@ -88,7 +95,7 @@ func TestSendRequestNotRunning(t *testing.T) {
 
 func TestHandleLineResponse(t *testing.T) {
 	client := NewClient("/home/test", "")
-	client.running = true
+	client.running.Store(true)
 
 	// Create a pending response channel
 	respChan := make(chan *Response, 1)
@@ -114,7 +121,7 @@ func TestHandleLineResponse(t *testing.T) {
 
 func TestHandleLineNotification(t *testing.T) {
 	client := NewClient("/home/test", "")
-	client.running = true
+	client.running.Store(true)
 
 	// Simulate receiving a notification
 	line := `{"method": "turn/completed", "params": {"threadId": "test"}}`
@@ -133,7 +140,7 @@ func TestHandleLineNotification(t *testing.T) {
 
 func TestHandleLineApprovalRequest(t *testing.T) {
 	client := NewClient("/home/test", "")
-	client.running = true
+	client.running.Store(true)
 
 	// We can't fully test auto-approval without a running stdin,
 	// but we can test that approval requests with ID are recognized
@@ -154,7 +161,7 @@ func TestHandleLineApprovalRequest(t *testing.T) {
 
 func TestHandleLineInvalidJSON(t *testing.T) {
 	client := NewClient("/home/test", "")
-	client.running = true
+	client.running.Store(true)
 
 	// Invalid JSON should not panic
 	client.handleLine("invalid json")
@@ -209,6 +216,136 @@ func TestThreadResume(t *testing.T) {
 	}
 }
 
+func TestThreadResume_ParsesTurnHistory(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+	client.stdin = nopWriteCloser{}
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	defer client.cancel()
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	client.stdout = bufio.NewReader(pr)
+	client.wg.Add(1)
+	go client.readLoop()
+
+	type result struct {
+		thread *Thread
+		err    error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		thread, err := client.ThreadResume(context.Background(), "thr_1")
+		resCh <- result{thread, err}
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		client.pendingMu.Lock()
+		_, ok := client.pending[1]
+		client.pendingMu.Unlock()
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("thread/resume request was not sent")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	resp := `{"id":1,"result":{"thread":{"id":"thr_1","preview":"fix the build","modelProvider":"openai","createdAt":1770137340,"updatedAt":1770137400,"cwd":"/srv/proj","cliVersion":"0.94.0","turns":[` +
+		`{"id":"turn_1","status":"completed","error":null,"items":[` +
+		`{"type":"userMessage","id":"i1","content":[{"type":"text","text":"fix the build"}]},` +
+		`{"type":"reasoning","id":"i2","summary":["Check go vet output"],"content":[]},` +
+		`{"type":"commandExecution","id":"i3","command":"go vet ./...","cwd":"/srv/proj","status":"completed","aggregatedOutput":"ok\n","exitCode":0,"durationMs":812},` +
+		`{"type":"fileChange","id":"i4","status":"completed","changes":[{"path":"main.go","kind":{"type":"update","move_path":null},"diff":"@@ -1 +1 @@"}]},` +
+		`{"type":"agentMessage","id":"i5","text":"Fixed."}]},` +
+		`{"id":"turn_2","status":"failed","error":{"message":"model is overloaded"},"items":[` +
+		`{"type":"userMessage","id":"i6","content":[{"type":"text","text":"now add tests"}]},` +
+		`{"type":"webSearch","id":"i7","query":"go table tests"},` +
+		`{"type":"mcpToolCall","id":"i8","server":"docs","tool":"lookup","status":"failed"}]}` +
+		`]}}}` + "\n"
+	if _, err := pw.Write([]byte(resp)); err != nil {
+		t.Fatal(err)
+	}
+
+	var res result
+	select {
+	case res = <-resCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("thread/resume did not return")
+	}
+	if res.err != nil {
+		t.Fatalf("ThreadResume failed: %v", res.err)
+	}
+
+	thread := res.thread
+	if thread.ID != "thr_1" || thread.Cwd != "/srv/proj" || len(thread.Turns) != 2 {
+		t.Fatalf("unexpected thread: %+v", thread)
+	}
+	first, second := thread.Turns[0], thread.Turns[1]
+	if len(first.Items) != 5 || len(second.Items) != 3 {
+		t.Fatalf("unexpected item counts: %d, %d", len(first.Items), len(second.Items))
+	}
+	if got := first.Items[0].UserText(); got != "fix the build" {
+		t.Errorf("user text = %q", got)
+	}
+	cmd := first.Items[2]
+	if cmd.Command != "go vet ./..." || cmd.AggregatedOutput != "ok\n" || cmd.ExitCode == nil || *cmd.ExitCode != 0 {
+		t.Errorf("unexpected command item: %+v", cmd)
+	}
+	if ch := first.Items[3].Changes; len(ch) != 1 || ch[0].Path != "main.go" {
+		t.Errorf("unexpected file changes: %+v", ch)
+	}
+	if first.Items[4].Text != "Fixed." {
+		t.Errorf("agent text = %q", first.Items[4].Text)
+	}
+	if second.Status != "failed" || second.Error == nil || second.Error.Message != "model is overloaded" {
+		t.Errorf("unexpected failed turn: %+v", second)
+	}
+	if second.Items[1].Query != "go table tests" || second.Items[2].Tool != "lookup" {
+		t.Errorf("unexpected tool items: %+v", second.Items[1:])
+	}
+}
+
+func TestSendRequestReturnsTypedRPCError(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+	client.stdin = nopWriteCloser{}
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	defer client.cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.sendRequest("turn/start", nil)
+		errCh <- err
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		client.pendingMu.Lock()
+		_, ok := client.pending[1]
+		client.pendingMu.Unlock()
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("request was not sent")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	client.handleLine(`{"id":1,"error":{"code":-32001,"message":"401 Unauthorized"}}`)
+
+	err := <-errCh
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected *RPCError, got %T: %v", err, err)
+	}
+	if rpcErr.Code != -32001 || rpcErr.Message != "401 Unauthorized" {
+		t.Fatalf("unexpected RPC error: %+v", rpcErr)
+	}
+}
+
 func TestTurnInterrupt(t *testing.T) {
 	client := NewClient("/home/test", "")
 
@@ -241,7 +378,7 @@ func TestEventStruct(t *testing.T) {
 
 func TestHandleLineEmptyLine(t *testing.T) {
 	client := NewClient("/home/test", "")
-	client.running = true
+	client.running.Store(true)
 
 	// Empty line should be handled gracefully
 	client.handleLine("")
@@ -249,7 +386,7 @@ func TestHandleLineEmptyLine(t *testing.T) {
 
 func TestHandleLineResponseNotPending(t *testing.T) {
 	client := NewClient("/home/test", "")
-	client.running = true
+	client.running.Store(true)
 
 	// Response for non-pending request (should be ignored)
 	line := `{"id": 999, "result": {"test": true}}`
@@ -258,7 +395,7 @@ func TestHandleLineResponseNotPending(t *testing.T) {
 
 func TestHandleLineNotificationDropped(t *testing.T) {
 	client := NewClient("/home/test", "")
-	client.running = true
+	client.running.Store(true)
 
 	// Fill the events channel
 	for i := 0; i < 100; i++ {
@@ -272,3 +409,469 @@ func TestHandleLineNotificationDropped(t *testing.T) {
 	line := `{"method": "test/notification", "params": {}}`
 	client.handleLine(line)
 }
+
+func TestReadLoopHandlesLineLargerThanOneMB(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+
+	respChan := make(chan *Response, 1)
+	client.pending[1] = respChan
+
+	big := strings.Repeat("x", 2*1024*1024)
+	input := `{"method":"item/agentMessage/delta","params":{"delta":"` + big + `"}}` + "\n" +
+		`{"id": 1, "result": {"ok": true}}` + "\n"
+	client.stdout = bufio.NewReaderSize(strings.NewReader(input), 4096)
+
+	client.wg.Add(1)
+	client.readLoop()
+
+	select {
+	case ev := <-client.events:
+		if ev.Method != "item/agentMessage/delta" || len(ev.Params) < len(big) {
+			t.Fatalf("unexpected event: %s (%d bytes)", ev.Method, len(ev.Params))
+		}
+	default:
+		t.Fatal("oversized notification was not delivered")
+	}
+	select {
+	case resp := <-respChan:
+		if resp.ID != 1 {
+			t.Fatalf("ID mismatch: got %d", resp.ID)
+		}
+	default:
+		t.Fatal("response after oversized line was not delivered")
+	}
+}
+
+func TestReadLoopDropsLineAboveLimit(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+	client.maxLineBytes = 1024
+
+	respChan := make(chan *Response, 1)
+	client.pending[1] = respChan
+
+	input := `{"method":"too/big","params":{"delta":"` + strings.Repeat("y", 8192) + `"}}` + "\n" +
+		`{"id": 1, "result": {"ok": true}}` + "\n"
+	client.stdout = bufio.NewReaderSize(strings.NewReader(input), 256)
+
+	client.wg.Add(1)
+	client.readLoop()
+
+	select {
+	case ev := <-client.events:
+		t.Fatalf("expected oversized line to be dropped, got %s", ev.Method)
+	default:
+	}
+	select {
+	case <-respChan:
+	default:
+		t.Fatal("reader did not recover after dropping oversized line")
+	}
+}
+
+func TestReadLoopFailsRequestWithDroppedResponse(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+	client.maxLineBytes = 1024
+
+	first := make(chan *Response, 1)
+	second := make(chan *Response, 1)
+	client.pending[1] = first
+	client.pending[2] = second
+
+	input := `{"id": 1, "result": {"data":"` + strings.Repeat("z", 8192) + `"}}` + "\n"
+	client.stdout = bufio.NewReaderSize(strings.NewReader(input), 256)
+
+	client.wg.Add(1)
+	client.readLoop()
+
+	select {
+	case resp := <-first:
+		if !errors.Is(resp.dropped, ErrResponseTooLarge) {
+			t.Fatalf("expected ErrResponseTooLarge, got %+v", resp)
+		}
+	default:
+		t.Fatal("request with a dropped response was left waiting")
+	}
+	select {
+	case resp := <-second:
+		t.Fatalf("other requests should keep waiting, got %+v", resp)
+	default:
+	}
+
+	// Without an id at the start of the line, every waiting request fails.
+	client.readDone = make(chan struct{})
+	input = `{"result": {"data":"` + strings.Repeat("z", 8192) + `"}, "id": 2}` + "\n"
+	client.stdout = bufio.NewReaderSize(strings.NewReader(input), 256)
+	client.wg.Add(1)
+	client.readLoop()
+	select {
+	case resp := <-second:
+		if !errors.Is(resp.dropped, ErrResponseTooLarge) {
+			t.Fatalf("expected ErrResponseTooLarge, got %+v", resp)
+		}
+	default:
+		t.Fatal("pending request was left waiting after an unidentified dropped line")
+	}
+}
+
+func TestReadLoopParsesFinalLineWithoutNewline(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+
+	respChan := make(chan *Response, 1)
+	client.pending[7] = respChan
+
+	client.stdout = bufio.NewReader(strings.NewReader(`{"id": 7, "result": {"final": true}}`))
+
+	client.wg.Add(1)
+	client.readLoop()
+
+	select {
+	case resp := <-respChan:
+		if resp.ID != 7 {
+			t.Fatalf("ID mismatch: got %d", resp.ID)
+		}
+	default:
+		t.Fatal("final line without newline was discarded")
+	}
+}
+
+type nopWriteCloser struct{}
+
+func (nopWriteCloser) Write(p []byte) (int, error) { return len(p), nil }
+func (nopWriteCloser) Close() error                { return nil }
+
+func TestSendRequestFailsWhenReaderExits(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+	client.stdin = nopWriteCloser{}
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	defer client.cancel()
+
+	pr, pw := io.Pipe()
+	client.stdout = bufio.NewReader(pr)
+	client.wg.Add(1)
+	go client.readLoop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.sendRequest("turn/start", nil)
+		errCh <- err
+	}()
+
+	// Simulate the process exiting mid-request.
+	pw.Close()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrServerExited) {
+			t.Fatalf("expected ErrServerExited, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("pending request hung after reader exit")
+	}
+
+	client.pendingMu.Lock()
+	n := len(client.pending)
+	client.pendingMu.Unlock()
+	if n != 0 {
+		t.Fatalf("expected pending map to be cleaned up, got %d", n)
+	}
+}
+
+type bufWriteCloser struct{ strings.Builder }
+
+func (*bufWriteCloser) Close() error { return nil }
+
+func TestHandleLineApprovalRequest_AutoAccepts(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+	out := &bufWriteCloser{}
+	client.stdin = out
+
+	// A pending request with the same ID must not swallow the server request.
+	respChan := make(chan *Response, 1)
+	client.pending[100] = respChan
+
+	client.handleLine(`{"id": 100, "method": "item/commandExecution/requestApproval", "params": {"command": "ls"}}`)
+
+	select {
+	case <-respChan:
+		t.Fatal("server request was treated as a response")
+	default:
+	}
+	if !strings.Contains(out.String(), `"id":100`) || !strings.Contains(out.String(), `"decision":"accept"`) {
+		t.Fatalf("expected auto-accept response, got %q", out.String())
+	}
+}
+
+func TestHandleLineApprovalRequest_Handler(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+	out := &bufWriteCloser{}
+	client.stdin = out
+
+	var got ApprovalRequest
+	client.SetApprovalHandler(func(req ApprovalRequest) bool {
+		got = req
+		return true
+	})
+
+	client.handleLine(`{"id": 101, "method": "item/fileChange/requestApproval", "params": {"threadId": "t1"}}`)
+
+	if got.ID != 101 || got.Method != MethodFileChangeRequestApproval {
+		t.Fatalf("handler not called with request: %+v", got)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no auto response when handler takes over, got %q", out.String())
+	}
+}
+
+func TestStopWhileNotificationsArrive(t *testing.T) {
+	// Repeatedly stop clients whose server is still emitting notifications;
+	// Stop must not close the events channel while readLoop can send on it.
+	for i := 0; i < 20; i++ {
+		client := NewClient(t.TempDir(), "")
+		client.ctx, client.cancel = context.WithCancel(context.Background())
+		client.readDone = make(chan struct{})
+		client.cmd = exec.CommandContext(client.ctx, "sh", "-c",
+			`while :; do echo '{"method":"item/agentMessage/delta","params":{}}'; done`)
+		stdout, err := client.cmd.StdoutPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.stdout = bufio.NewReader(stdout)
+		client.stdin = nopWriteCloser{}
+		if err := client.cmd.Start(); err != nil {
+			t.Skipf("sh not available: %v", err)
+		}
+		client.running.Store(true)
+		client.wg.Add(1)
+		go client.readLoop()
+
+		drained := make(chan struct{})
+		go func() {
+			defer close(drained)
+			for range client.Events() {
+			}
+		}()
+
+		time.Sleep(time.Millisecond)
+		if err := client.Stop(); err != nil {
+			t.Fatalf("Stop failed: %v", err)
+		}
+		select {
+		case <-drained:
+		case <-time.After(2 * time.Second):
+			t.Fatal("events channel was not closed after Stop")
+		}
+	}
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestDebugTracesRPCTraffic(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.running.Store(true)
+	client.stdin = &bufWriteCloser{}
+
+	quiet := captureStdout(t, func() {
+		_ = client.sendRaw(Request{ID: 3, Method: "thread/start"})
+		client.handleLine(`{"id": 3, "result": {}}`)
+	})
+	if strings.Contains(quiet, "[Codex][Debug]") {
+		t.Fatalf("expected no trace without debug, got %q", quiet)
+	}
+
+	client.SetDebug(true)
+	out := captureStdout(t, func() {
+		_ = client.sendRaw(Request{ID: 4, Method: "thread/start"})
+		client.handleLine(`{"id": 4, "result": {}}`)
+	})
+	if !strings.Contains(out, `>>> {"id":4,"method":"thread/start"}`) {
+		t.Errorf("missing outgoing trace: %q", out)
+	}
+	if !strings.Contains(out, `<<< {"id": 4, "result": {}}`) {
+		t.Errorf("missing incoming trace: %q", out)
+	}
+}
+
+func TestInitializeTimesOutWhenServerHangs(t *testing.T) {
+	client := NewClient("/home/test", "")
+	client.SetInitTimeout(50 * time.Millisecond)
+	client.running.Store(true)
+	client.stdin = nopWriteCloser{}
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	defer client.cancel()
+
+	// The server never answers initialize.
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	client.stdout = bufio.NewReader(pr)
+	client.wg.Add(1)
+	go client.readLoop()
+
+	start := time.Now()
+	err := client.initialize()
+	if err == nil || !strings.Contains(err.Error(), "request initialize timed out after 50ms") {
+		t.Fatalf("expected init timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("initialize took %v, expected to fail fast", elapsed)
+	}
+	if client.initialized.Load() {
+		t.Fatal("expected client not to be marked initialized")
+	}
+}
+
+func TestStartArgs_Network(t *testing.T) {
+	client := NewClient("/home/test", "gpt-5.2-codex")
+	args := strings.Join(client.startArgs(), " ")
+	if !strings.Contains(args, `model="gpt-5.2-codex"`) {
+		t.Errorf("expected the model in args, got %s", args)
+	}
+	if !strings.Contains(args, `"network-full-access"`) {
+		t.Errorf("expected network access by default, got %s", args)
+	}
+
+	client.SetDisableNetwork(true)
+	args = strings.Join(client.startArgs(), " ")
+	if strings.Contains(args, "network") {
+		t.Errorf("expected no network access, got %s", args)
+	}
+	if !strings.Contains(args, `sandbox_permissions=["disk-full-read-access","disk-full-write-access"]`) {
+		t.Errorf("expected disk permissions to remain, got %s", args)
+	}
+}
+
+func TestCrashed(t *testing.T) {
+	start := func(script string) *Client {
+		client := NewClient(t.TempDir(), "")
+		client.ctx, client.cancel = context.WithCancel(context.Background())
+		client.readDone = make(chan struct{})
+		client.cmd = exec.CommandContext(client.ctx, "sh", "-c", script)
+		stdout, err := client.cmd.StdoutPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.stdout = bufio.NewReader(stdout)
+		client.stdin = nopWriteCloser{}
+		if err := client.cmd.Start(); err != nil {
+			t.Skipf("sh not available: %v", err)
+		}
+		client.running.Store(true)
+		client.wg.Add(1)
+		go client.readLoop()
+		return client
+	}
+
+	// The server exits on its own.
+	client := start("exit 1")
+	select {
+	case <-client.Exited():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Exited not closed after the server exited")
+	}
+	if !client.Crashed() {
+		t.Error("expected Crashed after an unexpected exit")
+	}
+	_ = client.Stop()
+
+	// Stop is not a crash.
+	client = start("sleep 10")
+	if client.Crashed() {
+		t.Error("running server reported as crashed")
+	}
+	_ = client.Stop()
+	<-client.Exited()
+	if client.Crashed() {
+		t.Error("expected no crash after Stop")
+	}
+}
+
+func TestKeepAliveFailureMarksUnhealthy(t *testing.T) {
+	client := NewClient(t.TempDir(), "")
+	client.SetKeepAlive(20 * time.Millisecond)
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	client.readDone = make(chan struct{})
+	// The server reads requests but never answers them.
+	client.cmd = exec.CommandContext(client.ctx, "sh", "-c", "cat >/dev/null")
+	stdin, err := client.cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := client.cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.stdin = stdin
+	client.stdout = bufio.NewReader(stdout)
+	if err := client.cmd.Start(); err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+	client.running.Store(true)
+	client.initialized.Store(true)
+	client.wg.Add(2)
+	go client.readLoop()
+	go client.keepAliveLoop()
+	defer client.Stop()
+
+	select {
+	case <-client.Unhealthy():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Unhealthy not closed after an unanswered keep-alive")
+	}
+	if client.IsRunning() {
+		t.Error("expected IsRunning false after a failed keep-alive")
+	}
+	if !client.Crashed() {
+		t.Error("expected a failed keep-alive to count as a crash so the bridge restarts codex")
+	}
+}
+
+func TestStartArgs_ConfigOverrides(t *testing.T) {
+	client := NewClient("/home/test", "gpt-5.2-codex")
+	client.SetConfigOverrides([]string{"model_reasoning_summary=detailed", `tools.web_search=true`})
+	args := client.startArgs()
+
+	n := len(args)
+	want := []string{"-c", "model_reasoning_summary=detailed", "-c", "tools.web_search=true"}
+	if n < len(want) || strings.Join(args[n-len(want):], " ") != strings.Join(want, " ") {
+		t.Errorf("expected the overrides after the bridge's own args, got %q", args)
+	}
+}
+
+func TestParseConfigOverride(t *testing.T) {
+	for in, want := range map[string]string{
+		"model_reasoning_effort=high":          "model_reasoning_effort=high",
+		" tools.web_search = true ":            "tools.web_search=true",
+		`shell_environment_policy.inherit=all`: "shell_environment_policy.inherit=all",
+		`sandbox_permissions=["a","b"]`:        `sandbox_permissions=["a","b"]`,
+	} {
+		got, err := ParseConfigOverride(in)
+		if err != nil || got != want {
+			t.Errorf("ParseConfigOverride(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "model", "=high", "model=", "--dangerously-bypass=1", "a..b=1", "a b=1", "model=x\ny", "model=x\x00"} {
+		if got, err := ParseConfigOverride(in); err == nil {
+			t.Errorf("ParseConfigOverride(%q) = %q, expected an error", in, got)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package codex
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // CodexClient defines the interface for Codex operations
 type CodexClient interface {
@@ -8,11 +11,19 @@ type CodexClient interface {
 	Stop() error
 	Events() <-chan Event
 	IsRunning() bool
+	Exited() <-chan struct{}
+	Crashed() bool
+	Unhealthy() <-chan struct{}
 	ThreadStart(ctx context.Context, params *ThreadStartParams) (string, error)
 	ThreadResume(ctx context.Context, threadID string) (*Thread, error)
-	TurnStart(ctx context.Context, threadID, prompt string, images []string) (string, error)
+	TurnStart(ctx context.Context, threadID, prompt string, images []string, opts *TurnOptions) (string, error)
 	TurnInterrupt(ctx context.Context, threadID string) error
 	RespondToApproval(requestID int64, decision string) error
+	SetApprovalHandler(handler ApprovalHandler)
+	SetDebug(enabled bool)
+	SetInitTimeout(d time.Duration)
+	SetDisableNetwork(disable bool)
+	SetKeepAlive(interval time.Duration)
 }
 
 // Ensure Client implements CodexClient
@@ -0,0 +1,55 @@
+package codex
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// keepAliveMethod is the request sent as a keep-alive ping. The bridge
+// doesn't need its result: any response, including a JSON-RPC error for an
+// unknown method, shows the app-server is still reading and answering.
+const keepAliveMethod = "bridge/ping"
+
+// maxKeepAliveTimeout bounds how long a ping may go unanswered.
+const maxKeepAliveTimeout = 30 * time.Second
+
+// keepAliveLoop pings the app-server every keepAliveInterval until the
+// client stops or the server exits. The first failed ping closes
+// c.unhealthy and ends the loop.
+func (c *Client) keepAliveLoop() {
+	defer c.wg.Done()
+	timeout := c.keepAliveInterval
+	if timeout > maxKeepAliveTimeout {
+		timeout = maxKeepAliveTimeout
+	}
+	ticker := time.NewTicker(c.keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-c.readDone:
+			return
+		case <-ticker.C:
+		}
+		if err := c.ping(timeout); err != nil {
+			if c.ctx.Err() != nil {
+				return
+			}
+			fmt.Printf("[Codex] Keep-alive failed: %v\n", err)
+			c.unhealthyOnce.Do(func() { close(c.unhealthy) })
+			return
+		}
+	}
+}
+
+// ping sends one keep-alive request. An RPC error response counts as alive.
+func (c *Client) ping(timeout time.Duration) error {
+	_, err := c.sendRequestWithTimeout(keepAliveMethod, nil, timeout)
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package codex
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseConfigOverride validates one "key=value" codex config override (as
+// passed to `codex -c`) and returns it normalized, with the spaces around
+// the key and value trimmed. The key must be a dotted path of letters,
+// digits, '_' and '-' that doesn't start with '-', so an override can never
+// be taken for another flag; the value may not contain control characters.
+// The value is parsed by codex as TOML, falling back to a plain string.
+func ParseConfigOverride(kv string) (string, error) {
+	key, value, ok := strings.Cut(kv, "=")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !ok || key == "" || value == "" {
+		return "", fmt.Errorf("invalid codex config override %q: want key=value", kv)
+	}
+	if !validConfigKey(key) {
+		return "", fmt.Errorf("invalid codex config key %q", key)
+	}
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("codex config override %s contains control characters", key)
+	}
+	return key + "=" + value, nil
+}
+
+func validConfigKey(key string) bool {
+	for _, part := range strings.Split(key, ".") {
+		if part == "" || part[0] == '-' {
+			return false
+		}
+		for _, r := range part {
+			if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SetConfigOverrides sets extra `-c key=value` overrides for the
+// app-server, each already checked with ParseConfigOverride. They follow
+// the client's own model and sandbox overrides, so they win over them. It
+// takes effect on the next Start.
+func (c *Client) SetConfigOverrides(overrides []string) {
+	c.configOverrides = overrides
+}
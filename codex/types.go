@@ -1,6 +1,10 @@
 package codex
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 // ============ JSON-RPC Base Types ============
 // Note: Codex ACP doesn't include "jsonrpc":"2.0" header
@@ -17,6 +21,10 @@ type Response struct {
 	ID     int64           `json:"id,omitempty"`
 	Result json.RawMessage `json:"result,omitempty"`
 	Error  *RPCError       `json:"error,omitempty"`
+
+	// dropped is set instead of Result when the reader had to discard the
+	// response line.
+	dropped error
 }
 
 // Notification is a JSON-RPC notification (no response expected)
@@ -26,13 +34,18 @@ type Notification struct {
 	Params json.RawMessage `json:"params,omitempty"`
 }
 
-// RPCError represents a JSON-RPC error
+// RPCError represents a JSON-RPC error. Requests that fail with one return
+// it as the error, so callers can inspect it with errors.As.
 type RPCError struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
 
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
 // ============ Initialize ============
 
 type ClientInfo struct {
@@ -84,14 +97,19 @@ type ThreadItem struct {
 	// agentMessage
 	Text string `json:"text,omitempty"`
 
+	// userMessage ([]UserInput) and reasoning ([]string)
+	Content json.RawMessage `json:"content,omitempty"`
 	// reasoning
-	Content string `json:"content,omitempty"`
-	Summary string `json:"summary,omitempty"`
+	Summary []string `json:"summary,omitempty"`
 
 	// commandExecution
 	Command string          `json:"command,omitempty"`
 	Status  ExecutionStatus `json:"status,omitempty"`
 	Output  string          `json:"output,omitempty"`
+	// AggregatedOutput and ExitCode are set on completed commands in
+	// thread/resume history.
+	AggregatedOutput string `json:"aggregatedOutput,omitempty"`
+	ExitCode         *int   `json:"exitCode,omitempty"`
 
 	// fileChange
 	Changes []FileChange `json:"changes,omitempty"`
@@ -107,6 +125,21 @@ type ThreadItem struct {
 	Path string `json:"path,omitempty"`
 }
 
+// UserText joins the text inputs of a userMessage item.
+func (it ThreadItem) UserText() string {
+	var inputs []UserInput
+	if err := json.Unmarshal(it.Content, &inputs); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, in := range inputs {
+		if in.Type == "text" && in.Text != "" {
+			parts = append(parts, in.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
 type ExecutionStatus string
 
 const (
@@ -153,6 +186,13 @@ type UserInput struct {
 type TurnStartParams struct {
 	ThreadID string      `json:"threadId"`
 	Input    []UserInput `json:"input"`
+	// Effort overrides the reasoning effort for this and later turns.
+	Effort string `json:"effort,omitempty"`
+}
+
+// TurnOptions are optional per-turn overrides for TurnStart.
+type TurnOptions struct {
+	Effort string // low|medium|high; empty keeps the thread's setting
 }
 
 type TurnInterruptParams struct {
@@ -191,9 +231,27 @@ type TurnStartedParams struct {
 }
 
 type TurnCompletedParams struct {
-	ThreadID string `json:"threadId"`
-	TurnID   string `json:"turnId"`
-	Status   string `json:"status"` // completed|interrupted|failed
+	ThreadID string     `json:"threadId"`
+	TurnID   string     `json:"turnId"`
+	Status   string     `json:"status"` // completed|interrupted|failed
+	Error    *TurnError `json:"error,omitempty"`
+	Turn     *Turn      `json:"turn,omitempty"`
+}
+
+// ErrorMessage returns the reason a turn failed, taken from the top-level
+// error or the embedded turn, or "" if the server didn't send one.
+func (p TurnCompletedParams) ErrorMessage() string {
+	turnErr := p.Error
+	if turnErr == nil && p.Turn != nil {
+		turnErr = p.Turn.Error
+	}
+	if turnErr == nil {
+		return ""
+	}
+	if turnErr.Message != "" {
+		return turnErr.Message
+	}
+	return turnErr.Type
 }
 
 type ItemStartedParams struct {
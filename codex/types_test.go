@@ -2,6 +2,7 @@ package codex
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -74,6 +75,46 @@ func TestTurnStartParamsSerialization(t *testing.T) {
 	}
 }
 
+func TestTurnStartParamsSerialization_Effort(t *testing.T) {
+	data, err := json.Marshal(TurnStartParams{ThreadID: "t1"})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if strings.Contains(string(data), "effort") {
+		t.Errorf("expected effort to be omitted when unset: %s", data)
+	}
+
+	data, err = json.Marshal(TurnStartParams{ThreadID: "t1", Effort: "high"})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"effort":"high"`) {
+		t.Errorf("expected effort in params: %s", data)
+	}
+}
+
+func TestThreadStartParamsSerialization(t *testing.T) {
+	data, err := json.Marshal(ThreadStartParams{Model: "glm-4", ModelProviderID: "zhipu"})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	var result map[string]interface{}
+	json.Unmarshal(data, &result)
+
+	if result["model_provider_id"] != "zhipu" {
+		t.Errorf("model_provider_id mismatch: got %v", result["model_provider_id"])
+	}
+	if result["model"] != "glm-4" {
+		t.Errorf("model mismatch: got %v", result["model"])
+	}
+
+	data, _ = json.Marshal(ThreadStartParams{})
+	if string(data) != "{}" {
+		t.Errorf("expected empty params to omit fields, got %s", data)
+	}
+}
+
 func TestThreadStartResultDeserialization(t *testing.T) {
 	// Real response format from Codex app-server
 	jsonData := `{
@@ -264,6 +305,49 @@ func TestTurnCompletedParams(t *testing.T) {
 	}
 }
 
+func TestTurnCompletedParams_Failed(t *testing.T) {
+	jsonStr := `{
+		"threadId": "thread-1",
+		"turnId": "turn-1",
+		"status": "failed",
+		"turn": {
+			"id": "turn-1",
+			"status": "failed",
+			"items": [],
+			"error": {"type": "overloaded", "message": "model is overloaded"}
+		}
+	}`
+
+	var params TurnCompletedParams
+	if err := json.Unmarshal([]byte(jsonStr), &params); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+
+	if params.Turn == nil || params.Turn.Error == nil {
+		t.Fatalf("Turn error not parsed: %+v", params)
+	}
+	if params.Turn.Error.Type != "overloaded" {
+		t.Errorf("Error type mismatch: got %v", params.Turn.Error.Type)
+	}
+	if got := params.ErrorMessage(); got != "model is overloaded" {
+		t.Errorf("ErrorMessage mismatch: got %q", got)
+	}
+}
+
+func TestTurnCompletedParams_ErrorMessage(t *testing.T) {
+	var params TurnCompletedParams
+	if err := json.Unmarshal([]byte(`{"threadId":"t","status":"failed","error":{"type":"rate_limited"}}`), &params); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if got := params.ErrorMessage(); got != "rate_limited" {
+		t.Errorf("expected error type as fallback, got %q", got)
+	}
+
+	if got := (TurnCompletedParams{Status: "failed"}).ErrorMessage(); got != "" {
+		t.Errorf("expected empty message without error, got %q", got)
+	}
+}
+
 func TestExecutionStatus(t *testing.T) {
 	if StatusPending != "pending" {
 		t.Error("StatusPending mismatch")
@@ -379,6 +463,42 @@ func TestThread(t *testing.T) {
 	}
 }
 
+func TestThreadResumeResult_PopulatedTurns(t *testing.T) {
+	jsonData := `{
+		"thread": {
+			"id": "thread-1",
+			"turns": [{
+				"id": "turn-1",
+				"status": "completed",
+				"items": [
+					{"type": "userMessage", "id": "i1", "content": [{"type": "text", "text": "list files"}, {"type": "localImage", "path": "/tmp/a.png"}]},
+					{"type": "reasoning", "id": "i2", "summary": ["Looking at the tree"], "content": ["raw thoughts"]},
+					{"type": "commandExecution", "id": "i3", "command": "ls", "status": "completed"},
+					{"type": "agentMessage", "id": "i4", "text": "Here they are"}
+				]
+			}]
+		}
+	}`
+
+	var result ThreadResumeResult
+	if err := json.Unmarshal([]byte(jsonData), &result); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if len(result.Thread.Turns) != 1 || len(result.Thread.Turns[0].Items) != 4 {
+		t.Fatalf("unexpected turns: %+v", result.Thread.Turns)
+	}
+	items := result.Thread.Turns[0].Items
+	if got := items[0].UserText(); got != "list files" {
+		t.Errorf("UserText = %q, want %q", got, "list files")
+	}
+	if len(items[1].Summary) != 1 || items[1].Summary[0] != "Looking at the tree" {
+		t.Errorf("Summary mismatch: %v", items[1].Summary)
+	}
+	if items[1].UserText() != "" {
+		t.Error("reasoning item should have no user text")
+	}
+}
+
 func TestInitializeParams(t *testing.T) {
 	params := InitializeParams{
 		ClientInfo: ClientInfo{
@@ -646,4 +766,7 @@ func TestRPCError(t *testing.T) {
 	if rpcErr.Message != "Invalid request" {
 		t.Error("Message mismatch")
 	}
+	if got := rpcErr.Error(); got != "RPC error -32600: Invalid request" {
+		t.Errorf("Error() = %q", got)
+	}
 }
@@ -0,0 +1,68 @@
+package codex
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is a codex CLI release number.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is an older release than o.
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	return v.Patch < o.Patch
+}
+
+// MinSupportedVersion is the oldest codex CLI whose app-server speaks the
+// thread/turn protocol this client uses (no jsonrpc header, thread/start,
+// turn/start, item/* notifications).
+var MinSupportedVersion = Version{Major: 0, Minor: 46, Patch: 0}
+
+var versionRe = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion extracts the release number from `codex --version` output,
+// e.g. "codex-cli 0.98.0", "codex 0.46.0-alpha.3" or "v1.2".
+func ParseVersion(s string) (Version, error) {
+	m := versionRe.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("no version number in %q", strings.TrimSpace(s))
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+// versionTimeout bounds `codex --version`.
+const versionTimeout = 10 * time.Second
+
+// DetectVersion runs `codex --version` and returns its trimmed output.
+func DetectVersion(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "codex", "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("codex --version: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
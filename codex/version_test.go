@@ -0,0 +1,39 @@
+package codex
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"codex-cli 0.98.0", Version{0, 98, 0}},
+		{"codex-cli 0.98.0\n", Version{0, 98, 0}},
+		{"codex 0.46.0-alpha.3", Version{0, 46, 0}},
+		{"v1.2", Version{1, 2, 0}},
+		{"codex-cli 2.10.31 (abc1234)", Version{2, 10, 31}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if err != nil {
+			t.Errorf("ParseVersion(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := ParseVersion("codex-cli dev"); err == nil {
+		t.Error("expected an error without a version number")
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	if !(Version{0, 45, 9}).Less(MinSupportedVersion) {
+		t.Error("0.45.9 should be older than the minimum")
+	}
+	if (Version{0, 46, 0}).Less(MinSupportedVersion) || (Version{1, 0, 0}).Less(Version{0, 99, 9}) {
+		t.Error("unexpected ordering")
+	}
+}
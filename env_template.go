@@ -1,6 +1,32 @@
 package main
 
-import _ "embed"
+import (
+	_ "embed"
+	"strings"
+)
 
 //go:embed .env.example
 var envExample string
+
+// renderEnvTemplate fills in empty "KEY=" lines of the env template with the
+// given values, so the generated default config has concrete paths for this
+// machine. Values are double-quoted and escaped so the result stays valid
+// dotenv even when paths contain spaces, quotes or '$'.
+func renderEnvTemplate(tmpl string, values map[string]string) string {
+	lines := strings.Split(tmpl, "\n")
+	for i, line := range lines {
+		key, rest, ok := strings.Cut(line, "=")
+		if !ok || rest != "" || strings.HasPrefix(key, "#") {
+			continue
+		}
+		if v, ok := values[key]; ok && v != "" {
+			lines[i] = key + "=" + quoteEnvValue(v)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func quoteEnvValue(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+	return `"` + r.Replace(v) + `"`
+}
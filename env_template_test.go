@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joho/godotenv"
+)
+
+func TestRenderEnvTemplate(t *testing.T) {
+	tmpl := "# SESSION_DB_PATH=\nSESSION_DB_PATH=\nWORKING_DIR=\nCODEX_MODEL=gpt\n"
+	dbPath := `/home/a b/$x "q"/sessions.db`
+
+	out := renderEnvTemplate(tmpl, map[string]string{
+		"SESSION_DB_PATH": dbPath,
+		"CODEX_MODEL":     "ignored",
+	})
+
+	if !strings.HasPrefix(out, "# SESSION_DB_PATH=\n") {
+		t.Errorf("comment line should be untouched: %q", out)
+	}
+	if !strings.Contains(out, "\nWORKING_DIR=\n") || !strings.Contains(out, "\nCODEX_MODEL=gpt\n") {
+		t.Errorf("other lines should be untouched: %q", out)
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(out), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := godotenv.Read(path)
+	if err != nil {
+		t.Fatalf("rendered template is not valid dotenv: %v", err)
+	}
+	if parsed["SESSION_DB_PATH"] != dbPath {
+		t.Errorf("SESSION_DB_PATH = %q, want %q", parsed["SESSION_DB_PATH"], dbPath)
+	}
+	if parsed["CODEX_MODEL"] != "gpt" {
+		t.Errorf("CODEX_MODEL = %q, want %q", parsed["CODEX_MODEL"], "gpt")
+	}
+}
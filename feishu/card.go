@@ -0,0 +1,125 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher/callback"
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// CardButton is a button on an interactive card. Value is sent back in the
+// CardAction when it is clicked.
+type CardButton struct {
+	Text  string
+	Value map[string]string
+}
+
+// ButtonCard builds an interactive card with a title, a markdown text and a
+// row of buttons.
+func ButtonCard(title, text string, buttons []CardButton) map[string]interface{} {
+	actions := make([]map[string]interface{}, 0, len(buttons))
+	for _, btn := range buttons {
+		actions = append(actions, map[string]interface{}{
+			"tag":   "button",
+			"type":  "default",
+			"text":  map[string]interface{}{"tag": "plain_text", "content": btn.Text},
+			"value": btn.Value,
+		})
+	}
+	elements := []map[string]interface{}{}
+	if text != "" {
+		elements = append(elements, map[string]interface{}{
+			"tag":  "div",
+			"text": map[string]interface{}{"tag": "lark_md", "content": text},
+		})
+	}
+	elements = append(elements, map[string]interface{}{"tag": "action", "actions": actions})
+	return map[string]interface{}{
+		"config":   map[string]interface{}{"wide_screen_mode": true},
+		"header":   map[string]interface{}{"title": map[string]interface{}{"tag": "plain_text", "content": title}},
+		"elements": elements,
+	}
+}
+
+// CardAction contains info about a button clicked on an interactive card.
+type CardAction struct {
+	ChatID     string
+	MsgID      string // the card message
+	OperatorID string // open_id of the user who clicked
+	Value      map[string]string
+}
+
+// CardActionHandler is the callback for card button clicks. A non-empty
+// return value is shown to the user as a toast.
+type CardActionHandler func(action *CardAction) string
+
+// OnCardAction sets the handler for card button clicks. The app must
+// subscribe to the card.action.trigger callback.
+func (c *Client) OnCardAction(handler CardActionHandler) {
+	c.onCardAction = handler
+}
+
+func (c *Client) handleCardAction(event *callback.CardActionTriggerEvent) *callback.CardActionTriggerResponse {
+	if event == nil || event.Event == nil || event.Event.Action == nil {
+		return nil
+	}
+	data := event.Event
+	action := &CardAction{Value: make(map[string]string)}
+	if data.Context != nil {
+		action.ChatID = data.Context.OpenChatID
+		action.MsgID = data.Context.OpenMessageID
+	}
+	if data.Operator != nil {
+		action.OperatorID = data.Operator.OpenID
+	}
+	for k, v := range data.Action.Value {
+		if s, ok := v.(string); ok {
+			action.Value[k] = s
+		}
+	}
+
+	if c.debug {
+		fmt.Printf("[Feishu][Debug] Card action %v on %s by %s\n", action.Value, action.MsgID, action.OperatorID)
+	}
+
+	if c.onCardAction == nil {
+		return nil
+	}
+	toast := c.onCardAction(action)
+	if toast == "" {
+		return nil
+	}
+	return &callback.CardActionTriggerResponse{Toast: &callback.Toast{Type: "info", Content: toast}}
+}
+
+// ReplyCard replies to a message with an interactive card, e.g. one built
+// by ButtonCard.
+func (c *Client) ReplyCard(messageID string, card map[string]interface{}, replyInThread bool) error {
+	contentJSON, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("marshal card: %w", err)
+	}
+
+	req := larkim.NewReplyMessageReqBuilder().
+		MessageId(messageID).
+		Body(larkim.NewReplyMessageReqBodyBuilder().
+			MsgType(larkim.MsgTypeInteractive).
+			Content(string(contentJSON)).
+			ReplyInThread(replyInThread).
+			Build()).
+		Build()
+
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	resp, err := c.larkCli.Im.Message.Reply(ctx, req)
+	if err != nil {
+		return fmt.Errorf("reply card failed: %w", err)
+	}
+	if !resp.Success() {
+		return fmt.Errorf("reply card error: %s", resp.Msg)
+	}
+
+	c.debugf("Card replied to %s", messageID)
+	return nil
+}
@@ -0,0 +1,56 @@
+package feishu
+
+import (
+	"testing"
+
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher/callback"
+)
+
+func TestButtonCard(t *testing.T) {
+	card := ButtonCard("切换工作目录", "当前：/srv/api", []CardButton{
+		{Text: "api", Value: map[string]string{"action": "cd", "workspace": "api"}},
+		{Text: "web", Value: map[string]string{"action": "cd", "workspace": "web"}},
+	})
+
+	elements := card["elements"].([]map[string]interface{})
+	if len(elements) != 2 || elements[0]["tag"] != "div" || elements[1]["tag"] != "action" {
+		t.Fatalf("expected a text and an action element, got %+v", elements)
+	}
+	actions := elements[1]["actions"].([]map[string]interface{})
+	if len(actions) != 2 || actions[1]["value"].(map[string]string)["workspace"] != "web" {
+		t.Fatalf("expected one button per workspace, got %+v", actions)
+	}
+	title := card["header"].(map[string]interface{})["title"].(map[string]interface{})
+	if title["content"] != "切换工作目录" {
+		t.Errorf("unexpected title %+v", title)
+	}
+}
+
+func TestHandleCardAction(t *testing.T) {
+	c := &Client{}
+	var got *CardAction
+	c.OnCardAction(func(action *CardAction) string {
+		got = action
+		return "正在切换到 api…"
+	})
+
+	resp := c.handleCardAction(&callback.CardActionTriggerEvent{Event: &callback.CardActionTriggerRequest{
+		Operator: &callback.Operator{OpenID: "ou_1"},
+		Action:   &callback.CallBackAction{Value: map[string]interface{}{"action": "cd", "workspace": "api", "n": 1.0}},
+		Context:  &callback.Context{OpenChatID: "oc_1", OpenMessageID: "om_1"},
+	}})
+
+	if got == nil || got.ChatID != "oc_1" || got.MsgID != "om_1" || got.OperatorID != "ou_1" {
+		t.Fatalf("unexpected action %+v", got)
+	}
+	if len(got.Value) != 2 || got.Value["workspace"] != "api" {
+		t.Errorf("expected the string values, got %+v", got.Value)
+	}
+	if resp == nil || resp.Toast == nil || resp.Toast.Content != "正在切换到 api…" {
+		t.Errorf("expected the handler's toast, got %+v", resp)
+	}
+
+	if resp := c.handleCardAction(&callback.CardActionTriggerEvent{}); resp != nil {
+		t.Errorf("expected no response for an empty event, got %+v", resp)
+	}
+}
@@ -3,15 +3,18 @@ package feishu
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	lark "github.com/larksuite/oapi-sdk-go/v3"
 	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
 	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher/callback"
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
 	larkws "github.com/larksuite/oapi-sdk-go/v3/ws"
 )
@@ -28,6 +31,9 @@ type Message struct {
 	Mentions  []string // Mentioned user IDs (including bot)
 }
 
+// ImageContent is the Content of an image message, which has no text.
+const ImageContent = "[图片]"
+
 // Sender represents the message sender
 type Sender struct {
 	SenderID   string // User ID or bot ID
@@ -73,22 +79,65 @@ type MessageRecalled struct {
 // MessageRecalledHandler is the callback for recalled messages.
 type MessageRecalledHandler func(ev *MessageRecalled)
 
+// MessageRejectedHandler is the callback for messages of a known type that
+// is not in the accepted list.
+type MessageRejectedHandler func(msg *Message)
+
+// ReactionEvent contains info about a reaction added to a message.
+type ReactionEvent struct {
+	MsgID        string
+	EmojiType    string
+	OperatorType string // user, app
+	OperatorID   string // open_id for users, app_id for apps
+}
+
+// ReactionHandler is the callback for added reactions.
+type ReactionHandler func(ev *ReactionEvent)
+
+// BotJoinedHandler is the callback for the bot being added to a group chat.
+type BotJoinedHandler func(chatID string)
+
+// ChatChangedHandler is the callback for a chat's info or membership
+// changing (users added, removed or leaving, chat updated).
+type ChatChangedHandler func(chatID string)
+
 // Client is the Feishu API client
 type Client struct {
-	appID       string
-	appSecret   string
-	larkCli     *lark.Client
-	wsCli       *larkws.Client
-	onMessage   MessageHandler
-	onRecalled  MessageRecalledHandler
-	downloadDir string
-	debug       bool
-	ctx         context.Context
-	cancel      context.CancelFunc
+	appID         string
+	appSecret     string
+	larkCli       *lark.Client
+	wsCli         *larkws.Client
+	onMessage     MessageHandler
+	onRecalled    MessageRecalledHandler
+	onRejected    MessageRejectedHandler
+	onReaction    ReactionHandler
+	onBotJoined   BotJoinedHandler
+	onChatChanged ChatChangedHandler
+	onCardAction  CardActionHandler
+	downloadDir   string
+	maxDownload   int64
+	debug         bool
+	accepted      map[string]bool
+	botOpenID     string
+	ctx           context.Context
+	cancel        context.CancelFunc
 }
 
 const defaultRequestTimeout = 20 * time.Second
 
+// DefaultMaxAttachmentBytes caps downloaded attachments when no limit is set.
+const DefaultMaxAttachmentBytes = 20 << 20
+
+// ErrAttachmentTooLarge is returned when a download exceeds the attachment
+// size limit. The partial file is removed.
+var ErrAttachmentTooLarge = errors.New("attachment exceeds size limit")
+
+// DefaultAcceptedMsgTypes are the message types processed when none are configured.
+var DefaultAcceptedMsgTypes = []string{"text", "image", "post"}
+
+// supportedMsgTypes are the message types handleMessage knows how to parse.
+var supportedMsgTypes = map[string]bool{"text": true, "image": true, "post": true}
+
 // NewClient creates a new Feishu client
 func NewClient(appID, appSecret string) *Client {
 	return &Client{
@@ -111,10 +160,66 @@ func (c *Client) SetDownloadDir(dir string) {
 	c.downloadDir = dir
 }
 
+// SetMaxAttachmentBytes limits how large a downloaded attachment may be.
+// n <= 0 restores DefaultMaxAttachmentBytes.
+func (c *Client) SetMaxAttachmentBytes(n int64) {
+	c.maxDownload = n
+}
+
+func (c *Client) maxAttachmentBytes() int64 {
+	if c.maxDownload <= 0 {
+		return DefaultMaxAttachmentBytes
+	}
+	return c.maxDownload
+}
+
 func (c *Client) SetDebug(enabled bool) {
 	c.debug = enabled
 }
 
+// debugf prints routine per-request logs only when debug is enabled.
+func (c *Client) debugf(format string, args ...any) {
+	if !c.debug {
+		return
+	}
+	fmt.Printf("[Feishu][Debug] "+format+"\n", args...)
+}
+
+// SetBotOpenID sets the bot's open_id so its @-mention can be stripped from
+// incoming text before it is forwarded.
+func (c *Client) SetBotOpenID(openID string) {
+	c.botOpenID = strings.TrimSpace(openID)
+}
+
+// SetAcceptedMsgTypes limits which message types are forwarded to the handler.
+// An empty list restores DefaultAcceptedMsgTypes.
+func (c *Client) SetAcceptedMsgTypes(types []string) {
+	if len(types) == 0 {
+		c.accepted = nil
+		return
+	}
+	accepted := make(map[string]bool, len(types))
+	for _, t := range types {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			accepted[t] = true
+		}
+	}
+	c.accepted = accepted
+}
+
+func (c *Client) acceptsMsgType(msgType string) bool {
+	if c.accepted == nil {
+		for _, t := range DefaultAcceptedMsgTypes {
+			if t == msgType {
+				return true
+			}
+		}
+		return false
+	}
+	return c.accepted[msgType]
+}
+
 // OnMessage sets the message handler
 func (c *Client) OnMessage(handler MessageHandler) {
 	c.onMessage = handler
@@ -124,6 +229,27 @@ func (c *Client) OnMessageRecalled(handler MessageRecalledHandler) {
 	c.onRecalled = handler
 }
 
+// OnMessageRejected sets the handler for messages whose type is known but
+// not accepted, so the caller can tell the sender.
+func (c *Client) OnMessageRejected(handler MessageRejectedHandler) {
+	c.onRejected = handler
+}
+
+// OnReaction sets the handler for reactions added to messages.
+func (c *Client) OnReaction(handler ReactionHandler) {
+	c.onReaction = handler
+}
+
+// OnBotJoined sets the handler for the bot being added to a group chat.
+func (c *Client) OnBotJoined(handler BotJoinedHandler) {
+	c.onBotJoined = handler
+}
+
+// OnChatChanged sets the handler for a chat's info or membership changing.
+func (c *Client) OnChatChanged(handler ChatChangedHandler) {
+	c.onChatChanged = handler
+}
+
 // Start connects to Feishu via WebSocket and starts listening for messages
 func (c *Client) Start() error {
 	c.ctx, c.cancel = context.WithCancel(context.Background())
@@ -140,6 +266,41 @@ func (c *Client) Start() error {
 		OnP2MessageRecalledV1(func(ctx context.Context, event *larkim.P2MessageRecalledV1) error {
 			c.handleRecalled(event)
 			return nil
+		}).
+		OnP2MessageReactionCreatedV1(func(ctx context.Context, event *larkim.P2MessageReactionCreatedV1) error {
+			c.handleReactionCreated(event)
+			return nil
+		}).
+		OnP2ChatMemberBotAddedV1(func(ctx context.Context, event *larkim.P2ChatMemberBotAddedV1) error {
+			c.handleBotAdded(event)
+			return nil
+		}).
+		OnP2ChatMemberUserAddedV1(func(ctx context.Context, event *larkim.P2ChatMemberUserAddedV1) error {
+			if event != nil && event.Event != nil {
+				c.handleChatChanged(event.Event.ChatId)
+			}
+			return nil
+		}).
+		OnP2ChatMemberUserDeletedV1(func(ctx context.Context, event *larkim.P2ChatMemberUserDeletedV1) error {
+			if event != nil && event.Event != nil {
+				c.handleChatChanged(event.Event.ChatId)
+			}
+			return nil
+		}).
+		OnP2ChatMemberUserWithdrawnV1(func(ctx context.Context, event *larkim.P2ChatMemberUserWithdrawnV1) error {
+			if event != nil && event.Event != nil {
+				c.handleChatChanged(event.Event.ChatId)
+			}
+			return nil
+		}).
+		OnP2ChatUpdatedV1(func(ctx context.Context, event *larkim.P2ChatUpdatedV1) error {
+			if event != nil && event.Event != nil {
+				c.handleChatChanged(event.Event.ChatId)
+			}
+			return nil
+		}).
+		OnP2CardActionTrigger(func(ctx context.Context, event *callback.CardActionTriggerEvent) (*callback.CardActionTriggerResponse, error) {
+			return c.handleCardAction(event), nil
 		})
 
 	// Create WebSocket client
@@ -200,12 +361,44 @@ func (c *Client) handleMessage(event *larkim.P2MessageReceiveV1) {
 	}
 
 	// Parse mentions
+	var botMentionKeys []string
 	if rawMsg.Mentions != nil {
 		for _, mention := range rawMsg.Mentions {
 			if mention.Id != nil && mention.Id.OpenId != nil {
 				msg.Mentions = append(msg.Mentions, *mention.Id.OpenId)
+				if c.botOpenID != "" && *mention.Id.OpenId == c.botOpenID && mention.Key != nil {
+					botMentionKeys = append(botMentionKeys, *mention.Key)
+				}
+			}
+		}
+	}
+
+	switch msg.MsgType {
+	case "sticker":
+		// Stickers carry nothing Codex can use; drop them quietly.
+		c.debugf("Ignoring sticker message %s", msg.MsgID)
+		return
+	case "system":
+		// Join/leave notices. The bot's own join arrives as a separate
+		// chat.member.bot.added event (see handleBotAdded).
+		content := ""
+		if rawMsg.Content != nil {
+			content = *rawMsg.Content
+		}
+		c.debugf("System message in %s: %s", msg.ChatID, parseSystemContent(content))
+		return
+	}
+
+	if !c.acceptsMsgType(msg.MsgType) {
+		if supportedMsgTypes[msg.MsgType] {
+			fmt.Printf("[Feishu] Message type disabled: %s\n", msg.MsgType)
+			if c.onRejected != nil {
+				c.onRejected(msg)
 			}
+			return
 		}
+		fmt.Printf("[Feishu] Unsupported message type: %s\n", msg.MsgType)
+		return
 	}
 
 	switch msg.MsgType {
@@ -213,7 +406,7 @@ func (c *Client) handleMessage(event *larkim.P2MessageReceiveV1) {
 		msg.Content = c.parseTextContent(*rawMsg.Content)
 	case "image":
 		msg.ImageKeys = c.parseImageContent(*rawMsg.Content)
-		msg.Content = "[图片]"
+		msg.Content = ImageContent
 	case "post":
 		content, imageKeys := c.parsePostContent(*rawMsg.Content)
 		msg.Content = content
@@ -223,8 +416,11 @@ func (c *Client) handleMessage(event *larkim.P2MessageReceiveV1) {
 		fmt.Printf("[Feishu] Unsupported message type: %s\n", msg.MsgType)
 		return
 	}
+	if len(botMentionKeys) > 0 {
+		msg.Content = StripMentions(msg.Content, botMentionKeys...)
+	}
 
-	fmt.Printf("[Feishu] Received %s from %s chat %s: %s\n", msg.MsgType, msg.ChatType, msg.ChatID, truncate(msg.Content, 50))
+	c.debugf("Received %s from %s chat %s: %s", msg.MsgType, msg.ChatType, msg.ChatID, truncate(msg.Content, 50))
 
 	if c.onMessage != nil {
 		c.onMessage(msg)
@@ -275,6 +471,121 @@ func (c *Client) handleRecalled(event *larkim.P2MessageRecalledV1) {
 	}
 }
 
+func (c *Client) handleReactionCreated(event *larkim.P2MessageReactionCreatedV1) {
+	if event == nil || event.Event == nil {
+		return
+	}
+	data := event.Event
+	if data.MessageId == nil || *data.MessageId == "" {
+		return
+	}
+	if data.ReactionType == nil || data.ReactionType.EmojiType == nil {
+		return
+	}
+
+	ev := &ReactionEvent{
+		MsgID:     *data.MessageId,
+		EmojiType: *data.ReactionType.EmojiType,
+	}
+	if data.OperatorType != nil {
+		ev.OperatorType = *data.OperatorType
+	}
+	if data.UserId != nil && data.UserId.OpenId != nil {
+		ev.OperatorID = *data.UserId.OpenId
+	} else if data.AppId != nil {
+		ev.OperatorID = *data.AppId
+	}
+
+	if c.debug {
+		fmt.Printf("[Feishu][Debug] Reaction %s on %s by %s(%s)\n", ev.EmojiType, ev.MsgID, ev.OperatorType, ev.OperatorID)
+	}
+
+	if c.onReaction != nil {
+		c.onReaction(ev)
+	}
+}
+
+func (c *Client) handleBotAdded(event *larkim.P2ChatMemberBotAddedV1) {
+	if event == nil || event.Event == nil || event.Event.ChatId == nil || *event.Event.ChatId == "" {
+		return
+	}
+	chatID := *event.Event.ChatId
+	fmt.Printf("[Feishu] Bot added to chat %s\n", chatID)
+	if c.onBotJoined != nil {
+		c.onBotJoined(chatID)
+	}
+}
+
+func (c *Client) handleChatChanged(chatID *string) {
+	if chatID == nil || *chatID == "" {
+		return
+	}
+	c.debugf("Chat %s changed", *chatID)
+	if c.onChatChanged != nil {
+		c.onChatChanged(*chatID)
+	}
+}
+
+// parseSystemContent renders a system message (e.g. {"template":"{from_user}
+// invited {to_chatters} to this chat","from_user":["A"],"to_chatters":["B"]})
+// as plain text, returning content unchanged if it isn't in that shape.
+func parseSystemContent(content string) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &fields); err != nil {
+		return content
+	}
+	var template string
+	if err := json.Unmarshal(fields["template"], &template); err != nil || template == "" {
+		return content
+	}
+	for key, raw := range fields {
+		var names []string
+		if json.Unmarshal(raw, &names) != nil {
+			continue
+		}
+		template = strings.ReplaceAll(template, "{"+key+"}", strings.Join(names, ", "))
+	}
+	return template
+}
+
+// StripMentions removes the given mention placeholders (e.g. "@_user_1") from
+// text. Only whole tokens are removed, so "@_user_1" leaves "@_user_10" alone,
+// and the surrounding whitespace is collapsed so no gaps are left behind.
+func StripMentions(text string, keys ...string) string {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		from := 0
+		for {
+			i := strings.Index(text[from:], key)
+			if i < 0 {
+				break
+			}
+			i += from
+			end := i + len(key)
+			if end < len(text) && isMentionKeyChar(text[end]) {
+				from = end
+				continue
+			}
+			before := strings.TrimRight(text[:i], " ")
+			after := strings.TrimLeft(text[end:], " ")
+			if before != "" && after != "" && !strings.HasSuffix(before, "\n") && !strings.HasPrefix(after, "\n") {
+				text = before + " " + after
+				from = len(before) + 1
+			} else {
+				text = before + after
+				from = len(before)
+			}
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+func isMentionKeyChar(ch byte) bool {
+	return ch == '_' || ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
 // parseTextContent extracts text from a text message
 func (c *Client) parseTextContent(content string) string {
 	var parsed struct {
@@ -369,23 +680,46 @@ func (c *Client) DownloadImage(messageID, imageKey string) (string, error) {
 
 	// Save to file
 	filePath := filepath.Join(c.downloadDir, imageKey+".png")
-	file, err := os.Create(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create file: %w", err)
+	if err := saveLimited(filePath, resp.File, c.maxAttachmentBytes()); err != nil {
+		return "", err
 	}
-	defer file.Close()
 
-	_, err = io.Copy(file, resp.File)
+	c.debugf("Downloaded image to %s", filePath)
+	return filePath, nil
+}
+
+// saveLimited writes r to path, failing with ErrAttachmentTooLarge (and
+// removing the partial file) once more than max bytes have been read.
+func saveLimited(path string, r io.Reader, max int64) error {
+	file, err := os.Create(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to write file: %w", err)
+		return fmt.Errorf("failed to create file: %w", err)
 	}
 
-	fmt.Printf("[Feishu] Downloaded image to %s\n", filePath)
-	return filePath, nil
+	n, err := io.Copy(file, io.LimitReader(r, max+1))
+	closeErr := file.Close()
+	if err == nil && n > max {
+		err = ErrAttachmentTooLarge
+	} else if err != nil {
+		err = fmt.Errorf("failed to write file: %w", err)
+	} else if closeErr != nil {
+		err = fmt.Errorf("failed to write file: %w", closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	return nil
 }
 
 // SendText sends a text message to a chat
 func (c *Client) SendText(chatID, text string) error {
+	_, err := c.SendTextWithID(chatID, text)
+	return err
+}
+
+// SendTextWithID sends a text message to a chat and returns the new message ID
+func (c *Client) SendTextWithID(chatID, text string) (string, error) {
 	content := map[string]string{"text": text}
 	contentJSON, _ := json.Marshal(content)
 
@@ -402,18 +736,27 @@ func (c *Client) SendText(chatID, text string) error {
 	defer cancel()
 	resp, err := c.larkCli.Im.Message.Create(ctx, req)
 	if err != nil {
-		return fmt.Errorf("send message failed: %w", err)
+		return "", fmt.Errorf("send message failed: %w", err)
 	}
 	if !resp.Success() {
-		return fmt.Errorf("send message error: %s", resp.Msg)
+		return "", fmt.Errorf("send message error: %s", resp.Msg)
 	}
 
-	fmt.Printf("[Feishu] Message sent to %s\n", chatID)
-	return nil
+	c.debugf("Message sent to %s", chatID)
+	if resp.Data != nil && resp.Data.MessageId != nil {
+		return *resp.Data.MessageId, nil
+	}
+	return "", nil
 }
 
 // ReplyText replies to a specific message with a text message (quote-style reply)
 func (c *Client) ReplyText(messageID, text string, replyInThread bool) error {
+	_, err := c.ReplyTextWithID(messageID, text, replyInThread)
+	return err
+}
+
+// ReplyTextWithID replies to a specific message with a text message and returns the new message ID
+func (c *Client) ReplyTextWithID(messageID, text string, replyInThread bool) (string, error) {
 	content := map[string]string{"text": text}
 	contentJSON, _ := json.Marshal(content)
 
@@ -430,25 +773,114 @@ func (c *Client) ReplyText(messageID, text string, replyInThread bool) error {
 	defer cancel()
 	resp, err := c.larkCli.Im.Message.Reply(ctx, req)
 	if err != nil {
-		return fmt.Errorf("reply message failed: %w", err)
+		return "", fmt.Errorf("reply message failed: %w", err)
+	}
+	if !resp.Success() {
+		return "", fmt.Errorf("reply message error: %s", resp.Msg)
+	}
+
+	c.debugf("Replied to message %s", messageID)
+	if resp.Data != nil && resp.Data.MessageId != nil {
+		return *resp.Data.MessageId, nil
+	}
+	return "", nil
+}
+
+// uploadImage uploads a local image file and returns its image_key
+func (c *Client) uploadImage(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open image failed: %w", err)
+	}
+	defer f.Close()
+
+	req := larkim.NewCreateImageReqBuilder().
+		Body(larkim.NewCreateImageReqBodyBuilder().
+			ImageType(larkim.ImageTypeMessage).
+			Image(f).
+			Build()).
+		Build()
+
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	resp, err := c.larkCli.Im.Image.Create(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("upload image failed: %w", err)
+	}
+	if !resp.Success() {
+		return "", fmt.Errorf("upload image error: %s", resp.Msg)
+	}
+	if resp.Data == nil || resp.Data.ImageKey == nil {
+		return "", fmt.Errorf("upload image error: empty image_key")
+	}
+	return *resp.Data.ImageKey, nil
+}
+
+// SendImage uploads a local image and sends it to a chat
+func (c *Client) SendImage(chatID, path string) error {
+	imageKey, err := c.uploadImage(path)
+	if err != nil {
+		return err
+	}
+	contentJSON, _ := json.Marshal(map[string]string{"image_key": imageKey})
+
+	req := larkim.NewCreateMessageReqBuilder().
+		ReceiveIdType(larkim.ReceiveIdTypeChatId).
+		Body(larkim.NewCreateMessageReqBodyBuilder().
+			ReceiveId(chatID).
+			MsgType(larkim.MsgTypeImage).
+			Content(string(contentJSON)).
+			Build()).
+		Build()
+
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	resp, err := c.larkCli.Im.Message.Create(ctx, req)
+	if err != nil {
+		return fmt.Errorf("send image failed: %w", err)
+	}
+	if !resp.Success() {
+		return fmt.Errorf("send image error: %s", resp.Msg)
+	}
+
+	c.debugf("Image sent to %s", chatID)
+	return nil
+}
+
+// ReplyImage uploads a local image and sends it as a reply to a specific message
+func (c *Client) ReplyImage(messageID, path string, replyInThread bool) error {
+	imageKey, err := c.uploadImage(path)
+	if err != nil {
+		return err
+	}
+	contentJSON, _ := json.Marshal(map[string]string{"image_key": imageKey})
+
+	req := larkim.NewReplyMessageReqBuilder().
+		MessageId(messageID).
+		Body(larkim.NewReplyMessageReqBodyBuilder().
+			MsgType(larkim.MsgTypeImage).
+			Content(string(contentJSON)).
+			ReplyInThread(replyInThread).
+			Build()).
+		Build()
+
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	resp, err := c.larkCli.Im.Message.Reply(ctx, req)
+	if err != nil {
+		return fmt.Errorf("reply image failed: %w", err)
 	}
 	if !resp.Success() {
-		return fmt.Errorf("reply message error: %s", resp.Msg)
+		return fmt.Errorf("reply image error: %s", resp.Msg)
 	}
 
-	fmt.Printf("[Feishu] Replied to message %s\n", messageID)
+	c.debugf("Replied image to message %s", messageID)
 	return nil
 }
 
 // SendRichText sends a rich text (post) message to a chat
 func (c *Client) SendRichText(chatID, title string, content [][]map[string]interface{}) error {
-	post := map[string]interface{}{
-		"zh_cn": map[string]interface{}{
-			"title":   title,
-			"content": content,
-		},
-	}
-	contentJSON, _ := json.Marshal(post)
+	contentJSON, _ := postJSON(title, content)
 
 	req := larkim.NewCreateMessageReqBuilder().
 		ReceiveIdType(larkim.ReceiveIdTypeChatId).
@@ -469,19 +901,13 @@ func (c *Client) SendRichText(chatID, title string, content [][]map[string]inter
 		return fmt.Errorf("send rich text error: %s", resp.Msg)
 	}
 
-	fmt.Printf("[Feishu] Rich text sent to %s\n", chatID)
+	c.debugf("Rich text sent to %s", chatID)
 	return nil
 }
 
 // ReplyRichText replies to a specific message with a rich text (post) message
 func (c *Client) ReplyRichText(messageID, title string, content [][]map[string]interface{}, replyInThread bool) error {
-	post := map[string]interface{}{
-		"zh_cn": map[string]interface{}{
-			"title":   title,
-			"content": content,
-		},
-	}
-	contentJSON, _ := json.Marshal(post)
+	contentJSON, _ := postJSON(title, content)
 
 	req := larkim.NewReplyMessageReqBuilder().
 		MessageId(messageID).
@@ -502,7 +928,7 @@ func (c *Client) ReplyRichText(messageID, title string, content [][]map[string]i
 		return fmt.Errorf("reply rich text error: %s", resp.Msg)
 	}
 
-	fmt.Printf("[Feishu] Rich text replied to %s\n", messageID)
+	c.debugf("Rich text replied to %s", messageID)
 	return nil
 }
 
@@ -525,13 +951,60 @@ func (c *Client) AddReaction(messageID, emojiType string) (string, error) {
 		return "", fmt.Errorf("add reaction error: %s", resp.Msg)
 	}
 
-	fmt.Printf("[Feishu] Reaction %s added to message %s\n", emojiType, messageID)
+	c.debugf("Reaction %s added to message %s", emojiType, messageID)
 	if resp.Data != nil && resp.Data.ReactionId != nil {
 		return *resp.Data.ReactionId, nil
 	}
 	return "", nil
 }
 
+// UpdateText replaces the content of a text message previously sent by the bot.
+func (c *Client) UpdateText(messageID, text string) error {
+	content := map[string]string{"text": text}
+	contentJSON, _ := json.Marshal(content)
+
+	req := larkim.NewUpdateMessageReqBuilder().
+		MessageId(messageID).
+		Body(larkim.NewUpdateMessageReqBodyBuilder().
+			MsgType(larkim.MsgTypeText).
+			Content(string(contentJSON)).
+			Build()).
+		Build()
+
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	resp, err := c.larkCli.Im.Message.Update(ctx, req)
+	if err != nil {
+		return fmt.Errorf("update message failed: %w", err)
+	}
+	if !resp.Success() {
+		return fmt.Errorf("update message error: %s", resp.Msg)
+	}
+
+	c.debugf("Updated message %s", messageID)
+	return nil
+}
+
+// DeleteMessage recalls a message previously sent by the bot.
+func (c *Client) DeleteMessage(messageID string) error {
+	req := larkim.NewDeleteMessageReqBuilder().
+		MessageId(messageID).
+		Build()
+
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	resp, err := c.larkCli.Im.Message.Delete(ctx, req)
+	if err != nil {
+		return fmt.Errorf("delete message failed: %w", err)
+	}
+	if !resp.Success() {
+		return fmt.Errorf("delete message error: %s", resp.Msg)
+	}
+
+	c.debugf("Deleted message %s", messageID)
+	return nil
+}
+
 // RemoveReaction removes an emoji reaction from a message
 func (c *Client) RemoveReaction(messageID, reactionID string) error {
 	req := larkim.NewDeleteMessageReactionReqBuilder().
@@ -549,7 +1022,7 @@ func (c *Client) RemoveReaction(messageID, reactionID string) error {
 		return fmt.Errorf("remove reaction error: %s", resp.Msg)
 	}
 
-	fmt.Printf("[Feishu] Reaction removed from message %s\n", messageID)
+	c.debugf("Reaction removed from message %s", messageID)
 	return nil
 }
 
@@ -609,7 +1082,7 @@ func (c *Client) GetChatHistory(chatID string, pageSize int) ([]*HistoryMessage,
 		messages = append(messages, msg)
 	}
 
-	fmt.Printf("[Feishu] Retrieved %d messages from chat %s\n", len(messages), chatID)
+	c.debugf("Retrieved %d messages from chat %s", len(messages), chatID)
 	return messages, nil
 }
 
@@ -644,7 +1117,7 @@ func (c *Client) GetChatMembers(chatID string) ([]*ChatMember, error) {
 		members = append(members, member)
 	}
 
-	fmt.Printf("[Feishu] Retrieved %d members from chat %s\n", len(members), chatID)
+	c.debugf("Retrieved %d members from chat %s", len(members), chatID)
 	return members, nil
 }
 
@@ -685,7 +1158,7 @@ func (c *Client) GetChatInfo(chatID string) (*ChatInfo, error) {
 		info.MemberCount = count
 	}
 
-	fmt.Printf("[Feishu] Got chat info for %s: %s (%d members)\n", chatID, info.Name, info.MemberCount)
+	c.debugf("Got chat info for %s: %s (%d members)", chatID, info.Name, info.MemberCount)
 	return info, nil
 }
 
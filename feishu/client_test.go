@@ -1,6 +1,11 @@
 package feishu
 
 import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
@@ -471,3 +476,291 @@ func TestMessageWithSenderAndMentions(t *testing.T) {
 		t.Errorf("Mentions length mismatch: got %d, want 2", len(msg.Mentions))
 	}
 }
+
+func newReceiveEvent(msgType, content string) *larkim.P2MessageReceiveV1 {
+	chatID := "oc_test"
+	msgID := "om_test"
+	chatType := "p2p"
+	return &larkim.P2MessageReceiveV1{
+		Event: &larkim.P2MessageReceiveV1Data{
+			Message: &larkim.EventMessage{
+				ChatId:      &chatID,
+				MessageId:   &msgID,
+				MessageType: &msgType,
+				ChatType:    &chatType,
+				Content:     &content,
+			},
+		},
+	}
+}
+
+func TestHandleMessage_AcceptedMsgTypes(t *testing.T) {
+	client := NewClient("app_id", "app_secret")
+
+	var got []*Message
+	client.OnMessage(func(msg *Message) {
+		got = append(got, msg)
+	})
+
+	// Defaults accept text and image.
+	client.handleMessage(newReceiveEvent("text", `{"text":"hi"}`))
+	client.handleMessage(newReceiveEvent("image", `{"image_key":"img_1"}`))
+	if len(got) != 2 {
+		t.Fatalf("expected 2 messages with default types, got %d", len(got))
+	}
+
+	// Disabling image drops image messages but keeps text.
+	got = nil
+	client.SetAcceptedMsgTypes([]string{"text", " post "})
+	client.handleMessage(newReceiveEvent("image", `{"image_key":"img_1"}`))
+	client.handleMessage(newReceiveEvent("post", `{"content":[[{"tag":"text","text":"p"}]]}`))
+	client.handleMessage(newReceiveEvent("text", `{"text":"hi"}`))
+	if len(got) != 2 || got[0].MsgType != "post" || got[1].MsgType != "text" {
+		t.Fatalf("unexpected messages: %+v", got)
+	}
+
+	// Resetting restores defaults.
+	got = nil
+	client.SetAcceptedMsgTypes(nil)
+	client.handleMessage(newReceiveEvent("image", `{"image_key":"img_1"}`))
+	if len(got) != 1 {
+		t.Fatalf("expected image to be accepted after reset, got %d", len(got))
+	}
+}
+
+func TestHandleReactionCreated(t *testing.T) {
+	client := NewClient("app_id", "app_secret")
+
+	var got []*ReactionEvent
+	client.OnReaction(func(ev *ReactionEvent) {
+		got = append(got, ev)
+	})
+
+	msgID := "om_test"
+	emoji := "CheckMark"
+	operatorType := "user"
+	openID := "ou_user"
+	client.handleReactionCreated(&larkim.P2MessageReactionCreatedV1{
+		Event: &larkim.P2MessageReactionCreatedV1Data{
+			MessageId:    &msgID,
+			ReactionType: &larkim.Emoji{EmojiType: &emoji},
+			OperatorType: &operatorType,
+			UserId:       &larkim.UserId{OpenId: &openID},
+		},
+	})
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 reaction event, got %d", len(got))
+	}
+	want := ReactionEvent{MsgID: msgID, EmojiType: emoji, OperatorType: operatorType, OperatorID: openID}
+	if *got[0] != want {
+		t.Errorf("unexpected event: %+v", *got[0])
+	}
+
+	// Events without an emoji type are ignored.
+	client.handleReactionCreated(&larkim.P2MessageReactionCreatedV1{
+		Event: &larkim.P2MessageReactionCreatedV1Data{MessageId: &msgID},
+	})
+	client.handleReactionCreated(nil)
+	if len(got) != 1 {
+		t.Errorf("expected incomplete events to be ignored, got %d", len(got))
+	}
+}
+
+func TestStripMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		keys []string
+		want string
+	}{
+		{"start", "@_user_1 修复这个 bug", []string{"@_user_1"}, "修复这个 bug"},
+		{"middle", "请 @_user_1 看一下", []string{"@_user_1"}, "请 看一下"},
+		{"end", "看一下 @_user_1", []string{"@_user_1"}, "看一下"},
+		{"keeps other mentions", "@_user_1 让 @_user_2 review", []string{"@_user_1"}, "让 @_user_2 review"},
+		{"multiple", "@_user_1 @_user_2 /help", []string{"@_user_1", "@_user_2"}, "/help"},
+		{"repeated", "@_user_1 hi @_user_1", []string{"@_user_1"}, "hi"},
+		{"whole token only", "@_user_10 hi @_user_1", []string{"@_user_1"}, "@_user_10 hi"},
+		{"keeps newlines", "@_user_1\n第一行\n第二行", []string{"@_user_1"}, "第一行\n第二行"},
+		{"no keys", "  hi  ", nil, "hi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripMentions(tt.text, tt.keys...); got != tt.want {
+				t.Errorf("StripMentions(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleMessage_StripsBotMention(t *testing.T) {
+	client := NewClient("app_id", "app_secret")
+	client.SetBotOpenID("ou_bot")
+
+	var got *Message
+	client.OnMessage(func(msg *Message) {
+		got = msg
+	})
+
+	event := newReceiveEvent("text", `{"text":"@_user_1 修复这个 bug @_user_2"}`)
+	botKey, botID := "@_user_1", "ou_bot"
+	userKey, userID := "@_user_2", "ou_user"
+	event.Event.Message.Mentions = []*larkim.MentionEvent{
+		{Key: &botKey, Id: &larkim.UserId{OpenId: &botID}},
+		{Key: &userKey, Id: &larkim.UserId{OpenId: &userID}},
+	}
+	client.handleMessage(event)
+
+	if got == nil {
+		t.Fatal("message not delivered")
+	}
+	if got.Content != "修复这个 bug @_user_2" {
+		t.Errorf("unexpected content: %q", got.Content)
+	}
+	if len(got.Mentions) != 2 {
+		t.Errorf("expected both mentions kept in Mentions, got %v", got.Mentions)
+	}
+}
+
+func TestHandleMessage_ReceiveLogGatedByDebug(t *testing.T) {
+	capture := func(fn func()) string {
+		orig := os.Stdout
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.Stdout = w
+		fn()
+		w.Close()
+		os.Stdout = orig
+		out, _ := io.ReadAll(r)
+		return string(out)
+	}
+
+	client := NewClient("app_id", "app_secret")
+	if out := capture(func() { client.handleMessage(newReceiveEvent("text", `{"text":"hi"}`)) }); strings.Contains(out, "Received") {
+		t.Errorf("expected no receive log without debug, got %q", out)
+	}
+
+	client.SetDebug(true)
+	if out := capture(func() { client.handleMessage(newReceiveEvent("text", `{"text":"hi"}`)) }); !strings.Contains(out, "[Feishu][Debug] Received text") {
+		t.Errorf("expected receive log with debug, got %q", out)
+	}
+}
+
+// endlessReader yields zero bytes forever, like a huge attachment stream.
+type endlessReader struct{ read int64 }
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func TestSaveLimited_RejectsOversizedStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.png")
+	r := &endlessReader{}
+
+	err := saveLimited(path, r, 1024)
+	if !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Fatalf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+	if r.read > 64*1024 {
+		t.Fatalf("read %d bytes; download should stop right after the limit", r.read)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected partial file to be removed, stat err=%v", err)
+	}
+}
+
+func TestSaveLimited_AllowsFileAtLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ok.png")
+	if err := saveLimited(path, strings.NewReader(strings.Repeat("x", 1024)), 1024); err != nil {
+		t.Fatalf("saveLimited failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() != 1024 {
+		t.Fatalf("expected 1024-byte file, got %v, %v", info, err)
+	}
+}
+
+func TestMaxAttachmentBytes_Default(t *testing.T) {
+	c := NewClient("id", "secret")
+	if got := c.maxAttachmentBytes(); got != DefaultMaxAttachmentBytes {
+		t.Fatalf("expected default limit, got %d", got)
+	}
+	c.SetMaxAttachmentBytes(10)
+	if got := c.maxAttachmentBytes(); got != 10 {
+		t.Fatalf("expected 10, got %d", got)
+	}
+}
+
+func TestHandleMessage_IgnoresStickerAndSystem(t *testing.T) {
+	client := NewClient("app_id", "app_secret")
+
+	var got []*Message
+	client.OnMessage(func(msg *Message) {
+		got = append(got, msg)
+	})
+
+	client.SetAcceptedMsgTypes([]string{"text", "sticker", "system"})
+	client.handleMessage(newReceiveEvent("sticker", `{"file_key":"file_1"}`))
+	client.handleMessage(newReceiveEvent("system", `{"template":"{from_user} invited {to_chatters} to this chat","from_user":["Alice"],"to_chatters":["Bob"]}`))
+	if len(got) != 0 {
+		t.Fatalf("expected sticker/system messages to be dropped, got %+v", got)
+	}
+}
+
+func TestParseSystemContent(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{`{"template":"{from_user} invited {to_chatters} to this chat","from_user":["Alice"],"to_chatters":["Bob","Carol"]}`, "Alice invited Bob, Carol to this chat"},
+		{`{"template":"{to_chatters} joined","to_chatters":["Bob"],"divider_text":{"text":"x"}}`, "Bob joined"},
+		{`not json`, "not json"},
+		{`{"text":"hi"}`, `{"text":"hi"}`},
+	}
+	for _, tt := range tests {
+		if got := parseSystemContent(tt.content); got != tt.want {
+			t.Errorf("parseSystemContent(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestHandleBotAdded(t *testing.T) {
+	client := NewClient("app_id", "app_secret")
+
+	var joined []string
+	client.OnBotJoined(func(chatID string) {
+		joined = append(joined, chatID)
+	})
+
+	chatID := "oc_1"
+	client.handleBotAdded(&larkim.P2ChatMemberBotAddedV1{Event: &larkim.P2ChatMemberBotAddedV1Data{ChatId: &chatID}})
+	client.handleBotAdded(&larkim.P2ChatMemberBotAddedV1{Event: &larkim.P2ChatMemberBotAddedV1Data{}})
+	if len(joined) != 1 || joined[0] != "oc_1" {
+		t.Fatalf("unexpected joins: %v", joined)
+	}
+}
+
+func TestHandleChatChanged(t *testing.T) {
+	client := NewClient("app_id", "app_secret")
+
+	var changed []string
+	client.OnChatChanged(func(chatID string) {
+		changed = append(changed, chatID)
+	})
+
+	chatID := "oc_1"
+	empty := ""
+	client.handleChatChanged(&chatID)
+	client.handleChatChanged(&empty)
+	client.handleChatChanged(nil)
+	if len(changed) != 1 || changed[0] != "oc_1" {
+		t.Fatalf("unexpected changes: %v", changed)
+	}
+}
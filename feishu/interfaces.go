@@ -4,13 +4,26 @@ package feishu
 type FeishuClient interface {
 	OnMessage(handler MessageHandler)
 	OnMessageRecalled(handler MessageRecalledHandler)
+	OnMessageRejected(handler MessageRejectedHandler)
+	OnReaction(handler ReactionHandler)
+	OnBotJoined(handler BotJoinedHandler)
+	OnChatChanged(handler ChatChangedHandler)
+	OnCardAction(handler CardActionHandler)
 	SetDebug(enabled bool)
+	SetAcceptedMsgTypes(types []string)
 	Start() error
 	Stop()
 	SendText(chatID, text string) error
+	SendTextWithID(chatID, text string) (messageID string, err error)
 	SendRichText(chatID, title string, content [][]map[string]interface{}) error
 	ReplyText(messageID, text string, replyInThread bool) error
+	ReplyTextWithID(messageID, text string, replyInThread bool) (replyID string, err error)
 	ReplyRichText(messageID, title string, content [][]map[string]interface{}, replyInThread bool) error
+	ReplyCard(messageID string, card map[string]interface{}, replyInThread bool) error
+	UpdateText(messageID, text string) error
+	DeleteMessage(messageID string) error
+	SendImage(chatID, path string) error
+	ReplyImage(messageID, path string, replyInThread bool) error
 	AddReaction(messageID, emojiType string) (reactionID string, err error)
 	RemoveReaction(messageID, reactionID string) error
 	DownloadImage(messageID, imageKey string) (string, error)
@@ -0,0 +1,105 @@
+package feishu
+
+import "encoding/json"
+
+// Text styles accepted by a post's "text" element.
+const (
+	StyleBold        = "bold"
+	StyleItalic      = "italic"
+	StyleUnderline   = "underline"
+	StyleLineThrough = "lineThrough"
+)
+
+// PostBuilder assembles the content of a post ("rich text") message, the
+// [][]map[string]interface{} taken by SendRichText and ReplyRichText. Each
+// inner slice is one paragraph; runs are appended to the current paragraph
+// until NewLine starts the next one.
+type PostBuilder struct {
+	content [][]map[string]interface{}
+	line    []map[string]interface{}
+}
+
+// NewPostBuilder returns an empty builder.
+func NewPostBuilder() *PostBuilder {
+	return &PostBuilder{}
+}
+
+// TextRun appends plain text with optional Style* styles. Empty text is
+// skipped, since Feishu rejects empty text elements.
+func (p *PostBuilder) TextRun(text string, styles ...string) *PostBuilder {
+	if text == "" {
+		return p
+	}
+	el := map[string]interface{}{"tag": "text", "text": text}
+	if len(styles) > 0 {
+		el["style"] = append([]string(nil), styles...)
+	}
+	p.line = append(p.line, el)
+	return p
+}
+
+// BoldRun appends bold text.
+func (p *PostBuilder) BoldRun(text string) *PostBuilder {
+	return p.TextRun(text, StyleBold)
+}
+
+// LinkRun appends a hyperlink; the URL is shown when text is empty.
+func (p *PostBuilder) LinkRun(text, href string) *PostBuilder {
+	if href == "" {
+		return p.TextRun(text)
+	}
+	if text == "" {
+		text = href
+	}
+	p.line = append(p.line, map[string]interface{}{"tag": "a", "text": text, "href": href})
+	return p
+}
+
+// CodeRun appends a code block. Feishu only renders code blocks as a whole
+// paragraph, so it ends the current paragraph before and after the block.
+func (p *PostBuilder) CodeRun(language, code string) *PostBuilder {
+	if code == "" {
+		return p
+	}
+	p.flush()
+	el := map[string]interface{}{"tag": "code_block", "text": code}
+	if language != "" {
+		el["language"] = language
+	}
+	p.content = append(p.content, []map[string]interface{}{el})
+	return p
+}
+
+// NewLine ends the current paragraph. A NewLine on an empty paragraph adds
+// a blank line.
+func (p *PostBuilder) NewLine() *PostBuilder {
+	if len(p.line) == 0 {
+		p.content = append(p.content, []map[string]interface{}{})
+		return p
+	}
+	p.flush()
+	return p
+}
+
+func (p *PostBuilder) flush() {
+	if len(p.line) > 0 {
+		p.content = append(p.content, p.line)
+		p.line = nil
+	}
+}
+
+// Content returns the built paragraphs, including any unfinished one.
+func (p *PostBuilder) Content() [][]map[string]interface{} {
+	p.flush()
+	return p.content
+}
+
+// postJSON renders a post message body as sent to the Feishu API.
+func postJSON(title string, content [][]map[string]interface{}) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"zh_cn": map[string]interface{}{
+			"title":   title,
+			"content": content,
+		},
+	})
+}
@@ -0,0 +1,89 @@
+package feishu
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPostBuilder(t *testing.T) {
+	content := NewPostBuilder().
+		TextRun("plain ").BoldRun("bold").TextRun("").
+		NewLine().
+		LinkRun("docs", "https://example.com").LinkRun("", "https://example.org").
+		CodeRun("go", "fmt.Println(1)").
+		TextRun("after", StyleItalic, StyleUnderline).
+		Content()
+
+	want := [][]map[string]interface{}{
+		{
+			{"tag": "text", "text": "plain "},
+			{"tag": "text", "text": "bold", "style": []string{"bold"}},
+		},
+		{
+			{"tag": "a", "text": "docs", "href": "https://example.com"},
+			{"tag": "a", "text": "https://example.org", "href": "https://example.org"},
+		},
+		{
+			{"tag": "code_block", "language": "go", "text": "fmt.Println(1)"},
+		},
+		{
+			{"tag": "text", "text": "after", "style": []string{"italic", "underline"}},
+		},
+	}
+	if !reflect.DeepEqual(content, want) {
+		t.Fatalf("content = %#v\nwant %#v", content, want)
+	}
+}
+
+func TestPostBuilder_SerializesToPostJSON(t *testing.T) {
+	content := NewPostBuilder().
+		BoldRun("title").NewLine().
+		NewLine().
+		TextRun("see ").LinkRun("here", "https://example.com").
+		Content()
+
+	raw, err := postJSON("T", content)
+	if err != nil {
+		t.Fatalf("postJSON: %v", err)
+	}
+
+	var post map[string]struct {
+		Title   string `json:"title"`
+		Content [][]struct {
+			Tag   string   `json:"tag"`
+			Text  string   `json:"text"`
+			Href  string   `json:"href"`
+			Style []string `json:"style"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &post); err != nil {
+		t.Fatalf("invalid JSON %s: %v", raw, err)
+	}
+	zh, ok := post["zh_cn"]
+	if !ok || zh.Title != "T" {
+		t.Fatalf("unexpected post %s", raw)
+	}
+	if len(zh.Content) != 3 {
+		t.Fatalf("expected 3 paragraphs (one blank), got %s", raw)
+	}
+	if got := zh.Content[0][0]; got.Tag != "text" || got.Text != "title" || len(got.Style) != 1 || got.Style[0] != StyleBold {
+		t.Errorf("bold run = %+v", got)
+	}
+	if len(zh.Content[1]) != 0 {
+		t.Errorf("expected blank paragraph, got %+v", zh.Content[1])
+	}
+	for _, line := range zh.Content {
+		for _, el := range line {
+			if el.Tag == "" {
+				t.Errorf("element without tag in %s", raw)
+			}
+			if el.Tag == "text" && el.Text == "" {
+				t.Errorf("empty text element in %s", raw)
+			}
+		}
+	}
+	if got := zh.Content[2][1]; got.Tag != "a" || got.Href != "https://example.com" {
+		t.Errorf("link run = %+v", got)
+	}
+}
@@ -7,6 +7,7 @@ toolchain go1.24.12
 require (
 	github.com/joho/godotenv v1.5.1
 	github.com/larksuite/oapi-sdk-go/v3 v3.5.3
+	github.com/lib/pq v1.10.9
 	modernc.org/sqlite v1.44.3
 )
 
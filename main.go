@@ -10,9 +10,11 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/anthropics/feishu-codex-bridge/bridge"
 	"github.com/joho/godotenv"
@@ -67,7 +69,10 @@ func main() {
 	_, envStatErr := os.Stat(defaultEnvPath)
 	envMissing := os.IsNotExist(envStatErr)
 	if envMissing {
-		if err := os.WriteFile(defaultEnvPath, []byte(envExample), 0o600); err != nil {
+		envContent := renderEnvTemplate(envExample, map[string]string{
+			"SESSION_DB_PATH": defaultSessionDBPath(configDir, homeDir),
+		})
+		if err := os.WriteFile(defaultEnvPath, []byte(envContent), 0o600); err != nil {
 			log.Fatalf("Failed to write default env file %s: %v", defaultEnvPath, err)
 		}
 		fmt.Printf("Created default config: %s (please edit it). You can also create <workdir>/.feishu-codex-bridge/.env to override per project.\n", defaultEnvPath)
@@ -113,8 +118,19 @@ func main() {
 		applyEnvFile(perProjectEnvPath, true)
 	}
 
+	// FEISHU_APP_ID_FILE / FEISHU_APP_SECRET_FILE point at secrets files
+	// (e.g. Docker or systemd credentials).
+	feishuAppID, err := resolveSecretFile("FEISHU_APP_ID", envPreexisting)
+	if err != nil {
+		log.Fatalf("Failed to load FEISHU_APP_ID: %v", err)
+	}
+	feishuAppSecret, err := resolveSecretFile("FEISHU_APP_SECRET", envPreexisting)
+	if err != nil {
+		log.Fatalf("Failed to load FEISHU_APP_SECRET: %v", err)
+	}
+
 	// If required secrets are missing, exit early (do not start Codex).
-	if os.Getenv("FEISHU_APP_ID") == "" || os.Getenv("FEISHU_APP_SECRET") == "" {
+	if feishuAppID == "" || feishuAppSecret == "" {
 		if envMissing {
 			fmt.Printf("Missing required config. Please edit %s and set FEISHU_APP_ID and FEISHU_APP_SECRET, then re-run.\n", defaultEnvPath)
 			fmt.Printf("Optional per-project override: %s\n", perProjectEnvPath)
@@ -143,28 +159,227 @@ func main() {
 	// Session DB path
 	sessionDBPath := os.Getenv("SESSION_DB_PATH")
 	if sessionDBPath == "" {
-		newDefault := filepath.Join(configDir, "sessions.db")
-		legacyDefault := filepath.Join(homeDir, ".feishu-codex", "sessions.db")
+		sessionDBPath = defaultSessionDBPath(configDir, homeDir)
+	}
 
-		if _, err := os.Stat(newDefault); err == nil {
-			sessionDBPath = newDefault
-		} else if _, err := os.Stat(legacyDefault); err == nil {
-			sessionDBPath = legacyDefault
-		} else {
-			sessionDBPath = newDefault
+	idleWarnMin := 0 // 0 disables the idle warning
+	if val := os.Getenv("SESSION_IDLE_WARN_MINUTES"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			idleWarnMin = parsed
+		}
+	}
+
+	approvalTimeout := 0 * time.Second // 0 means the bridge default
+	if val := os.Getenv("APPROVAL_TIMEOUT_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			approvalTimeout = time.Duration(parsed) * time.Second
+		}
+	}
+
+	maxResponseChars := 0 // 0 means no limit
+	if val := os.Getenv("MAX_RESPONSE_CHARS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			maxResponseChars = parsed
+		}
+	}
+
+	var maxAttachmentBytes int64 // 0 means the feishu default
+	if val := os.Getenv("MAX_ATTACHMENT_MB"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			maxAttachmentBytes = int64(parsed) << 20
+		}
+	}
+
+	var codexInitTimeout time.Duration // 0 means the codex default (30s)
+	if val := os.Getenv("CODEX_INIT_TIMEOUT_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			codexInitTimeout = time.Duration(parsed) * time.Second
+		}
+	}
+
+	var codexKeepAlive time.Duration // 0 disables the keep-alive ping
+	if val := os.Getenv("CODEX_KEEPALIVE_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed > 0 {
+			codexKeepAlive = time.Duration(parsed) * time.Second
+		}
+	}
+
+	codexOverrides, err := loadCodexConfigOverrides(os.Getenv("CODEX_CONFIG_OVERRIDES"), os.Getenv("CODEX_CONFIG_OVERRIDES_FILE"))
+	if err != nil {
+		log.Fatalf("Invalid codex config overrides: %v", err)
+	}
+
+	var chatInfoCacheTTL time.Duration // 0 means the bridge default (10m)
+	if val := os.Getenv("CHAT_INFO_CACHE_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			chatInfoCacheTTL = time.Duration(parsed) * time.Second
+			if parsed == 0 {
+				chatInfoCacheTTL = -1 // 0 disables caching
+			}
+		}
+	}
+
+	maxMessageAttempts := 1 // 1 means no retries
+	if val := os.Getenv("MAX_MESSAGE_ATTEMPTS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			maxMessageAttempts = parsed
+		}
+	}
+
+	maxPromptChars := 0 // 0 means unlimited
+	if val := os.Getenv("MAX_PROMPT_CHARS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			maxPromptChars = parsed
+		}
+	}
+
+	maxImagesPerMessage := 0 // 0 means unlimited
+	if val := os.Getenv("MAX_IMAGES_PER_MESSAGE"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			maxImagesPerMessage = parsed
+		}
+	}
+
+	progressInterval := 0 * time.Second // 0 disables progress updates
+	if val := os.Getenv("PROGRESS_INTERVAL_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			progressInterval = time.Duration(parsed) * time.Second
+		}
+	}
+
+	commandCooldown := 2 * time.Second // 0 disables the cooldown
+	if val := os.Getenv("COMMAND_COOLDOWN_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			commandCooldown = time.Duration(parsed) * time.Second
+		}
+	}
+
+	resetCooldown := 30 * time.Second
+	if val := os.Getenv("RESET_COOLDOWN_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			resetCooldown = time.Duration(parsed) * time.Second
+		}
+	}
+
+	maxConcurrentTurns := 0 // 0 means unlimited
+	if val := os.Getenv("MAX_CONCURRENT_TURNS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			maxConcurrentTurns = parsed
+		}
+	}
+
+	var adminOpenIDs []string
+	if val := os.Getenv("ADMIN_OPEN_IDS"); val != "" {
+		for _, id := range strings.Split(val, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				adminOpenIDs = append(adminOpenIDs, id)
+			}
+		}
+	}
+
+	var allowedDirs []string
+	if val := os.Getenv("ALLOWED_DIRS"); val != "" {
+		for _, dir := range strings.Split(val, ",") {
+			if dir = strings.TrimSpace(dir); dir != "" {
+				allowedDirs = append(allowedDirs, dir)
+			}
+		}
+	}
+
+	approvalPolicy := os.Getenv("APPROVAL_POLICY")
+	switch approvalPolicy {
+	case "", bridge.ApprovalPolicyAuto, bridge.ApprovalPolicyAsk, bridge.ApprovalPolicyReadOnly:
+	default:
+		log.Fatalf("Invalid APPROVAL_POLICY %q: want auto, ask or readonly", approvalPolicy)
+	}
+
+	interruptedOutput := os.Getenv("INTERRUPTED_OUTPUT")
+	switch interruptedOutput {
+	case "", bridge.InterruptedOutputKeep, bridge.InterruptedOutputDiscard:
+	default:
+		log.Fatalf("Invalid INTERRUPTED_OUTPUT %q: want keep or discard", interruptedOutput)
+	}
+
+	workspaces, err := bridge.ParseWorkspaces(os.Getenv("WORKSPACES"))
+	if err != nil {
+		log.Fatalf("Invalid WORKSPACES: %v", err)
+	}
+
+	var acceptedMsgTypes []string
+	if val := os.Getenv("ACCEPTED_MSG_TYPES"); val != "" {
+		for _, t := range strings.Split(val, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				acceptedMsgTypes = append(acceptedMsgTypes, t)
+			}
 		}
 	}
 
 	config := bridge.Config{
-		FeishuAppID:     os.Getenv("FEISHU_APP_ID"),
-		FeishuAppSecret: os.Getenv("FEISHU_APP_SECRET"),
-		WorkingDir:      os.Getenv("WORKING_DIR"),
-		CodexModel:      os.Getenv("CODEX_MODEL"),
-		SessionDBPath:   sessionDBPath,
-		SessionIdleMin:  sessionIdleMin,
-		SessionResetHr:  sessionResetHr,
-		Debug:           os.Getenv("DEBUG") == "true",
+		FeishuAppID:        feishuAppID,
+		FeishuAppSecret:    feishuAppSecret,
+		FeishuBotOpenID:    os.Getenv("FEISHU_BOT_OPEN_ID"),
+		WorkingDir:         os.Getenv("WORKING_DIR"),
+		CodexModel:         os.Getenv("CODEX_MODEL"),
+		CodexModelProvider: os.Getenv("CODEX_MODEL_PROVIDER"),
+		SessionDBPath:      sessionDBPath,
+		SessionIdleMin:     sessionIdleMin,
+		SessionResetHr:     sessionResetHr,
+		IdleWarnMinutes:    idleWarnMin,
+		Debug:              os.Getenv("DEBUG") == "true",
+		ProcessingEmoji:    os.Getenv("PROCESSING_EMOJI"),
+		CommandPrefix:      os.Getenv("COMMAND_PREFIX"),
+		AdminOpenIDs:       adminOpenIDs,
+		AcceptedMsgTypes:   acceptedMsgTypes,
+		ApprovalPolicy:     approvalPolicy,
+		ApprovalTimeout:    approvalTimeout,
+		GreetOnNewThread:   os.Getenv("GREET_ON_NEW_THREAD") == "true",
+		SendImages:         os.Getenv("SEND_IMAGES") == "true",
+		MaxResponseChars:   maxResponseChars,
+		ParallelAsk:        os.Getenv("PARALLEL_ASK") == "true",
+		AckQueued:          os.Getenv("ACK_QUEUED") == "true",
+		ClearOnDirMismatch: os.Getenv("CLEAR_ON_DIR_MISMATCH") == "true",
+		LiveCommandOutput:  os.Getenv("LIVE_COMMAND_OUTPUT") == "true",
+		PlainTextOnly:      os.Getenv("PLAIN_TEXT_ONLY") == "true",
+		WebhookAddr:        os.Getenv("WEBHOOK_ADDR"),
+		WebhookSecret:      os.Getenv("WEBHOOK_SECRET"),
+		AdminAPIToken:      os.Getenv("ADMIN_API_TOKEN"),
+	}
+
+	config.GroupCommandsRequireMention = os.Getenv("GROUP_COMMANDS_REQUIRE_MENTION") == "true"
+	config.QuotePromptInReply = os.Getenv("QUOTE_PROMPT_IN_REPLY") == "true"
+	config.InMemorySessions = os.Getenv("IN_MEMORY_SESSIONS") == "true"
+	config.SessionDBDSN = os.Getenv("SESSION_DB_DSN")
+	config.MaxAttachmentBytes = maxAttachmentBytes
+	config.MaxImagesPerMessage = maxImagesPerMessage
+	config.ImagePrompt = os.Getenv("IMAGE_ONLY_PROMPT")
+	config.MaxPromptChars = maxPromptChars
+	config.LongPromptPolicy = os.Getenv("LONG_PROMPT_POLICY")
+	config.InterruptedOutput = interruptedOutput
+	config.MaxMessageAttempts = maxMessageAttempts
+	config.CodexInitTimeout = codexInitTimeout
+	config.CodexKeepAliveInterval = codexKeepAlive
+	config.CodexConfigOverrides = codexOverrides
+	config.ChatInfoCacheTTL = chatInfoCacheTTL
+	config.DisableNetwork = os.Getenv("ALLOW_NETWORK") == "false"
+	config.CleanupImages = os.Getenv("CLEANUP_IMAGES") != "false"
+	config.CodexDownPolicy = os.Getenv("CODEX_DOWN_POLICY")
+	if config.CodexDownPolicy == "" {
+		config.CodexDownPolicy = bridge.CodexDownReject
 	}
+	config.WarmupPolicy = os.Getenv("CODEX_WARMUP_POLICY")
+	config.ProgressInterval = progressInterval
+	config.CommandCooldown = commandCooldown
+	config.ResetCooldown = resetCooldown
+	config.Language = os.Getenv("BRIDGE_LANGUAGE")
+	config.WelcomeOnJoin = os.Getenv("WELCOME_ON_JOIN") == "true"
+	config.MaxConcurrentTurns = maxConcurrentTurns
+	config.AllowedDirs = allowedDirs
+	config.Workspaces = workspaces
+	config.VerboseP2P = os.Getenv("VERBOSE_P2P") != "false"
+	config.VerboseGroup = os.Getenv("VERBOSE_GROUP") == "true"
+	config.MessagesFile = os.Getenv("MESSAGES_FILE")
+	config.AuditChatID = os.Getenv("AUDIT_CHAT_ID")
+	config.IncludeSenderContext = os.Getenv("INCLUDE_SENDER_CONTEXT") == "true"
 
 	if config.FeishuAppID == "" || config.FeishuAppSecret == "" {
 		log.Fatal("FEISHU_APP_ID and FEISHU_APP_SECRET are required")
@@ -215,3 +430,18 @@ func main() {
 		log.Printf("Bridge stopped: %v", err)
 	}
 }
+
+// defaultSessionDBPath returns <configDir>/sessions.db, or the legacy
+// ~/.feishu-codex/sessions.db if only that one exists.
+func defaultSessionDBPath(configDir, homeDir string) string {
+	newDefault := filepath.Join(configDir, "sessions.db")
+	legacyDefault := filepath.Join(homeDir, ".feishu-codex", "sessions.db")
+
+	if _, err := os.Stat(newDefault); err == nil {
+		return newDefault
+	}
+	if _, err := os.Stat(legacyDefault); err == nil {
+		return legacyDefault
+	}
+	return newDefault
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anthropics/feishu-codex-bridge/codex"
+)
+
+// loadCodexConfigOverrides collects the extra codex -c overrides from
+// CODEX_CONFIG_OVERRIDES (comma-separated; see splitOverrideList) followed by
+// the file named by CODEX_CONFIG_OVERRIDES_FILE (one per line; blank lines
+// and lines starting with # are skipped). Every entry is validated; the
+// first bad one is an error.
+func loadCodexConfigOverrides(list, path string) ([]string, error) {
+	var entries []string
+	for _, kv := range splitOverrideList(list) {
+		if kv = strings.TrimSpace(kv); kv != "" {
+			entries = append(entries, kv)
+		}
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read CODEX_CONFIG_OVERRIDES_FILE: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				entries = append(entries, line)
+			}
+		}
+	}
+
+	overrides := make([]string, 0, len(entries))
+	for _, kv := range entries {
+		override, err := codex.ParseConfigOverride(kv)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, nil
+}
+
+// splitOverrideList splits list on the commas that are outside brackets,
+// braces and quotes, so array and inline-table values such as
+// sandbox_permissions=["a","b"] stay in one entry.
+func splitOverrideList(list string) []string {
+	var parts []string
+	var quote rune
+	depth, start := 0, 0
+	for i, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{':
+			depth++
+		case (r == ']' || r == '}') && depth > 0:
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, list[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, list[start:])
+}
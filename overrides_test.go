@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCodexConfigOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides")
+	content := "# reasoning\nmodel_reasoning_summary = detailed\n\nsandbox_permissions=[\"disk-full-read-access\",\"network-full-access\"]\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadCodexConfigOverrides("model_reasoning_effort=high, tools.web_search=true,", path)
+	if err != nil {
+		t.Fatalf("loadCodexConfigOverrides: %v", err)
+	}
+	want := []string{
+		"model_reasoning_effort=high",
+		"tools.web_search=true",
+		"model_reasoning_summary=detailed",
+		`sandbox_permissions=["disk-full-read-access","network-full-access"]`,
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got, err = loadCodexConfigOverrides(`sandbox_permissions=["a","b"], shell_environment_policy={include_only=["PATH","HOME"]},note="x, y"`, "")
+	if err != nil {
+		t.Fatalf("loadCodexConfigOverrides: %v", err)
+	}
+	want = []string{
+		`sandbox_permissions=["a","b"]`,
+		`shell_environment_policy={include_only=["PATH","HOME"]}`,
+		`note="x, y"`,
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := loadCodexConfigOverrides("model=o3,--yolo", ""); err == nil {
+		t.Error("expected an error for an entry that isn't key=value")
+	}
+	if _, err := loadCodexConfigOverrides("", filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if got, err := loadCodexConfigOverrides("", ""); err != nil || len(got) != 0 {
+		t.Errorf("expected no overrides, got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// resolveSecretFile returns the value of a secret, applying the <name>_FILE
+// indirection: when <name> was not exported before the process started, the
+// trimmed contents of the file named by <name>_FILE replace whatever a .env
+// file set. Precedence is therefore real env var > file > .env. The secret
+// is not written back to the environment, so codex and other child
+// processes never inherit it.
+func resolveSecretFile(name string, envPreexisting map[string]struct{}) (string, error) {
+	if _, ok := envPreexisting[name]; ok && os.Getenv(name) != "" {
+		return os.Getenv(name), nil
+	}
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return os.Getenv(name), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s_FILE: %w", name, err)
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("%s_FILE %s is empty", name, path)
+	}
+	return value, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A value loaded from .env is replaced by the file.
+	t.Setenv("TEST_APP_SECRET", "from-dotenv")
+	t.Setenv("TEST_APP_SECRET_FILE", path)
+	got, err := resolveSecretFile("TEST_APP_SECRET", map[string]struct{}{})
+	if err != nil {
+		t.Fatalf("resolveSecretFile: %v", err)
+	}
+	if got != "from-file" {
+		t.Fatalf("resolveSecretFile = %q, want from-file", got)
+	}
+	if env := os.Getenv("TEST_APP_SECRET"); env != "from-dotenv" {
+		t.Fatalf("TEST_APP_SECRET = %q, want the environment left alone", env)
+	}
+
+	// A real environment variable wins over the file.
+	t.Setenv("TEST_APP_SECRET", "from-env")
+	pre := map[string]struct{}{"TEST_APP_SECRET": {}}
+	if got, err := resolveSecretFile("TEST_APP_SECRET", pre); err != nil || got != "from-env" {
+		t.Fatalf("resolveSecretFile = %q, %v; want from-env", got, err)
+	}
+
+	// A missing file is an error.
+	t.Setenv("TEST_APP_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+	if _, err := resolveSecretFile("TEST_APP_SECRET", map[string]struct{}{}); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
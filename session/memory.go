@@ -0,0 +1,269 @@
+package session
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store that keeps everything in memory, for tests and for
+// deployments that should forget sessions on restart.
+type MemoryStore struct {
+	expiry
+
+	mu               sync.Mutex
+	sessions         map[string]Entry
+	lastVersion      int64 // store-wide version counter; see Entry.Version
+	paused           map[string]time.Time
+	languages        map[string]string
+	models           map[string]string
+	streamModes      map[string]string
+	efforts          map[string]string
+	approvalPolicies map[string]string
+}
+
+// NewMemoryStore creates an empty in-memory session store; idleMinutes and
+// resetHour behave as for NewStore.
+func NewMemoryStore(idleMinutes, resetHour int) (*MemoryStore, error) {
+	exp, err := newExpiry(idleMinutes, resetHour)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryStore{
+		expiry:           exp,
+		sessions:         make(map[string]Entry),
+		paused:           make(map[string]time.Time),
+		languages:        make(map[string]string),
+		models:           make(map[string]string),
+		streamModes:      make(map[string]string),
+		efforts:          make(map[string]string),
+		approvalPolicies: make(map[string]string),
+	}, nil
+}
+
+// Close is a no-op; the store stays usable.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// GetByChatID retrieves a session by Feishu chat ID
+func (s *MemoryStore) GetByChatID(chatID string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.sessions[chatID]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// Create creates or replaces the session entry for chatID with a new
+// version
+func (s *MemoryStore) Create(chatID, threadID string) (*Entry, error) {
+	now := time.Now()
+	entry := Entry{
+		ChatID:    chatID,
+		ThreadID:  threadID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.mu.Lock()
+	s.lastVersion++
+	entry.Version = s.lastVersion
+	s.sessions[chatID] = entry
+	s.mu.Unlock()
+	return &entry, nil
+}
+
+// Update switches the session read as entry to threadID, or returns
+// ErrConflict if it changed since; see SQLiteStore.Update.
+func (s *MemoryStore) Update(entry *Entry, threadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.sessions[entry.ChatID]
+	if !ok || stored.Version != entry.Version {
+		return ErrConflict
+	}
+	stored.ThreadID = threadID
+	stored.UpdatedAt = time.Now()
+	s.lastVersion++
+	stored.Version = s.lastVersion
+	s.sessions[entry.ChatID] = stored
+	*entry = stored
+	return nil
+}
+
+// Touch updates the timestamp for a session (to track activity), leaving
+// its version alone
+func (s *MemoryStore) Touch(chatID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.sessions[chatID]; ok {
+		entry.UpdatedAt = time.Now()
+		s.sessions[chatID] = entry
+	}
+	return nil
+}
+
+// Delete removes a session
+func (s *MemoryStore) Delete(chatID string) error {
+	s.mu.Lock()
+	delete(s.sessions, chatID)
+	s.mu.Unlock()
+	return nil
+}
+
+// CleanupStale removes sessions past the idle timeout. It does nothing when
+// the idle timeout is disabled; the daily reset is enforced by IsFresh.
+func (s *MemoryStore) CleanupStale() (int64, error) {
+	if s.idleMinutes <= 0 {
+		return 0, nil
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var removed int64
+	for chatID, entry := range s.sessions {
+		if s.idleExpired(&entry, now) {
+			delete(s.sessions, chatID)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// SetPaused records whether message processing is paused for a chat.
+func (s *MemoryStore) SetPaused(chatID string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if paused {
+		s.paused[chatID] = time.Now()
+	} else {
+		delete(s.paused, chatID)
+	}
+	return nil
+}
+
+// PausedChats returns the IDs of all paused chats.
+func (s *MemoryStore) PausedChats() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var chatIDs []string
+	for chatID := range s.paused {
+		chatIDs = append(chatIDs, chatID)
+	}
+	sort.Slice(chatIDs, func(i, j int) bool {
+		return s.paused[chatIDs[i]].Before(s.paused[chatIDs[j]])
+	})
+	return chatIDs, nil
+}
+
+// SetLanguage records a chat's language; an empty language clears it.
+func (s *MemoryStore) SetLanguage(chatID, language string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	setOrClear(s.languages, chatID, language)
+	return nil
+}
+
+// ChatLanguages returns the language of every chat that has one set.
+func (s *MemoryStore) ChatLanguages() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyMap(s.languages), nil
+}
+
+// SetModel records the model a chat uses for new threads; an empty model
+// clears it.
+func (s *MemoryStore) SetModel(chatID, model string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	setOrClear(s.models, chatID, model)
+	return nil
+}
+
+// ChatModels returns the model of every chat that has one set.
+func (s *MemoryStore) ChatModels() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyMap(s.models), nil
+}
+
+// SetStreamMode records the /stream mode of a chat; an empty mode
+// clears it.
+func (s *MemoryStore) SetStreamMode(chatID, mode string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	setOrClear(s.streamModes, chatID, mode)
+	return nil
+}
+
+// ChatStreamModes returns the stream mode of every chat that has one set.
+func (s *MemoryStore) ChatStreamModes() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyMap(s.streamModes), nil
+}
+
+// SetEffort records the reasoning effort of a chat; an empty effort
+// clears it.
+func (s *MemoryStore) SetEffort(chatID, effort string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	setOrClear(s.efforts, chatID, effort)
+	return nil
+}
+
+// ChatEfforts returns the reasoning effort of every chat that has one set.
+func (s *MemoryStore) ChatEfforts() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyMap(s.efforts), nil
+}
+
+// SetApprovalPolicy records the /approvals policy of a chat; an empty policy
+// clears it.
+func (s *MemoryStore) SetApprovalPolicy(chatID, policy string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	setOrClear(s.approvalPolicies, chatID, policy)
+	return nil
+}
+
+// ChatApprovalPolicies returns the approval policy of every chat that has one set.
+func (s *MemoryStore) ChatApprovalPolicies() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyMap(s.approvalPolicies), nil
+}
+
+// ListAll returns all sessions, most recently used first
+func (s *MemoryStore) ListAll() ([]*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []*Entry
+	for _, entry := range s.sessions {
+		entry := entry
+		entries = append(entries, &entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].UpdatedAt.After(entries[j].UpdatedAt)
+	})
+	return entries, nil
+}
+
+func setOrClear(m map[string]string, key, value string) {
+	if value != "" {
+		m[key] = value
+	} else {
+		delete(m, key)
+	}
+}
+
+func copyMap(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
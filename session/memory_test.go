@@ -0,0 +1,173 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_CreateGetDelete(t *testing.T) {
+	store, err := NewMemoryStore(60, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if entry, err := store.GetByChatID("oc_1"); err != nil || entry != nil {
+		t.Fatalf("GetByChatID on empty store = %v, %v; want nil, nil", entry, err)
+	}
+
+	if _, err := store.Create("oc_1", "thread-1"); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	entry, err := store.GetByChatID("oc_1")
+	if err != nil || entry == nil || entry.ThreadID != "thread-1" {
+		t.Fatalf("GetByChatID = %+v, %v; want thread-1", entry, err)
+	}
+
+	// Returned entries are copies.
+	entry.ThreadID = "mutated"
+	if again, _ := store.GetByChatID("oc_1"); again.ThreadID != "thread-1" {
+		t.Errorf("stored entry changed through returned pointer: %q", again.ThreadID)
+	}
+
+	entry, _ = store.GetByChatID("oc_1")
+	if err := store.Update(entry, "thread-2"); err != nil {
+		t.Fatalf("Failed to update session: %v", err)
+	}
+	if entry, _ := store.GetByChatID("oc_1"); entry.ThreadID != "thread-2" {
+		t.Errorf("ThreadID after Update = %q, want thread-2", entry.ThreadID)
+	}
+
+	if err := store.Delete("oc_1"); err != nil {
+		t.Fatalf("Failed to delete session: %v", err)
+	}
+	if entry, _ := store.GetByChatID("oc_1"); entry != nil {
+		t.Errorf("session still present after Delete: %+v", entry)
+	}
+}
+
+func TestMemoryStore_UpdateConflict(t *testing.T) {
+	store, err := NewMemoryStore(60, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	store.Create("oc_1", "thread-1")
+	entry, _ := store.GetByChatID("oc_1")
+	stale := *entry
+	store.Touch("oc_1")
+	if err := store.Update(entry, "thread-2"); err != nil {
+		t.Fatalf("Update after Touch: %v", err)
+	}
+	if err := store.Update(&stale, "thread-3"); err != ErrConflict {
+		t.Fatalf("Update with a stale entry = %v, want ErrConflict", err)
+	}
+
+	// Replaced by Create.
+	read, _ := store.GetByChatID("oc_1")
+	store.Create("oc_1", "thread-4")
+	if err := store.Update(read, "thread-5"); err != ErrConflict {
+		t.Fatalf("Update after Create = %v, want ErrConflict", err)
+	}
+
+	// Deleted, then created again.
+	read, _ = store.GetByChatID("oc_1")
+	store.Delete("oc_1")
+	if err := store.Update(read, "thread-6"); err != ErrConflict {
+		t.Fatalf("Update of a deleted session = %v, want ErrConflict", err)
+	}
+	store.Create("oc_1", "thread-7")
+	if err := store.Update(read, "thread-6"); err != ErrConflict {
+		t.Fatalf("Update after Delete and Create = %v, want ErrConflict", err)
+	}
+	if entry, _ := store.GetByChatID("oc_1"); entry.ThreadID != "thread-7" {
+		t.Errorf("ThreadID = %s, want thread-7 kept", entry.ThreadID)
+	}
+}
+
+func TestMemoryStore_TouchAndCleanupStale(t *testing.T) {
+	store, err := NewMemoryStore(60, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	store.Create("oc_old", "thread-old")
+	store.Create("oc_new", "thread-new")
+
+	old := store.sessions["oc_old"]
+	old.UpdatedAt = time.Now().Add(-2 * time.Hour)
+	store.sessions["oc_old"] = old
+	if entry, _ := store.GetByChatID("oc_old"); store.IsFresh(entry) {
+		t.Error("session idle for 2h should not be fresh")
+	}
+
+	removed, err := store.CleanupStale()
+	if err != nil || removed != 1 {
+		t.Fatalf("CleanupStale = %d, %v; want 1, nil", removed, err)
+	}
+	entries, _ := store.ListAll()
+	if len(entries) != 1 || entries[0].ChatID != "oc_new" {
+		t.Errorf("sessions after cleanup = %+v, want only oc_new", entries)
+	}
+
+	before := store.sessions["oc_new"].UpdatedAt
+	time.Sleep(10 * time.Millisecond)
+	store.Touch("oc_new")
+	if !store.sessions["oc_new"].UpdatedAt.After(before) {
+		t.Error("Touch did not advance UpdatedAt")
+	}
+}
+
+func TestMemoryStore_ChatSettings(t *testing.T) {
+	store, err := NewMemoryStore(0, ResetHourDisabled)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	store.SetPaused("oc_1", true)
+	store.SetPaused("oc_2", true)
+	store.SetPaused("oc_1", false)
+	if paused, _ := store.PausedChats(); len(paused) != 1 || paused[0] != "oc_2" {
+		t.Errorf("PausedChats = %v, want [oc_2]", paused)
+	}
+
+	store.SetLanguage("oc_1", "en")
+	store.SetModel("oc_1", "gpt-5")
+	store.SetStreamMode("oc_1", "stream")
+	store.SetEffort("oc_1", "low")
+	store.SetApprovalPolicy("oc_1", "readonly")
+	langs, _ := store.ChatLanguages()
+	langs["oc_1"] = "zh"
+	if langs, _ := store.ChatLanguages(); langs["oc_1"] != "en" {
+		t.Errorf("ChatLanguages = %v, want oc_1=en", langs)
+	}
+	if models, _ := store.ChatModels(); models["oc_1"] != "gpt-5" {
+		t.Errorf("ChatModels = %v, want oc_1=gpt-5", models)
+	}
+
+	if modes, _ := store.ChatStreamModes(); modes["oc_1"] != "stream" {
+		t.Errorf("ChatStreamModes = %v, want oc_1=stream", modes)
+	}
+	if efforts, _ := store.ChatEfforts(); efforts["oc_1"] != "low" {
+		t.Errorf("ChatEfforts = %v, want oc_1=low", efforts)
+	}
+	if policies, _ := store.ChatApprovalPolicies(); policies["oc_1"] != "readonly" {
+		t.Errorf("ChatApprovalPolicies = %v, want oc_1=readonly", policies)
+	}
+
+	store.SetLanguage("oc_1", "")
+	store.SetModel("oc_1", "")
+	if langs, _ := store.ChatLanguages(); len(langs) != 0 {
+		t.Errorf("ChatLanguages after clear = %v, want empty", langs)
+	}
+	if models, _ := store.ChatModels(); len(models) != 0 {
+		t.Errorf("ChatModels after clear = %v, want empty", models)
+	}
+}
+
+func TestNewMemoryStore_InvalidExpiry(t *testing.T) {
+	if _, err := NewMemoryStore(-1, ResetHourDisabled); err == nil {
+		t.Error("expected error for negative idle minutes")
+	}
+	if _, err := NewMemoryStore(60, 24); err == nil {
+		t.Error("expected error for reset hour 24")
+	}
+}
@@ -0,0 +1,438 @@
+package session
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresStore manages session persistence in PostgreSQL, so several
+// bridge instances can share sessions. It uses the same tables and
+// semantics as SQLiteStore.
+type PostgresStore struct {
+	expiry
+	db *sql.DB
+}
+
+// postgresMigrations create the schema on startup; each statement must be
+// safe to re-run.
+var postgresMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS sessions (
+		chat_id TEXT PRIMARY KEY,
+		thread_id TEXT NOT NULL,
+		created_at BIGINT NOT NULL,
+		updated_at BIGINT NOT NULL
+	)`,
+	`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0`,
+	`CREATE INDEX IF NOT EXISTS idx_sessions_updated_at ON sessions(updated_at)`,
+	// session_versions is the store-wide version counter (see
+	// Entry.Version), started past the versions already stored.
+	`CREATE SEQUENCE IF NOT EXISTS session_versions`,
+	`SELECT setval('session_versions', GREATEST(
+		(SELECT COALESCE(MAX(version), 0) FROM sessions),
+		(SELECT last_value FROM session_versions)
+	))`,
+	`CREATE TABLE IF NOT EXISTS paused_chats (
+		chat_id TEXT PRIMARY KEY,
+		paused_at BIGINT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS chat_languages (
+		chat_id TEXT PRIMARY KEY,
+		language TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS chat_models (
+		chat_id TEXT PRIMARY KEY,
+		model TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS chat_stream_modes (
+		chat_id TEXT PRIMARY KEY,
+		stream_mode TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS chat_efforts (
+		chat_id TEXT PRIMARY KEY,
+		effort TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS chat_approval_policies (
+		chat_id TEXT PRIMARY KEY,
+		policy TEXT NOT NULL
+	)`,
+}
+
+// postgresMigrationLock is the advisory lock key held while migrating, so
+// bridge instances starting together don't race on the same DDL.
+const postgresMigrationLock int64 = 0x66636272 // "fcbr"
+
+// NewPostgresStore connects to the database at dsn (a lib/pq connection
+// string or postgres:// URL) and migrates its schema; idleMinutes and
+// resetHour behave as for NewStore.
+func NewPostgresStore(dsn string, idleMinutes, resetHour int) (*PostgresStore, error) {
+	exp, err := newExpiry(idleMinutes, resetHour)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := migratePostgres(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return &PostgresStore{
+		expiry: exp,
+		db:     db,
+	}, nil
+}
+
+// migratePostgres runs postgresMigrations in one transaction under
+// postgresMigrationLock; the lock is released when the transaction ends.
+func migratePostgres(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, postgresMigrationLock); err != nil {
+		return err
+	}
+	for _, stmt := range postgresMigrations {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Close closes the database connection
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+// GetByChatID retrieves a session by Feishu chat ID
+func (s *PostgresStore) GetByChatID(chatID string) (*Entry, error) {
+	row := s.db.QueryRow(`
+		SELECT chat_id, thread_id, created_at, updated_at, version
+		FROM sessions
+		WHERE chat_id = $1
+	`, chatID)
+
+	var entry Entry
+	var createdAt, updatedAt int64
+	err := row.Scan(&entry.ChatID, &entry.ThreadID, &createdAt, &updatedAt, &entry.Version)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session: %w", err)
+	}
+
+	entry.CreatedAt = time.Unix(createdAt, 0)
+	entry.UpdatedAt = time.Unix(updatedAt, 0)
+
+	return &entry, nil
+}
+
+// Create creates or replaces the session entry for chatID, bumping the
+// version of a replaced one
+func (s *PostgresStore) Create(chatID, threadID string) (*Entry, error) {
+	now := time.Now()
+	nowUnix := now.Unix()
+
+	var version int64
+	err := s.db.QueryRow(`
+		INSERT INTO sessions (chat_id, thread_id, created_at, updated_at, version)
+		VALUES ($1, $2, $3, $3, nextval('session_versions'))
+		ON CONFLICT (chat_id) DO UPDATE
+		SET thread_id = EXCLUDED.thread_id,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at,
+			version = EXCLUDED.version
+		RETURNING version
+	`, chatID, threadID, nowUnix).Scan(&version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return &Entry{
+		ChatID:    chatID,
+		ThreadID:  threadID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   version,
+	}, nil
+}
+
+// Update switches the session read as entry to threadID, or returns
+// ErrConflict if it changed since; see SQLiteStore.Update.
+func (s *PostgresStore) Update(entry *Entry, threadID string) error {
+	now := time.Now()
+
+	var version int64
+	err := s.db.QueryRow(`
+		UPDATE sessions
+		SET thread_id = $1, updated_at = $2, version = nextval('session_versions')
+		WHERE chat_id = $3 AND version = $4
+		RETURNING version
+	`, threadID, now.Unix(), entry.ChatID, entry.Version).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	entry.ThreadID = threadID
+	entry.UpdatedAt = now
+	entry.Version = version
+	return nil
+}
+
+// Touch updates the timestamp for a session (to track activity), leaving
+// its version alone
+func (s *PostgresStore) Touch(chatID string) error {
+	_, err := s.db.Exec(`
+		UPDATE sessions
+		SET updated_at = $1
+		WHERE chat_id = $2
+	`, time.Now().Unix(), chatID)
+	if err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a session
+func (s *PostgresStore) Delete(chatID string) error {
+	_, err := s.db.Exec(`DELETE FROM sessions WHERE chat_id = $1`, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// CleanupStale removes sessions past the idle timeout. It does nothing when
+// the idle timeout is disabled; the daily reset is enforced by IsFresh.
+func (s *PostgresStore) CleanupStale() (int64, error) {
+	if s.idleMinutes <= 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().Add(-time.Duration(s.idleMinutes) * time.Minute).Unix()
+	result, err := s.db.Exec(`DELETE FROM sessions WHERE updated_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup stale sessions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// SetPaused records whether message processing is paused for a chat.
+func (s *PostgresStore) SetPaused(chatID string, paused bool) error {
+	var err error
+	if paused {
+		_, err = s.db.Exec(`
+			INSERT INTO paused_chats (chat_id, paused_at)
+			VALUES ($1, $2)
+			ON CONFLICT (chat_id) DO UPDATE SET paused_at = EXCLUDED.paused_at
+		`, chatID, time.Now().Unix())
+	} else {
+		_, err = s.db.Exec(`DELETE FROM paused_chats WHERE chat_id = $1`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set paused state: %w", err)
+	}
+	return nil
+}
+
+// PausedChats returns the IDs of all paused chats.
+func (s *PostgresStore) PausedChats() ([]string, error) {
+	rows, err := s.db.Query(`SELECT chat_id FROM paused_chats ORDER BY paused_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paused chats: %w", err)
+	}
+	defer rows.Close()
+
+	var chatIDs []string
+	for rows.Next() {
+		var chatID string
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("failed to scan paused chat: %w", err)
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+	return chatIDs, rows.Err()
+}
+
+// SetLanguage records a chat's language; an empty language clears it.
+func (s *PostgresStore) SetLanguage(chatID, language string) error {
+	var err error
+	if language != "" {
+		_, err = s.db.Exec(`
+			INSERT INTO chat_languages (chat_id, language)
+			VALUES ($1, $2)
+			ON CONFLICT (chat_id) DO UPDATE SET language = EXCLUDED.language
+		`, chatID, language)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_languages WHERE chat_id = $1`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat language: %w", err)
+	}
+	return nil
+}
+
+// ChatLanguages returns the language of every chat that has one set.
+func (s *PostgresStore) ChatLanguages() (map[string]string, error) {
+	return s.chatSettings(`SELECT chat_id, language FROM chat_languages`, "chat language")
+}
+
+// SetModel records the model a chat uses for new threads; an empty model
+// clears it.
+func (s *PostgresStore) SetModel(chatID, model string) error {
+	var err error
+	if model != "" {
+		_, err = s.db.Exec(`
+			INSERT INTO chat_models (chat_id, model)
+			VALUES ($1, $2)
+			ON CONFLICT (chat_id) DO UPDATE SET model = EXCLUDED.model
+		`, chatID, model)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_models WHERE chat_id = $1`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat model: %w", err)
+	}
+	return nil
+}
+
+// ChatModels returns the model of every chat that has one set.
+func (s *PostgresStore) ChatModels() (map[string]string, error) {
+	return s.chatSettings(`SELECT chat_id, model FROM chat_models`, "chat model")
+}
+
+// SetStreamMode records the /stream mode of a chat; an empty mode
+// clears it.
+func (s *PostgresStore) SetStreamMode(chatID, mode string) error {
+	var err error
+	if mode != "" {
+		_, err = s.db.Exec(`
+			INSERT INTO chat_stream_modes (chat_id, stream_mode)
+			VALUES ($1, $2)
+			ON CONFLICT (chat_id) DO UPDATE SET stream_mode = EXCLUDED.stream_mode
+		`, chatID, mode)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_stream_modes WHERE chat_id = $1`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat stream mode: %w", err)
+	}
+	return nil
+}
+
+// ChatStreamModes returns the stream mode of every chat that has one set.
+func (s *PostgresStore) ChatStreamModes() (map[string]string, error) {
+	return s.chatSettings(`SELECT chat_id, stream_mode FROM chat_stream_modes`, "chat stream mode")
+}
+
+// SetEffort records the reasoning effort of a chat; an empty effort
+// clears it.
+func (s *PostgresStore) SetEffort(chatID, effort string) error {
+	var err error
+	if effort != "" {
+		_, err = s.db.Exec(`
+			INSERT INTO chat_efforts (chat_id, effort)
+			VALUES ($1, $2)
+			ON CONFLICT (chat_id) DO UPDATE SET effort = EXCLUDED.effort
+		`, chatID, effort)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_efforts WHERE chat_id = $1`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat effort: %w", err)
+	}
+	return nil
+}
+
+// ChatEfforts returns the reasoning effort of every chat that has one set.
+func (s *PostgresStore) ChatEfforts() (map[string]string, error) {
+	return s.chatSettings(`SELECT chat_id, effort FROM chat_efforts`, "chat effort")
+}
+
+// SetApprovalPolicy records the /approvals policy of a chat; an empty policy
+// clears it.
+func (s *PostgresStore) SetApprovalPolicy(chatID, policy string) error {
+	var err error
+	if policy != "" {
+		_, err = s.db.Exec(`
+			INSERT INTO chat_approval_policies (chat_id, policy)
+			VALUES ($1, $2)
+			ON CONFLICT (chat_id) DO UPDATE SET policy = EXCLUDED.policy
+		`, chatID, policy)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_approval_policies WHERE chat_id = $1`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat approval policy: %w", err)
+	}
+	return nil
+}
+
+// ChatApprovalPolicies returns the approval policy of every chat that has one set.
+func (s *PostgresStore) ChatApprovalPolicies() (map[string]string, error) {
+	return s.chatSettings(`SELECT chat_id, policy FROM chat_approval_policies`, "chat approval policy")
+}
+
+// chatSettings reads a two-column chat_id -> value table.
+func (s *PostgresStore) chatSettings(query, what string) (map[string]string, error) {
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %ss: %w", what, err)
+	}
+	defer rows.Close()
+
+	settings := make(map[string]string)
+	for rows.Next() {
+		var chatID, value string
+		if err := rows.Scan(&chatID, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", what, err)
+		}
+		settings[chatID] = value
+	}
+	return settings, rows.Err()
+}
+
+// ListAll returns all sessions, most recently used first
+func (s *PostgresStore) ListAll() ([]*Entry, error) {
+	rows, err := s.db.Query(`
+		SELECT chat_id, thread_id, created_at, updated_at, version
+		FROM sessions
+		ORDER BY updated_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*Entry
+	for rows.Next() {
+		var entry Entry
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&entry.ChatID, &entry.ThreadID, &createdAt, &updatedAt, &entry.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		entry.CreatedAt = time.Unix(createdAt, 0)
+		entry.UpdatedAt = time.Unix(updatedAt, 0)
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,173 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// newTestPostgresStore connects to SESSION_TEST_DSN, skipping the test when
+// it is unset. Chat IDs from chatID are removed again on cleanup.
+func newTestPostgresStore(t *testing.T, idleMinutes int) (store *PostgresStore, chatID func(string) string) {
+	t.Helper()
+	dsn := os.Getenv("SESSION_TEST_DSN")
+	if dsn == "" {
+		t.Skip("SESSION_TEST_DSN not set")
+	}
+	store, err := NewPostgresStore(dsn, idleMinutes, ResetHourDisabled)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	prefix := fmt.Sprintf("test_%d_", time.Now().UnixNano())
+	t.Cleanup(func() {
+		for _, table := range []string{"sessions", "paused_chats", "chat_languages", "chat_models", "chat_stream_modes", "chat_efforts", "chat_approval_policies"} {
+			store.db.Exec(`DELETE FROM `+table+` WHERE chat_id LIKE $1`, prefix+"%")
+		}
+		store.Close()
+	})
+	return store, func(name string) string { return prefix + name }
+}
+
+func TestPostgresStore_Sessions(t *testing.T) {
+	store, chatID := newTestPostgresStore(t, 60)
+	c1 := chatID("c1")
+
+	if entry, err := store.GetByChatID(c1); err != nil || entry != nil {
+		t.Fatalf("GetByChatID before Create = %v, %v; want nil, nil", entry, err)
+	}
+	if _, err := store.Create(c1, "thread-1"); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	// Create replaces an existing session.
+	if _, err := store.Create(c1, "thread-2"); err != nil {
+		t.Fatalf("Failed to replace session: %v", err)
+	}
+	entry, err := store.GetByChatID(c1)
+	if err != nil || entry == nil || entry.ThreadID != "thread-2" {
+		t.Fatalf("GetByChatID = %+v, %v; want thread-2", entry, err)
+	}
+	if !store.IsFresh(entry) {
+		t.Error("new session should be fresh")
+	}
+
+	if err := store.Update(entry, "thread-3"); err != nil {
+		t.Fatalf("Failed to update session: %v", err)
+	}
+	// The entry read before the update is stale now.
+	if err := store.Update(&Entry{ChatID: c1, Version: entry.Version - 1}, "thread-4"); err != ErrConflict {
+		t.Errorf("Update with a stale entry = %v, want ErrConflict", err)
+	}
+	if err := store.Touch(c1); err != nil {
+		t.Fatalf("Failed to touch session: %v", err)
+	}
+	if entry, _ := store.GetByChatID(c1); entry.ThreadID != "thread-3" {
+		t.Errorf("ThreadID after Update = %q, want thread-3", entry.ThreadID)
+	}
+
+	// An entry read before a Delete must not overwrite the session created
+	// after it.
+	beforeDelete, _ := store.GetByChatID(c1)
+	if err := store.Delete(c1); err != nil {
+		t.Fatalf("Failed to delete session: %v", err)
+	}
+	if _, err := store.Create(c1, "thread-5"); err != nil {
+		t.Fatalf("Failed to re-create session: %v", err)
+	}
+	if err := store.Update(beforeDelete, "thread-6"); err != ErrConflict {
+		t.Errorf("Update after Delete and Create = %v, want ErrConflict", err)
+	}
+	if err := store.Delete(c1); err != nil {
+		t.Fatalf("Failed to delete session: %v", err)
+	}
+	if entry, _ := store.GetByChatID(c1); entry != nil {
+		t.Errorf("session still present after Delete: %+v", entry)
+	}
+}
+
+func TestPostgresStore_CleanupStale(t *testing.T) {
+	store, chatID := newTestPostgresStore(t, 60)
+	stale, fresh := chatID("stale"), chatID("fresh")
+	store.Create(stale, "thread-old")
+	store.Create(fresh, "thread-new")
+	old := time.Now().Add(-2 * time.Hour).Unix()
+	if _, err := store.db.Exec(`UPDATE sessions SET updated_at = $1 WHERE chat_id = $2`, old, stale); err != nil {
+		t.Fatalf("Failed to age session: %v", err)
+	}
+
+	if _, err := store.CleanupStale(); err != nil {
+		t.Fatalf("CleanupStale: %v", err)
+	}
+	if entry, _ := store.GetByChatID(stale); entry != nil {
+		t.Error("stale session survived CleanupStale")
+	}
+	if entry, _ := store.GetByChatID(fresh); entry == nil {
+		t.Error("fresh session removed by CleanupStale")
+	}
+
+	entries, err := store.ListAll()
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	found := false
+	for _, e := range entries {
+		found = found || e.ChatID == fresh
+	}
+	if !found {
+		t.Error("ListAll is missing the fresh session")
+	}
+}
+
+func TestPostgresStore_ChatSettings(t *testing.T) {
+	store, chatID := newTestPostgresStore(t, 0)
+	c1 := chatID("c1")
+
+	store.SetPaused(c1, true)
+	store.SetPaused(c1, true) // re-pausing must not conflict
+	paused, err := store.PausedChats()
+	if err != nil {
+		t.Fatalf("PausedChats: %v", err)
+	}
+	if !containsString(paused, c1) {
+		t.Errorf("PausedChats = %v, want it to contain %s", paused, c1)
+	}
+	store.SetPaused(c1, false)
+	if paused, _ := store.PausedChats(); containsString(paused, c1) {
+		t.Errorf("%s still paused after resume", c1)
+	}
+
+	store.SetLanguage(c1, "en")
+	store.SetLanguage(c1, "zh")
+	store.SetModel(c1, "gpt-5")
+	store.SetStreamMode(c1, "chunks")
+	store.SetEffort(c1, "high")
+	store.SetApprovalPolicy(c1, "ask")
+	if langs, _ := store.ChatLanguages(); langs[c1] != "zh" {
+		t.Errorf("language = %q, want zh", langs[c1])
+	}
+	if models, _ := store.ChatModels(); models[c1] != "gpt-5" {
+		t.Errorf("model = %q, want gpt-5", models[c1])
+	}
+	if modes, _ := store.ChatStreamModes(); modes[c1] != "chunks" {
+		t.Errorf("stream mode = %q, want chunks", modes[c1])
+	}
+	if efforts, _ := store.ChatEfforts(); efforts[c1] != "high" {
+		t.Errorf("effort = %q, want high", efforts[c1])
+	}
+	if policies, _ := store.ChatApprovalPolicies(); policies[c1] != "ask" {
+		t.Errorf("approval policy = %q, want ask", policies[c1])
+	}
+	store.SetModel(c1, "")
+	if models, _ := store.ChatModels(); models[c1] != "" {
+		t.Errorf("model after clear = %q, want empty", models[c1])
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
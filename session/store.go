@@ -2,6 +2,7 @@ package session
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,24 +17,98 @@ type Entry struct {
 	ThreadID  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// Version changes with the thread (Create and Update, not Touch); Update
+	// uses it to detect that the session changed since it was read. Versions
+	// come from a store-wide counter, so a session deleted and created again
+	// never repeats the version of an entry read before.
+	Version int64
 }
 
-// Store manages session persistence using SQLite
-type Store struct {
-	db          *sql.DB
+// ErrConflict is returned by Update when the session was replaced, updated
+// or deleted after the entry was read. Re-read it and decide again.
+var ErrConflict = errors.New("session changed since it was read")
+
+// Store is the session persistence used by the bridge. SQLiteStore keeps
+// sessions across restarts, PostgresStore also shares them between bridge
+// instances, and MemoryStore keeps them only for the process lifetime.
+type Store interface {
+	GetByChatID(chatID string) (*Entry, error)
+	Create(chatID, threadID string) (*Entry, error)
+	Update(entry *Entry, threadID string) error
+	Touch(chatID string) error
+	Delete(chatID string) error
+	IsFresh(entry *Entry) bool
+	IdleRemaining(entry *Entry) (time.Duration, bool)
+	CleanupStale() (int64, error)
+	ListAll() ([]*Entry, error)
+
+	SetPaused(chatID string, paused bool) error
+	PausedChats() ([]string, error)
+	SetLanguage(chatID, language string) error
+	ChatLanguages() (map[string]string, error)
+	SetModel(chatID, model string) error
+	ChatModels() (map[string]string, error)
+	SetStreamMode(chatID, mode string) error
+	ChatStreamModes() (map[string]string, error)
+	SetEffort(chatID, effort string) error
+	ChatEfforts() (map[string]string, error)
+	SetApprovalPolicy(chatID, policy string) error
+	ChatApprovalPolicies() (map[string]string, error)
+
+	Close() error
+}
+
+var (
+	_ Store = (*SQLiteStore)(nil)
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*PostgresStore)(nil)
+)
+
+// SQLiteStore manages session persistence using SQLite
+type SQLiteStore struct {
+	expiry
+	db *sql.DB
+}
+
+// ResetHourDisabled turns off the daily reset.
+const ResetHourDisabled = -1
+
+// expiry holds the two session expiry rules shared by every Store.
+type expiry struct {
 	idleMinutes int
 	resetHour   int
 }
 
-// NewStore creates a new session store
-func NewStore(dbPath string, idleMinutes, resetHour int) (*Store, error) {
+// newExpiry validates the expiry rules. They are independent: idleMinutes
+// > 0 expires sessions idle that long (0 disables it), and resetHour 0-23
+// expires sessions at that hour every day (ResetHourDisabled disables it).
+func newExpiry(idleMinutes, resetHour int) (expiry, error) {
+	if resetHour < ResetHourDisabled || resetHour > 23 {
+		return expiry{}, fmt.Errorf("invalid reset hour %d: want %d (disabled) or 0-23", resetHour, ResetHourDisabled)
+	}
+	if idleMinutes < 0 {
+		return expiry{}, fmt.Errorf("invalid idle minutes %d: want 0 (disabled) or more", idleMinutes)
+	}
+	return expiry{idleMinutes: idleMinutes, resetHour: resetHour}, nil
+}
+
+// NewStore creates a new SQLite session store at dbPath; see newExpiry for
+// idleMinutes and resetHour.
+func NewStore(dbPath string, idleMinutes, resetHour int) (*SQLiteStore, error) {
+	exp, err := newExpiry(idleMinutes, resetHour)
+	if err != nil {
+		return nil, err
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create db directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	// Concurrent writers wait for the lock instead of failing with
+	// SQLITE_BUSY.
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -44,7 +119,8 @@ func NewStore(dbPath string, idleMinutes, resetHour int) (*Store, error) {
 			chat_id TEXT PRIMARY KEY,
 			thread_id TEXT NOT NULL,
 			created_at INTEGER NOT NULL,
-			updated_at INTEGER NOT NULL
+			updated_at INTEGER NOT NULL,
+			version INTEGER NOT NULL DEFAULT 0
 		)
 	`)
 	if err != nil {
@@ -52,6 +128,32 @@ func NewStore(dbPath string, idleMinutes, resetHour int) (*Store, error) {
 		return nil, fmt.Errorf("failed to create table: %w", err)
 	}
 
+	// Databases created before versioned updates lack the column.
+	var hasVersion int
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('sessions') WHERE name = 'version'`).Scan(&hasVersion)
+	if err == nil && hasVersion == 0 {
+		_, err = db.Exec(`ALTER TABLE sessions ADD COLUMN version INTEGER NOT NULL DEFAULT 0`)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to add version column: %w", err)
+	}
+
+	// session_versions is the store-wide version counter; see
+	// Entry.Version.
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS session_versions (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			last INTEGER NOT NULL
+		);
+		INSERT OR IGNORE INTO session_versions (id, last)
+		SELECT 1, COALESCE(MAX(version), 0) FROM sessions;
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create session_versions table: %w", err)
+	}
+
 	// Create index
 	_, err = db.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_sessions_updated_at ON sessions(updated_at)
@@ -61,29 +163,94 @@ func NewStore(dbPath string, idleMinutes, resetHour int) (*Store, error) {
 		return nil, fmt.Errorf("failed to create index: %w", err)
 	}
 
-	return &Store{
-		db:          db,
-		idleMinutes: idleMinutes,
-		resetHour:   resetHour,
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS paused_chats (
+			chat_id TEXT PRIMARY KEY,
+			paused_at INTEGER NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create paused_chats table: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS chat_languages (
+			chat_id TEXT PRIMARY KEY,
+			language TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create chat_languages table: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS chat_models (
+			chat_id TEXT PRIMARY KEY,
+			model TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create chat_models table: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS chat_stream_modes (
+			chat_id TEXT PRIMARY KEY,
+			stream_mode TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create chat_stream_modes table: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS chat_efforts (
+			chat_id TEXT PRIMARY KEY,
+			effort TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create chat_efforts table: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS chat_approval_policies (
+			chat_id TEXT PRIMARY KEY,
+			policy TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create chat_approval_policies table: %w", err)
+	}
+
+	return &SQLiteStore{
+		expiry: exp,
+		db:     db,
 	}, nil
 }
 
 // Close closes the database connection
-func (s *Store) Close() error {
+func (s *SQLiteStore) Close() error {
 	return s.db.Close()
 }
 
 // GetByChatID retrieves a session by Feishu chat ID
-func (s *Store) GetByChatID(chatID string) (*Entry, error) {
+func (s *SQLiteStore) GetByChatID(chatID string) (*Entry, error) {
 	row := s.db.QueryRow(`
-		SELECT chat_id, thread_id, created_at, updated_at
+		SELECT chat_id, thread_id, created_at, updated_at, version
 		FROM sessions
 		WHERE chat_id = ?
 	`, chatID)
 
 	var entry Entry
 	var createdAt, updatedAt int64
-	err := row.Scan(&entry.ChatID, &entry.ThreadID, &createdAt, &updatedAt)
+	err := row.Scan(&entry.ChatID, &entry.ThreadID, &createdAt, &updatedAt, &entry.Version)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -97,15 +264,32 @@ func (s *Store) GetByChatID(chatID string) (*Entry, error) {
 	return &entry, nil
 }
 
-// Create creates a new session entry
-func (s *Store) Create(chatID, threadID string) (*Entry, error) {
+// Create creates a new session entry, replacing any existing one
+// unconditionally; it bumps the version so older entries no longer Update.
+func (s *SQLiteStore) Create(chatID, threadID string) (*Entry, error) {
 	now := time.Now()
 	nowUnix := now.Unix()
 
-	_, err := s.db.Exec(`
-		INSERT OR REPLACE INTO sessions (chat_id, thread_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?)
-	`, chatID, threadID, nowUnix, nowUnix)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	defer tx.Rollback()
+	version, err := nextVersion(tx)
+	if err == nil {
+		_, err = tx.Exec(`
+			INSERT INTO sessions (chat_id, thread_id, created_at, updated_at, version)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (chat_id) DO UPDATE
+			SET thread_id = excluded.thread_id,
+				created_at = excluded.created_at,
+				updated_at = excluded.updated_at,
+				version = excluded.version
+		`, chatID, threadID, nowUnix, nowUnix, version)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
@@ -115,27 +299,58 @@ func (s *Store) Create(chatID, threadID string) (*Entry, error) {
 		ThreadID:  threadID,
 		CreatedAt: now,
 		UpdatedAt: now,
+		Version:   version,
 	}, nil
 }
 
-// Update updates the thread ID and timestamp for a session
-func (s *Store) Update(chatID, threadID string) error {
-	nowUnix := time.Now().Unix()
+// Update switches the session read as entry to threadID. It returns
+// ErrConflict, changing nothing, if the session's version moved on since
+// entry was read; on success entry is updated to match.
+func (s *SQLiteStore) Update(entry *Entry, threadID string) error {
+	now := time.Now()
 
-	_, err := s.db.Exec(`
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	defer tx.Rollback()
+	version, err := nextVersion(tx)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	result, err := tx.Exec(`
 		UPDATE sessions
-		SET thread_id = ?, updated_at = ?
-		WHERE chat_id = ?
-	`, threadID, nowUnix, chatID)
+		SET thread_id = ?, updated_at = ?, version = ?
+		WHERE chat_id = ? AND version = ?
+	`, threadID, now.Unix(), version, entry.ChatID, entry.Version)
 	if err != nil {
 		return fmt.Errorf("failed to update session: %w", err)
 	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	} else if n == 0 {
+		return ErrConflict
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
 
+	entry.ThreadID = threadID
+	entry.UpdatedAt = now
+	entry.Version = version
 	return nil
 }
 
-// Touch updates the timestamp for a session (to track activity)
-func (s *Store) Touch(chatID string) error {
+// nextVersion takes the next value of the session_versions counter in tx.
+func nextVersion(tx *sql.Tx) (int64, error) {
+	var version int64
+	err := tx.QueryRow(`UPDATE session_versions SET last = last + 1 WHERE id = 1 RETURNING last`).Scan(&version)
+	return version, err
+}
+
+// Touch updates the timestamp for a session (to track activity). It leaves
+// the thread and version alone, so it never invalidates a pending Update.
+func (s *SQLiteStore) Touch(chatID string) error {
 	nowUnix := time.Now().Unix()
 
 	_, err := s.db.Exec(`
@@ -151,7 +366,7 @@ func (s *Store) Touch(chatID string) error {
 }
 
 // Delete removes a session
-func (s *Store) Delete(chatID string) error {
+func (s *SQLiteStore) Delete(chatID string) error {
 	_, err := s.db.Exec(`DELETE FROM sessions WHERE chat_id = ?`, chatID)
 	if err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
@@ -159,46 +374,56 @@ func (s *Store) Delete(chatID string) error {
 	return nil
 }
 
-// IsFresh checks if a session is still fresh (not expired)
-func (s *Store) IsFresh(entry *Entry) bool {
+// IsFresh checks if a session is still fresh: neither idle for longer than
+// the idle timeout nor last used before the most recent daily reset.
+func (e expiry) IsFresh(entry *Entry) bool {
+	return e.isFreshAt(entry, time.Now())
+}
+
+func (e expiry) isFreshAt(entry *Entry, now time.Time) bool {
 	if entry == nil {
 		return false
 	}
+	return !e.idleExpired(entry, now) && !e.pastDailyReset(entry, now)
+}
 
-	now := time.Now()
-
-	// Check idle timeout
-	if s.idleMinutes > 0 {
-		idleTime := now.Sub(entry.UpdatedAt)
-		if idleTime > time.Duration(s.idleMinutes)*time.Minute {
-			return false
-		}
+// idleExpired applies the idle timeout only.
+func (e expiry) idleExpired(entry *Entry, now time.Time) bool {
+	if e.idleMinutes <= 0 {
+		return false
 	}
+	return now.Sub(entry.UpdatedAt) > time.Duration(e.idleMinutes)*time.Minute
+}
 
-	// Check daily reset (if configured)
-	if s.resetHour >= 0 && s.resetHour < 24 {
-		// Get today's reset time
-		resetTime := time.Date(now.Year(), now.Month(), now.Day(), s.resetHour, 0, 0, 0, now.Location())
-
-		// If current time is past reset and session was last updated before reset
-		if now.After(resetTime) && entry.UpdatedAt.Before(resetTime) {
-			return false
-		}
-
-		// If reset time is in the morning and session was from yesterday
-		if now.Before(resetTime) {
-			yesterdayReset := resetTime.Add(-24 * time.Hour)
-			if entry.UpdatedAt.Before(yesterdayReset) {
-				return false
-			}
-		}
+// pastDailyReset applies the daily reset only: it reports whether the most
+// recent reset time before now is after the entry's last use.
+func (e expiry) pastDailyReset(entry *Entry, now time.Time) bool {
+	if e.resetHour == ResetHourDisabled {
+		return false
+	}
+	lastReset := time.Date(now.Year(), now.Month(), now.Day(), e.resetHour, 0, 0, 0, now.Location())
+	if now.Before(lastReset) {
+		lastReset = lastReset.AddDate(0, 0, -1)
 	}
+	return entry.UpdatedAt.Before(lastReset)
+}
 
-	return true
+// IdleRemaining returns how long entry has left before the idle timeout
+// expires it. ok is false when the idle timeout is disabled.
+func (e expiry) IdleRemaining(entry *Entry) (remaining time.Duration, ok bool) {
+	if entry == nil || e.idleMinutes <= 0 {
+		return 0, false
+	}
+	remaining = time.Duration(e.idleMinutes)*time.Minute - time.Since(entry.UpdatedAt)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
 }
 
-// CleanupStale removes all stale sessions
-func (s *Store) CleanupStale() (int64, error) {
+// CleanupStale removes sessions past the idle timeout. It does nothing when
+// the idle timeout is disabled; the daily reset is enforced by IsFresh.
+func (s *SQLiteStore) CleanupStale() (int64, error) {
 	if s.idleMinutes <= 0 {
 		return 0, nil
 	}
@@ -212,10 +437,207 @@ func (s *Store) CleanupStale() (int64, error) {
 	return result.RowsAffected()
 }
 
+// SetPaused records whether message processing is paused for a chat.
+func (s *SQLiteStore) SetPaused(chatID string, paused bool) error {
+	var err error
+	if paused {
+		_, err = s.db.Exec(`
+			INSERT OR REPLACE INTO paused_chats (chat_id, paused_at)
+			VALUES (?, ?)
+		`, chatID, time.Now().Unix())
+	} else {
+		_, err = s.db.Exec(`DELETE FROM paused_chats WHERE chat_id = ?`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set paused state: %w", err)
+	}
+	return nil
+}
+
+// PausedChats returns the IDs of all paused chats.
+func (s *SQLiteStore) PausedChats() ([]string, error) {
+	rows, err := s.db.Query(`SELECT chat_id FROM paused_chats ORDER BY paused_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paused chats: %w", err)
+	}
+	defer rows.Close()
+
+	var chatIDs []string
+	for rows.Next() {
+		var chatID string
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("failed to scan paused chat: %w", err)
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+	return chatIDs, rows.Err()
+}
+
+// SetLanguage records a chat's language; an empty language clears it.
+func (s *SQLiteStore) SetLanguage(chatID, language string) error {
+	var err error
+	if language != "" {
+		_, err = s.db.Exec(`
+			INSERT OR REPLACE INTO chat_languages (chat_id, language)
+			VALUES (?, ?)
+		`, chatID, language)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_languages WHERE chat_id = ?`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat language: %w", err)
+	}
+	return nil
+}
+
+// ChatLanguages returns the language of every chat that has one set.
+func (s *SQLiteStore) ChatLanguages() (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT chat_id, language FROM chat_languages`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat languages: %w", err)
+	}
+	defer rows.Close()
+
+	langs := make(map[string]string)
+	for rows.Next() {
+		var chatID, language string
+		if err := rows.Scan(&chatID, &language); err != nil {
+			return nil, fmt.Errorf("failed to scan chat language: %w", err)
+		}
+		langs[chatID] = language
+	}
+	return langs, rows.Err()
+}
+
+// SetModel records the model a chat uses for new threads; an empty model
+// clears it.
+func (s *SQLiteStore) SetModel(chatID, model string) error {
+	var err error
+	if model != "" {
+		_, err = s.db.Exec(`
+			INSERT OR REPLACE INTO chat_models (chat_id, model)
+			VALUES (?, ?)
+		`, chatID, model)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_models WHERE chat_id = ?`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat model: %w", err)
+	}
+	return nil
+}
+
+// ChatModels returns the model of every chat that has one set.
+func (s *SQLiteStore) ChatModels() (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT chat_id, model FROM chat_models`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat models: %w", err)
+	}
+	defer rows.Close()
+
+	models := make(map[string]string)
+	for rows.Next() {
+		var chatID, model string
+		if err := rows.Scan(&chatID, &model); err != nil {
+			return nil, fmt.Errorf("failed to scan chat model: %w", err)
+		}
+		models[chatID] = model
+	}
+	return models, rows.Err()
+}
+
+// SetStreamMode records the /stream mode of a chat; an empty mode
+// clears it.
+func (s *SQLiteStore) SetStreamMode(chatID, mode string) error {
+	var err error
+	if mode != "" {
+		_, err = s.db.Exec(`
+			INSERT OR REPLACE INTO chat_stream_modes (chat_id, stream_mode)
+			VALUES (?, ?)
+		`, chatID, mode)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_stream_modes WHERE chat_id = ?`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat stream mode: %w", err)
+	}
+	return nil
+}
+
+// ChatStreamModes returns the stream mode of every chat that has one set.
+func (s *SQLiteStore) ChatStreamModes() (map[string]string, error) {
+	return s.chatSettings(`SELECT chat_id, stream_mode FROM chat_stream_modes`, "chat stream mode")
+}
+
+// SetEffort records the reasoning effort of a chat; an empty effort
+// clears it.
+func (s *SQLiteStore) SetEffort(chatID, effort string) error {
+	var err error
+	if effort != "" {
+		_, err = s.db.Exec(`
+			INSERT OR REPLACE INTO chat_efforts (chat_id, effort)
+			VALUES (?, ?)
+		`, chatID, effort)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_efforts WHERE chat_id = ?`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat effort: %w", err)
+	}
+	return nil
+}
+
+// ChatEfforts returns the reasoning effort of every chat that has one set.
+func (s *SQLiteStore) ChatEfforts() (map[string]string, error) {
+	return s.chatSettings(`SELECT chat_id, effort FROM chat_efforts`, "chat effort")
+}
+
+// SetApprovalPolicy records the /approvals policy of a chat; an empty policy
+// clears it.
+func (s *SQLiteStore) SetApprovalPolicy(chatID, policy string) error {
+	var err error
+	if policy != "" {
+		_, err = s.db.Exec(`
+			INSERT OR REPLACE INTO chat_approval_policies (chat_id, policy)
+			VALUES (?, ?)
+		`, chatID, policy)
+	} else {
+		_, err = s.db.Exec(`DELETE FROM chat_approval_policies WHERE chat_id = ?`, chatID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set chat approval policy: %w", err)
+	}
+	return nil
+}
+
+// ChatApprovalPolicies returns the approval policy of every chat that has one set.
+func (s *SQLiteStore) ChatApprovalPolicies() (map[string]string, error) {
+	return s.chatSettings(`SELECT chat_id, policy FROM chat_approval_policies`, "chat approval policy")
+}
+
+// chatSettings reads a two-column chat_id -> value table.
+func (s *SQLiteStore) chatSettings(query, what string) (map[string]string, error) {
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %ss: %w", what, err)
+	}
+	defer rows.Close()
+
+	settings := make(map[string]string)
+	for rows.Next() {
+		var chatID, value string
+		if err := rows.Scan(&chatID, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", what, err)
+		}
+		settings[chatID] = value
+	}
+	return settings, rows.Err()
+}
+
 // ListAll returns all sessions (for debugging)
-func (s *Store) ListAll() ([]*Entry, error) {
+func (s *SQLiteStore) ListAll() ([]*Entry, error) {
 	rows, err := s.db.Query(`
-		SELECT chat_id, thread_id, created_at, updated_at
+		SELECT chat_id, thread_id, created_at, updated_at, version
 		FROM sessions
 		ORDER BY updated_at DESC
 	`)
@@ -228,7 +650,7 @@ func (s *Store) ListAll() ([]*Entry, error) {
 	for rows.Next() {
 		var entry Entry
 		var createdAt, updatedAt int64
-		if err := rows.Scan(&entry.ChatID, &entry.ThreadID, &createdAt, &updatedAt); err != nil {
+		if err := rows.Scan(&entry.ChatID, &entry.ThreadID, &createdAt, &updatedAt, &entry.Version); err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
 		}
 		entry.CreatedAt = time.Unix(createdAt, 0)
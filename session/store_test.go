@@ -1,8 +1,11 @@
 package session
 
 import (
+	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -94,17 +97,145 @@ func TestUpdateSession(t *testing.T) {
 
 	chatID := "oc_test123"
 	store.Create(chatID, "thread-1")
+	entry, _ := store.GetByChatID(chatID)
 
 	// Update to new thread
-	err = store.Update(chatID, "thread-2")
+	err = store.Update(entry, "thread-2")
 	if err != nil {
 		t.Fatalf("Failed to update session: %v", err)
 	}
+	if entry.ThreadID != "thread-2" {
+		t.Errorf("entry not updated in place: got %v, want thread-2", entry.ThreadID)
+	}
 
 	retrieved, _ := store.GetByChatID(chatID)
 	if retrieved.ThreadID != "thread-2" {
 		t.Errorf("ThreadID not updated: got %v, want thread-2", retrieved.ThreadID)
 	}
+	if retrieved.Version != entry.Version {
+		t.Errorf("Version = %d, want %d", retrieved.Version, entry.Version)
+	}
+}
+
+func TestUpdateSession_Conflict(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"), 60, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	store.Create("oc_1", "thread-1")
+	stale, _ := store.GetByChatID("oc_1")
+
+	// Touch does not invalidate the read...
+	store.Touch("oc_1")
+	fresh, _ := store.GetByChatID("oc_1")
+	// ...but replacing the thread does.
+	store.Create("oc_1", "thread-2")
+	if err := store.Update(stale, "thread-3"); err != ErrConflict {
+		t.Fatalf("Update after Create = %v, want ErrConflict", err)
+	}
+	if err := store.Update(fresh, "thread-3"); err != ErrConflict {
+		t.Fatalf("Update after Create = %v, want ErrConflict", err)
+	}
+	if entry, _ := store.GetByChatID("oc_1"); entry.ThreadID != "thread-2" {
+		t.Errorf("ThreadID = %s, want thread-2 kept", entry.ThreadID)
+	}
+
+	store.Delete("oc_1")
+	if err := store.Update(&Entry{ChatID: "oc_1"}, "thread-4"); err != ErrConflict {
+		t.Errorf("Update of a deleted session = %v, want ErrConflict", err)
+	}
+
+	// An entry read before a Delete must not overwrite the session created
+	// after it.
+	store.Create("oc_2", "thread-1")
+	beforeDelete, _ := store.GetByChatID("oc_2")
+	store.Delete("oc_2")
+	store.Create("oc_2", "thread-2")
+	if err := store.Update(beforeDelete, "thread-3"); err != ErrConflict {
+		t.Errorf("Update after Delete and Create = %v, want ErrConflict", err)
+	}
+	if entry, _ := store.GetByChatID("oc_2"); entry.ThreadID != "thread-2" {
+		t.Errorf("ThreadID = %s, want thread-2 kept", entry.ThreadID)
+	}
+}
+
+func TestUpdateSession_ConcurrentWriters(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"), 60, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	store.Create("oc_1", "thread-0")
+	read, _ := store.GetByChatID("oc_1")
+
+	// Every writer read the same version; exactly one may win.
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			entry := *read
+			errs <- store.Update(&entry, fmt.Sprintf("thread-%d", i+1))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	won := 0
+	for err := range errs {
+		switch err {
+		case nil:
+			won++
+		case ErrConflict:
+		default:
+			t.Fatalf("Update: %v", err)
+		}
+	}
+	if won != 1 {
+		t.Fatalf("%d writers succeeded, want 1", won)
+	}
+	if entry, _ := store.GetByChatID("oc_1"); entry.Version != read.Version+1 {
+		t.Errorf("Version = %d, want %d", entry.Version, read.Version+1)
+	}
+}
+
+func TestNewStore_AddsVersionColumn(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE sessions (
+			chat_id TEXT PRIMARY KEY,
+			thread_id TEXT NOT NULL,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		);
+		INSERT INTO sessions VALUES ('oc_1', 'thread-1', 1, 1);
+	`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+
+	store, err := NewStore(dbPath, 0, -1)
+	if err != nil {
+		t.Fatalf("Failed to open old database: %v", err)
+	}
+	defer store.Close()
+	entry, err := store.GetByChatID("oc_1")
+	if err != nil || entry == nil || entry.Version != 0 {
+		t.Fatalf("GetByChatID = %+v, %v; want version 0", entry, err)
+	}
+	if err := store.Update(entry, "thread-2"); err != nil {
+		t.Errorf("Update on migrated database: %v", err)
+	}
 }
 
 func TestTouchSession(t *testing.T) {
@@ -208,6 +339,40 @@ func TestIsFresh_NilEntry(t *testing.T) {
 	}
 }
 
+func TestIdleRemaining(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	store, err := NewStore(dbPath, 60, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	entry := &Entry{ChatID: "test", ThreadID: "thread", UpdatedAt: time.Now().Add(-50 * time.Minute)}
+	remaining, ok := store.IdleRemaining(entry)
+	if !ok {
+		t.Fatal("Expected idle timeout to be enabled")
+	}
+	if remaining <= 9*time.Minute || remaining > 10*time.Minute {
+		t.Errorf("Expected ~10m remaining, got %v", remaining)
+	}
+
+	entry.UpdatedAt = time.Now().Add(-2 * time.Hour)
+	if remaining, _ := store.IdleRemaining(entry); remaining != 0 {
+		t.Errorf("Expected 0 remaining for expired entry, got %v", remaining)
+	}
+
+	disabled, err := NewStore(filepath.Join(tmpDir, "disabled.db"), 0, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer disabled.Close()
+	if _, ok := disabled.IdleRemaining(entry); ok {
+		t.Error("Expected ok=false when idle timeout is disabled")
+	}
+}
+
 func TestCleanupStale(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -343,6 +508,152 @@ func TestCleanupStale_Disabled(t *testing.T) {
 	}
 }
 
+func TestPausedChats(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	store, err := NewStore(dbPath, 1, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if err := store.SetPaused("chat1", true); err != nil {
+		t.Fatalf("SetPaused failed: %v", err)
+	}
+	if err := store.SetPaused("chat2", true); err != nil {
+		t.Fatalf("SetPaused failed: %v", err)
+	}
+	if err := store.SetPaused("chat2", false); err != nil {
+		t.Fatalf("SetPaused failed: %v", err)
+	}
+	store.Close()
+
+	// Paused state survives reopening and is unaffected by session cleanup.
+	store, err = NewStore(dbPath, 1, -1)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer store.Close()
+	if _, err := store.CleanupStale(); err != nil {
+		t.Fatalf("CleanupStale failed: %v", err)
+	}
+
+	paused, err := store.PausedChats()
+	if err != nil {
+		t.Fatalf("PausedChats failed: %v", err)
+	}
+	if len(paused) != 1 || paused[0] != "chat1" {
+		t.Errorf("Expected [chat1], got %v", paused)
+	}
+}
+
+func TestChatLanguages(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	store, err := NewStore(dbPath, 1, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if err := store.SetLanguage("chat1", "en"); err != nil {
+		t.Fatalf("SetLanguage failed: %v", err)
+	}
+	if err := store.SetLanguage("chat2", "en"); err != nil {
+		t.Fatalf("SetLanguage failed: %v", err)
+	}
+	if err := store.SetLanguage("chat2", ""); err != nil {
+		t.Fatalf("SetLanguage failed: %v", err)
+	}
+	store.Close()
+
+	store, err = NewStore(dbPath, 1, -1)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer store.Close()
+
+	langs, err := store.ChatLanguages()
+	if err != nil {
+		t.Fatalf("ChatLanguages failed: %v", err)
+	}
+	if len(langs) != 1 || langs["chat1"] != "en" {
+		t.Errorf("Expected map[chat1:en], got %v", langs)
+	}
+}
+
+func TestChatModels(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	store, err := NewStore(dbPath, 1, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if err := store.SetModel("chat1", "gpt-5.2-codex"); err != nil {
+		t.Fatalf("SetModel failed: %v", err)
+	}
+	if err := store.SetModel("chat2", "o3"); err != nil {
+		t.Fatalf("SetModel failed: %v", err)
+	}
+	if err := store.SetModel("chat2", ""); err != nil {
+		t.Fatalf("SetModel failed: %v", err)
+	}
+	// Clearing the session must not drop the chat's model.
+	if _, err := store.Create("chat1", "thread1"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := store.Delete("chat1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	store.Close()
+
+	store, err = NewStore(dbPath, 1, -1)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer store.Close()
+
+	models, err := store.ChatModels()
+	if err != nil {
+		t.Fatalf("ChatModels failed: %v", err)
+	}
+	if len(models) != 1 || models["chat1"] != "gpt-5.2-codex" {
+		t.Errorf("Expected map[chat1:gpt-5.2-codex], got %v", models)
+	}
+}
+
+func TestChatStreamModesEffortsAndApprovalPolicies(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store, err := NewStore(dbPath, 1, -1)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	store.SetStreamMode("chat1", "chunks")
+	store.SetStreamMode("chat2", "stream")
+	store.SetStreamMode("chat2", "")
+	store.SetEffort("chat1", "high")
+	store.SetApprovalPolicy("chat2", "ask")
+	store.Close()
+
+	store, err = NewStore(dbPath, 1, -1)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer store.Close()
+
+	if modes, err := store.ChatStreamModes(); err != nil || len(modes) != 1 || modes["chat1"] != "chunks" {
+		t.Errorf("ChatStreamModes = %v, %v; want map[chat1:chunks]", modes, err)
+	}
+	if efforts, err := store.ChatEfforts(); err != nil || len(efforts) != 1 || efforts["chat1"] != "high" {
+		t.Errorf("ChatEfforts = %v, %v; want map[chat1:high]", efforts, err)
+	}
+	if policies, err := store.ChatApprovalPolicies(); err != nil || len(policies) != 1 || policies["chat2"] != "ask" {
+		t.Errorf("ChatApprovalPolicies = %v, %v; want map[chat2:ask]", policies, err)
+	}
+}
+
 func TestNewStore_InvalidPath(t *testing.T) {
 	// Try to create store in non-existent nested directory
 	// This should succeed because NewStore creates the directory
@@ -378,3 +689,80 @@ func TestEntry_Fields(t *testing.T) {
 		t.Error("UpdatedAt mismatch")
 	}
 }
+
+func TestIsFresh_IdleAndResetIndependent(t *testing.T) {
+	now := time.Date(2026, 3, 10, 4, 10, 0, 0, time.Local)
+	// Used 20 minutes ago, before today's 04:00 reset.
+	beforeReset := &Entry{ChatID: "c", ThreadID: "t", UpdatedAt: now.Add(-20 * time.Minute)}
+	// Used 5 minutes ago, after the reset.
+	recent := &Entry{ChatID: "c", ThreadID: "t", UpdatedAt: now.Add(-5 * time.Minute)}
+	// Used 3 hours ago: past a 1 minute idle timeout and before the reset.
+	old := &Entry{ChatID: "c", ThreadID: "t", UpdatedAt: now.Add(-3 * time.Hour)}
+
+	tests := []struct {
+		name        string
+		idleMinutes int
+		resetHour   int
+		fresh       map[*Entry]bool
+	}{
+		{"both on", 30, 4, map[*Entry]bool{beforeReset: false, recent: true, old: false}},
+		{"idle only", 30, ResetHourDisabled, map[*Entry]bool{beforeReset: true, recent: true, old: false}},
+		{"reset only", 0, 4, map[*Entry]bool{beforeReset: false, recent: true, old: false}},
+		{"both off", 0, ResetHourDisabled, map[*Entry]bool{beforeReset: true, recent: true, old: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewStore(filepath.Join(t.TempDir(), "test.db"), tt.idleMinutes, tt.resetHour)
+			if err != nil {
+				t.Fatalf("Failed to create store: %v", err)
+			}
+			defer store.Close()
+			for entry, want := range tt.fresh {
+				if got := store.isFreshAt(entry, now); got != want {
+					t.Errorf("entry updated %v before now: fresh = %v, want %v", now.Sub(entry.UpdatedAt), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCleanupStale_IgnoresResetHour(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		idleMinutes int
+		resetHour   int
+		want        int64
+	}{
+		{"both on", 1, 4, 1},
+		{"idle only", 1, ResetHourDisabled, 1},
+		{"reset only", 0, 4, 0},
+		{"both off", 0, ResetHourDisabled, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewStore(filepath.Join(t.TempDir(), "test.db"), tt.idleMinutes, tt.resetHour)
+			if err != nil {
+				t.Fatalf("Failed to create store: %v", err)
+			}
+			defer store.Close()
+			store.Create("chat1", "thread1")
+			if _, err := store.db.Exec(`UPDATE sessions SET updated_at = ?`, time.Now().Add(-time.Hour).Unix()); err != nil {
+				t.Fatal(err)
+			}
+			count, err := store.CleanupStale()
+			if err != nil {
+				t.Fatalf("CleanupStale failed: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("cleaned up %d, want %d", count, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStore_InvalidExpiry(t *testing.T) {
+	for _, tc := range []struct{ idle, reset int }{{60, 24}, {60, -2}, {-1, 4}} {
+		if _, err := NewStore(filepath.Join(t.TempDir(), "test.db"), tc.idle, tc.reset); err == nil {
+			t.Errorf("NewStore(idle=%d, reset=%d) should fail", tc.idle, tc.reset)
+		}
+	}
+}